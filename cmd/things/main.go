@@ -9,12 +9,18 @@ import (
 
 func main() {
 	app := cli.NewApp()
+	args, err := cli.ExpandAliases(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(app.Err, cli.FormatError(err))
+		os.Exit(1)
+	}
 	root := cli.NewRoot(app)
+	root.SetArgs(args)
 	if err := root.Execute(); err != nil {
 		if err == cli.ErrVersionPrinted {
 			return
 		}
-		if err == cli.ErrHelpPrinted {
+		if err == cli.ErrHelpPrinted || err == cli.ErrExplainPrinted {
 			return
 		}
 		fmt.Fprintln(app.Err, cli.FormatError(err))
@@ -66,7 +66,9 @@ func runThings(t *testing.T, stdin string, args ...string) (string, string, int)
 	var outBuf, errBuf bytes.Buffer
 	cmd.Stdout = &outBuf
 	cmd.Stderr = &errBuf
-	cmd.Env = append(os.Environ(), "OPEN=echo", "OSASCRIPT=echo")
+	// Keep the action log out of the real config directory.
+	config := t.TempDir()
+	cmd.Env = append(os.Environ(), "OPEN=echo", "OSASCRIPT=echo", "HOME="+config, "XDG_CONFIG_HOME="+config)
 
 	err := cmd.Run()
 	code := 0
@@ -4,51 +4,89 @@ import (
 	"bufio"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ossianhempel/things3-cli/internal/config"
 	"github.com/ossianhempel/things3-cli/internal/db"
 )
 
 type ActionType string
 
 const (
-	ActionUpdate ActionType = "update"
-	ActionTrash  ActionType = "trash"
+	ActionUpdate        ActionType = "update"
+	ActionTrash         ActionType = "trash"
+	ActionRestore       ActionType = "restore"
+	ActionStatus        ActionType = "status"
+	ActionAdd           ActionType = "add"
+	ActionAddProject    ActionType = "add-project"
+	ActionUpdateProject ActionType = "update-project"
+	ActionAddArea       ActionType = "add-area"
+	ActionUpdateArea    ActionType = "update-area"
+	ActionDeleteArea    ActionType = "delete-area"
+	ActionRepeat        ActionType = "repeat"
 )
 
+// ActionEntry is one logged action. Entries are never removed; undo and redo
+// toggle Undone so that IDs stay stable.
 type ActionEntry struct {
+	ID        string       `json:"id,omitempty"`
 	Timestamp string       `json:"timestamp"`
 	Type      ActionType   `json:"type"`
+	Undone    bool         `json:"undone,omitempty"`
 	Items     []ActionItem `json:"items"`
+	Redo      []ActionStep `json:"redo,omitempty"`
 }
 
+// ActionItem snapshots an item before the action ran. Expected, when set, is
+// the state the action should have produced; undo compares it with the
+// database to detect later edits.
 type ActionItem struct {
-	UUID         string   `json:"uuid"`
-	Title        string   `json:"title"`
-	Status       int      `json:"status"`
-	Notes        string   `json:"notes,omitempty"`
-	Tags         []string `json:"tags,omitempty"`
-	Deadline     string   `json:"deadline,omitempty"`
-	Start        string   `json:"start,omitempty"`
-	StartDate    string   `json:"start_date,omitempty"`
-	ProjectID    string   `json:"project_id,omitempty"`
-	AreaID       string   `json:"area_id,omitempty"`
-	HeadingTitle string   `json:"heading_title,omitempty"`
+	UUID         string           `json:"uuid"`
+	Title        string           `json:"title"`
+	Status       int              `json:"status"`
+	Notes        string           `json:"notes,omitempty"`
+	Tags         []string         `json:"tags,omitempty"`
+	TagsKnown    bool             `json:"tags_known,omitempty"` // Tags were read from the database; empty means none
+	Deadline     string           `json:"deadline,omitempty"`
+	Start        string           `json:"start,omitempty"`
+	StartDate    string           `json:"start_date,omitempty"`
+	ProjectID    string           `json:"project_id,omitempty"`
+	AreaID       string           `json:"area_id,omitempty"`
+	HeadingTitle string           `json:"heading_title,omitempty"`
+	Repeat       *db.RepeatUpdate `json:"repeat,omitempty"`
+	Expected     *PlanState       `json:"expected,omitempty"`
+}
+
+// ActionStep replays an action for redo. URLs are stored with the auth token
+// redacted; it is filled in again when the step runs.
+type ActionStep struct {
+	URL         string           `json:"url,omitempty"`
+	Script      string           `json:"script,omitempty"`
+	RepeatID    string           `json:"repeat_id,omitempty"`
+	Repeat      *db.RepeatUpdate `json:"repeat,omitempty"`
+	RepeatClear bool             `json:"repeat_clear,omitempty"`
 }
 
 func actionLogPath() (string, error) {
-	dir, err := os.UserConfigDir()
+	return configFilePath("actions.jsonl")
+}
+
+// configFilePath returns name inside the config directory, creating the
+// directory if needed.
+func configFilePath(name string) (string, error) {
+	dir, err := config.Dir()
 	if err != nil {
 		return "", err
 	}
-	path := filepath.Join(dir, "things3-cli")
-	if err := os.MkdirAll(path, 0o755); err != nil {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return "", err
 	}
-	return filepath.Join(path, "actions.jsonl"), nil
+	return filepath.Join(dir, name), nil
 }
 
 func appendAction(entry ActionEntry) error {
@@ -56,6 +94,11 @@ func appendAction(entry ActionEntry) error {
 	if err != nil {
 		return err
 	}
+	entries, err := readActions()
+	if err != nil {
+		return err
+	}
+	entry.ID = nextActionID(entries)
 	entry.Timestamp = time.Now().Format(time.RFC3339)
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
 	if err != nil {
@@ -66,62 +109,144 @@ func appendAction(entry ActionEntry) error {
 	return enc.Encode(entry)
 }
 
-func readLastAction() (ActionEntry, error) {
+// readActions returns all logged actions, oldest first. Entries written before
+// IDs were introduced are numbered by their position in the log.
+func readActions() ([]ActionEntry, error) {
 	path, err := actionLogPath()
 	if err != nil {
-		return ActionEntry{}, err
+		return nil, err
 	}
 	file, err := os.Open(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return ActionEntry{}, errors.New("no actions logged")
+			return nil, nil
 		}
-		return ActionEntry{}, err
+		return nil, err
 	}
 	defer file.Close()
 
-	var lastLine string
+	entries := []ActionEntry{}
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			lastLine = line
+		if line == "" {
+			continue
+		}
+		var entry ActionEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, err
 		}
+		if entry.ID == "" {
+			entry.ID = strconv.Itoa(len(entries) + 1)
+		}
+		entries = append(entries, entry)
 	}
 	if err := scanner.Err(); err != nil {
-		return ActionEntry{}, err
+		return nil, err
+	}
+	return entries, nil
+}
+
+func writeActions(entries []ActionEntry) error {
+	path, err := actionLogPath()
+	if err != nil {
+		return err
 	}
-	if lastLine == "" {
-		return ActionEntry{}, errors.New("no actions logged")
+	var b strings.Builder
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
 	}
-	var entry ActionEntry
-	if err := json.Unmarshal([]byte(lastLine), &entry); err != nil {
-		return ActionEntry{}, err
+	return os.WriteFile(path, []byte(b.String()), 0o600)
+}
+
+func nextActionID(entries []ActionEntry) string {
+	max := 0
+	for _, entry := range entries {
+		if n, err := strconv.Atoi(entry.ID); err == nil && n > max {
+			max = n
+		}
 	}
-	return entry, nil
+	return strconv.Itoa(max + 1)
 }
 
-func removeLastAction() error {
-	path, err := actionLogPath()
+// findAction picks the entry to undo or redo. With an empty id it returns the
+// most recent entry whose Undone flag equals undone.
+func findAction(entries []ActionEntry, id string, undone bool) (int, error) {
+	if len(entries) == 0 {
+		return -1, errors.New("no actions logged")
+	}
+	if strings.TrimSpace(id) != "" {
+		for i, entry := range entries {
+			if entry.ID == strings.TrimSpace(id) {
+				return i, nil
+			}
+		}
+		return -1, errors.New("no action with id " + id)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Undone == undone {
+			return i, nil
+		}
+	}
+	if undone {
+		return -1, errors.New("nothing to redo")
+	}
+	return -1, errors.New("nothing to undo")
+}
+
+func setActionUndone(id string, undone bool) error {
+	entries, err := readActions()
 	if err != nil {
 		return err
 	}
-	data, err := os.ReadFile(path)
+	for i := range entries {
+		if entries[i].ID == id {
+			entries[i].Undone = undone
+		}
+	}
+	return writeActions(entries)
+}
+
+// setActionItems replaces the logged items of the action id.
+func setActionItems(id string, items []ActionItem) error {
+	entries, err := readActions()
 	if err != nil {
 		return err
 	}
-	lines := strings.Split(string(data), "\n")
-	for i := len(lines) - 1; i >= 0; i-- {
-		if strings.TrimSpace(lines[i]) != "" {
-			lines = append(lines[:i], lines[i+1:]...)
-			break
+	for i := range entries {
+		if entries[i].ID == id {
+			entries[i].Items = items
 		}
 	}
-	content := strings.Join(lines, "\n")
-	return os.WriteFile(path, []byte(content), 0o600)
+	return writeActions(entries)
+}
+
+// logAction appends entry to the action log, warning instead of failing.
+func logAction(app *App, entry ActionEntry) {
+	if app.DryRun {
+		return
+	}
+	if err := appendAction(entry); err != nil {
+		fmt.Fprintf(app.Err, "Warning: failed to write action log: %v\n", err)
+	}
+}
+
+func urlStep(url string) ActionStep {
+	return ActionStep{URL: redactAuthToken(url)}
 }
 
 func taskToActionItem(task db.Task) ActionItem {
+	// Todos under a heading have no project of their own.
+	projectID := task.ProjectID
+	if projectID == "" {
+		projectID = task.HeadingProjectID
+	}
 	return ActionItem{
 		UUID:         task.UUID,
 		Title:        task.Title,
@@ -131,7 +256,7 @@ func taskToActionItem(task db.Task) ActionItem {
 		Deadline:     task.Deadline,
 		Start:        task.Start,
 		StartDate:    task.StartDate,
-		ProjectID:    task.ProjectID,
+		ProjectID:    projectID,
 		AreaID:       task.AreaID,
 		HeadingTitle: task.HeadingTitle,
 	}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
@@ -20,7 +21,7 @@ func NewAddCommand(app *App) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "add [OPTIONS...] [--] [-|TITLE]",
 		Short: "Add a new todo",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			rawInput, err := readInput(app.In, args)
 			if err != nil {
 				return err
@@ -55,7 +56,13 @@ func NewAddCommand(app *App) *cobra.Command {
 
 			url := things.BuildAddURL(opts, rawInput)
 			if !repeatSpec.Enabled {
-				return openURL(app, url)
+				if err := openURL(app, url); err != nil {
+					return err
+				}
+				if entry, ok := addActionEntry(ActionAdd, opts.TitlesRaw, title, url); ok {
+					logAction(app, entry)
+				}
+				return nil
 			}
 			if app.DryRun {
 				if err := openURL(app, url); err != nil {
@@ -74,7 +81,7 @@ func NewAddCommand(app *App) *cobra.Command {
 			if err != nil {
 				return formatDBError(err)
 			}
-			defer store.Close()
+			defer closeWriteStore(app, store, &err)
 
 			taskID, err := waitForCreatedItem(store, title, db.TaskTypeTodo, started)
 			if err != nil {
@@ -87,6 +94,11 @@ func NewAddCommand(app *App) *cobra.Command {
 			if err := store.ApplyRepeatRule(taskID, update); err != nil {
 				return formatDBError(err)
 			}
+			logAction(app, ActionEntry{
+				Type:  ActionAdd,
+				Items: []ActionItem{{UUID: taskID, Title: title}},
+				Redo:  []ActionStep{urlStep(url)},
+			})
 			return nil
 		},
 	}
@@ -116,3 +128,30 @@ func NewAddCommand(app *App) *cobra.Command {
 
 	return cmd
 }
+
+// addActionEntry logs items created through the add URL. Quick entry adds are
+// not logged since the title is unknown.
+func addActionEntry(actionType ActionType, titlesRaw string, title string, url string) (ActionEntry, bool) {
+	titles := []string{}
+	if strings.TrimSpace(titlesRaw) != "" {
+		for _, t := range strings.Split(titlesRaw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				titles = append(titles, t)
+			}
+		}
+	} else if title != "" {
+		titles = append(titles, title)
+	}
+	if len(titles) == 0 {
+		return ActionEntry{}, false
+	}
+	entry := ActionEntry{
+		Type:  actionType,
+		Items: make([]ActionItem, 0, len(titles)),
+		Redo:  []ActionStep{urlStep(url)},
+	}
+	for _, t := range titles {
+		entry.Items = append(entry.Items, ActionItem{Title: t})
+	}
+	return entry, true
+}
@@ -1,13 +1,23 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
 	"github.com/ossianhempel/things3-cli/internal/things"
 	"github.com/spf13/cobra"
 )
 
+// createdAreaTimeout bounds how long add-area waits for Things to write the
+// new area to its database.
+var createdAreaTimeout = 5 * time.Second
+
 // NewAddAreaCommand builds the add-area subcommand.
 func NewAddAreaCommand(app *App) *cobra.Command {
 	opts := things.AddAreaOptions{}
+	var dbPath string
 	var allowUnsafeTitle bool
 
 	cmd := &cobra.Command{
@@ -28,13 +38,81 @@ func NewAddAreaCommand(app *App) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return runScript(app, script)
+			var store *db.Store
+			var before map[string]bool
+			if !app.DryRun {
+				if opened, _, err := db.OpenDefault(dbPath); err == nil {
+					defer opened.Close()
+					if before, err = areaIDsTitled(opened, title); err == nil {
+						store = opened
+					}
+				}
+			}
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+			item := ActionItem{Title: title}
+			if store != nil {
+				item.UUID = findCreatedArea(store, title, before)
+			}
+			if item.UUID == "" && !app.DryRun {
+				fmt.Fprintf(app.Err, "Warning: could not find the new area %q in the database; undo will not delete it.\n", title)
+			}
+			logAction(app, ActionEntry{
+				Type:  ActionAddArea,
+				Items: []ActionItem{item},
+				Redo:  []ActionStep{{Script: script}},
+			})
+			return nil
 		},
 	}
 
 	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
 	flags.StringVar(&opts.Tags, "tags", "", "Comma-separated tags")
 	flags.BoolVar(&allowUnsafeTitle, "allow-unsafe-title", false, "Allow titles that look like flag assignments")
 
 	return cmd
 }
+
+// areaIDsTitled returns the IDs of the areas titled title.
+func areaIDsTitled(store *db.Store, title string) (map[string]bool, error) {
+	areas, err := store.Areas()
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, area := range areas {
+		if strings.EqualFold(area.Title, title) {
+			ids[area.UUID] = true
+		}
+	}
+	return ids, nil
+}
+
+// findCreatedArea waits for the area created as title to show up and returns
+// its ID. Areas have no creation date, so the new area is the one titled
+// title that is not in before; "" means none or several appeared.
+func findCreatedArea(store *db.Store, title string, before map[string]bool) string {
+	deadline := time.Now().Add(createdAreaTimeout)
+	for {
+		ids, err := areaIDsTitled(store, title)
+		if err != nil {
+			return ""
+		}
+		created := []string{}
+		for id := range ids {
+			if !before[id] {
+				created = append(created, id)
+			}
+		}
+		if len(created) == 1 {
+			return created[0]
+		}
+		if len(created) > 1 || !time.Now().Before(deadline) {
+			return ""
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
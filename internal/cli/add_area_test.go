@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"database/sql"
 	"strings"
 	"testing"
 )
@@ -80,3 +81,47 @@ func TestCreateAreaAlias(t *testing.T) {
 		t.Fatalf("expected name in script, got %q", script)
 	}
 }
+
+// createAreaRunner records the script and inserts the area Things would
+// create into the test database.
+type createAreaRunner struct {
+	dbPath string
+	uuid   string
+	title  string
+	script string
+}
+
+func (r *createAreaRunner) Run(script string) error {
+	r.script = script
+	conn, err := sql.Open("sqlite", r.dbPath)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Exec(`INSERT INTO TMArea (uuid, title, visible, "index") VALUES (?, ?, 1, 2)`, r.uuid, r.title)
+	return err
+}
+
+func TestAddAreaLogsCreatedAreaID(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	// The fixture already has an area titled "Home" (A1).
+	runner := &createAreaRunner{dbPath: dbPath, uuid: "A2", title: "Home"}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"add-area", "--db", dbPath, "Home"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	entries, err := readActions()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one logged action, got %v (%v)", entries, err)
+	}
+	if item := entries[0].Items[0]; item.UUID != "A2" || item.Title != "Home" {
+		t.Fatalf("expected the new area logged, got %+v", item)
+	}
+}
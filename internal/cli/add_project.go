@@ -1,6 +1,11 @@
 package cli
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/repeat"
 	"github.com/ossianhempel/things3-cli/internal/things"
 	"github.com/spf13/cobra"
 )
@@ -8,17 +13,23 @@ import (
 // NewAddProjectCommand builds the add-project subcommand.
 func NewAddProjectCommand(app *App) *cobra.Command {
 	opts := things.AddProjectOptions{}
+	repeatOpts := RepeatOptions{}
+	var dbPath string
 	var allowUnsafeTitle bool
 
 	cmd := &cobra.Command{
 		Use:     "add-project [OPTIONS...] [-|TITLE]",
 		Aliases: []string{"create-project"},
 		Short:   "Add a new project",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			rawInput, err := readInput(app.In, args)
 			if err != nil {
 				return err
 			}
+			repeatSpec, err := parseRepeatSpec(cmd, repeatOpts)
+			if err != nil {
+				return err
+			}
 			title := extractTitle(rawInput, "")
 			if err := guardUnsafeTitle(title, allowUnsafeTitle); err != nil {
 				return err
@@ -26,13 +37,70 @@ func NewAddProjectCommand(app *App) *cobra.Command {
 			if err := validateWhenInput(opts.When); err != nil {
 				return err
 			}
+			if repeatSpec.Enabled {
+				if repeatSpec.Clear {
+					return fmt.Errorf("Error: --repeat-clear is only valid with update commands")
+				}
+				if title == "" {
+					return fmt.Errorf("Error: repeating add-project requires an explicit title")
+				}
+			}
 
 			url := things.BuildAddProjectURL(opts, rawInput)
-			return openURL(app, url)
+			if !repeatSpec.Enabled {
+				if err := openURL(app, url); err != nil {
+					return err
+				}
+				if entry, ok := addActionEntry(ActionAddProject, "", title, url); ok {
+					logAction(app, entry)
+				}
+				return nil
+			}
+			if app.DryRun {
+				if err := openURL(app, url); err != nil {
+					return err
+				}
+				fmt.Fprintln(app.Err, "Note: --repeat is skipped in --dry-run mode.")
+				return nil
+			}
+
+			ensureThingsLaunched(app)
+			started := time.Now().Add(-2 * time.Second)
+			if err := openURL(app, url); err != nil {
+				return err
+			}
+			store, _, err := db.OpenDefaultWritable(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer closeWriteStore(app, store, &err)
+
+			projectID, err := waitForCreatedItem(store, title, db.TaskTypeProject, started)
+			if err != nil {
+				return formatDBError(err)
+			}
+			if err := checkProjectTemplate(store, projectID); err != nil {
+				return err
+			}
+			update, err := repeat.BuildUpdate(repeatSpec.Spec)
+			if err != nil {
+				return err
+			}
+			if err := store.ApplyRepeatRule(projectID, update); err != nil {
+				return formatDBError(err)
+			}
+			logAction(app, ActionEntry{
+				Type:  ActionAddProject,
+				Items: []ActionItem{{UUID: projectID, Title: title}},
+				Redo:  []ActionStep{urlStep(url)},
+			})
+			return nil
 		},
 	}
 
 	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
 	flags.StringVar(&opts.AreaID, "area-id", "", "Area ID to add to")
 	flags.StringVar(&opts.Area, "area", "", "Area to add to")
 	flags.BoolVar(&opts.Canceled, "canceled", false, "Mark the project canceled")
@@ -47,6 +115,7 @@ func NewAddProjectCommand(app *App) *cobra.Command {
 	flags.StringVar(&opts.When, "when", "", "When to schedule the project")
 	flags.StringArrayVar(&opts.Todos, "todo", nil, "Todo title to add (repeatable)")
 	flags.BoolVar(&allowUnsafeTitle, "allow-unsafe-title", false, "Allow titles that look like flag assignments")
+	addRepeatFlags(cmd, &repeatOpts, false)
 
 	return cmd
 }
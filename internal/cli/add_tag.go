@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewAddTagCommand builds the add-tag subcommand.
+func NewAddTagCommand(app *App) *cobra.Command {
+	opts := things.AddTagOptions{}
+	var allowUnsafeTitle bool
+
+	cmd := &cobra.Command{
+		Use:     "add-tag [OPTIONS...] [-|TITLE]",
+		Aliases: []string{"create-tag"},
+		Short:   "Add a new tag",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rawInput, err := readInput(app.In, args)
+			if err != nil {
+				return err
+			}
+			title := extractTitle(rawInput, "")
+			if err := guardUnsafeTitle(title, allowUnsafeTitle); err != nil {
+				return err
+			}
+
+			script, err := things.BuildAddTagScript(opts, rawInput)
+			if err != nil {
+				return err
+			}
+			return runScript(app, script)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.Parent, "parent", "", "Parent tag title")
+	flags.StringVar(&opts.Shortcut, "shortcut", "", "Keyboard shortcut")
+	flags.BoolVar(&allowUnsafeTitle, "allow-unsafe-title", false, "Allow titles that look like flag assignments")
+
+	return cmd
+}
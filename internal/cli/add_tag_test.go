@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAddTagCommandWithParent(t *testing.T) {
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"add-tag", "--parent", "Errands", "--shortcut", "g", "Groceries"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, "make new tag with properties {name:\"Groceries\"}") {
+		t.Fatalf("expected make new tag, got %q", script)
+	}
+	if !strings.Contains(script, "set parent tag of newTag to tag \"Errands\"") {
+		t.Fatalf("expected parent tag, got %q", script)
+	}
+}
+
+func TestUpdateTagCommandRename(t *testing.T) {
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"update-tag", "--title", "Calls", "Phone"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, "set targetTag to tag \"Phone\"") || !strings.Contains(script, "set name of targetTag to \"Calls\"") {
+		t.Fatalf("unexpected script %q", script)
+	}
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
 	"github.com/spf13/cobra"
@@ -12,8 +13,10 @@ func NewAllCommand(app *App) *cobra.Command {
 	var dbPath string
 	var limit int
 	var asJSON bool
+	var format string
 	var noHeader bool
 	var recursive bool
+	var templateFlags taskTemplateFlags
 
 	cmd := &cobra.Command{
 		Use:   "all",
@@ -25,10 +28,29 @@ func NewAllCommand(app *App) *cobra.Command {
 			}
 			defer store.Close()
 
+			// --template renders the task sections; projects and areas are
+			// not tasks, so No Area and Areas are left out.
+			taskOpts := TaskOutputOptions{Format: "table", NoHeader: noHeader}
+			tmpl, err := parseTaskTemplate(templateFlags)
+			if err != nil {
+				return err
+			}
+			if tmpl != nil {
+				if asJSON || strings.TrimSpace(format) != "" {
+					return fmt.Errorf("Error: --template cannot be used with --format or --json")
+				}
+				taskOpts = TaskOutputOptions{Format: formatTemplate, NoHeader: noHeader, Template: tmpl}
+			}
+			format, err = resolveOutlineFormat(format, asJSON)
+			if err != nil {
+				return err
+			}
+			includeChecklist := recursive || taskOpts.needsChecklist()
+
 			incompleteFilter, _, err := buildTaskFilter(store, TaskQueryOptions{
 				Status:           "incomplete",
 				Limit:            limit,
-				IncludeChecklist: recursive,
+				IncludeChecklist: includeChecklist,
 			})
 			if err != nil {
 				return err
@@ -36,7 +58,7 @@ func NewAllCommand(app *App) *cobra.Command {
 			anyFilter, _, err := buildTaskFilter(store, TaskQueryOptions{
 				Status:           "any",
 				Limit:            limit,
-				IncludeChecklist: recursive,
+				IncludeChecklist: includeChecklist,
 			})
 			if err != nil {
 				return err
@@ -100,7 +122,29 @@ func NewAllCommand(app *App) *cobra.Command {
 				areas = areaItems
 			}
 
-			if asJSON {
+			if isOutlineFormat(format) {
+				var noAreaItems, areaItems []db.TreeItem
+				if recursive {
+					noAreaItems = noArea.([]db.TreeItem)
+					areaItems = areas.([]db.TreeItem)
+				} else {
+					noAreaItems = projectTreeItems(noArea.([]db.Project))
+					areaItems = areaTreeItems(areas.([]db.Area))
+				}
+				sections := []outlineNode{
+					{Title: "Inbox", Children: taskOutline(inbox)},
+					{Title: "Today", Children: taskOutline(today)},
+					{Title: "Upcoming", Children: taskOutline(upcoming)},
+					{Title: "Repeating", Children: taskOutline(repeating)},
+					{Title: "Anytime", Children: taskOutline(anytime)},
+					{Title: "Someday", Children: taskOutline(someday)},
+					{Title: "Logbook", Children: taskOutline(logbook)},
+					{Title: "No Area", Children: treeOutline(noAreaItems)},
+					{Title: "Areas", Children: treeOutline(areaItems)},
+				}
+				return writeOutline(app.Out, sections, format)
+			}
+			if format == "json" {
 				sections := []struct {
 					Title string `json:"title"`
 					Items any    `json:"items"`
@@ -118,8 +162,6 @@ func NewAllCommand(app *App) *cobra.Command {
 				enc := json.NewEncoder(app.Out)
 				return enc.Encode(sections)
 			}
-			tableOpts := TaskOutputOptions{Format: "table", NoHeader: noHeader}
-
 			first := true
 			printSection := func(title string, fn func() error) error {
 				if !first {
@@ -134,7 +176,7 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(inbox) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, inbox, tableOpts)
+				return printTasks(app.Out, inbox, taskOpts)
 			}); err != nil {
 				return err
 			}
@@ -142,7 +184,7 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(today) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, today, tableOpts)
+				return printTasks(app.Out, today, taskOpts)
 			}); err != nil {
 				return err
 			}
@@ -150,7 +192,7 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(upcoming) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, upcoming, tableOpts)
+				return printTasks(app.Out, upcoming, taskOpts)
 			}); err != nil {
 				return err
 			}
@@ -158,7 +200,7 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(repeating) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, repeating, tableOpts)
+				return printTasks(app.Out, repeating, taskOpts)
 			}); err != nil {
 				return err
 			}
@@ -166,7 +208,7 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(anytime) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, anytime, tableOpts)
+				return printTasks(app.Out, anytime, taskOpts)
 			}); err != nil {
 				return err
 			}
@@ -174,7 +216,7 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(someday) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, someday, tableOpts)
+				return printTasks(app.Out, someday, taskOpts)
 			}); err != nil {
 				return err
 			}
@@ -182,17 +224,20 @@ func NewAllCommand(app *App) *cobra.Command {
 				if len(logbook) == 0 {
 					return nil
 				}
-				return printTasks(app.Out, logbook, tableOpts)
+				return printTasks(app.Out, logbook, taskOpts)
 			}); err != nil {
 				return err
 			}
+			if taskOpts.Template != nil {
+				return nil
+			}
 			if err := printSection("No Area", func() error {
 				if recursive {
 					items := noArea.([]db.TreeItem)
 					if len(items) == 0 {
 						return nil
 					}
-					return printTree(app.Out, items, false)
+					return printTree(app.Out, items, "table")
 				}
 				items := noArea.([]db.Project)
 				if len(items) == 0 {
@@ -208,7 +253,7 @@ func NewAllCommand(app *App) *cobra.Command {
 					if len(items) == 0 {
 						return nil
 					}
-					return printTree(app.Out, items, false)
+					return printTree(app.Out, items, "table")
 				}
 				items := areas.([]db.Area)
 				if len(items) == 0 {
@@ -227,7 +272,10 @@ func NewAllCommand(app *App) *cobra.Command {
 	cmd.Flags().IntVar(&limit, "limit", 200, "Limit number of results (0 = no limit)")
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Include checklist items in JSON output")
 	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: table, json, markdown, org, taskpaper")
 	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Suppress header row")
+	cmd.Flags().StringVar(&templateFlags.Text, "template", "", "Render each task with a Go text/template")
+	cmd.Flags().StringVar(&templateFlags.File, "template-file", "", "Read the --template text from a file")
 
 	return cmd
 }
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 
+	"github.com/ossianhempel/things3-cli/internal/config"
 	"github.com/ossianhempel/things3-cli/internal/open"
 	"github.com/ossianhempel/things3-cli/internal/osascript"
 )
@@ -28,6 +29,10 @@ type App struct {
 	Debug      bool
 	Foreground bool
 	DryRun     bool
+	// ProfileName selects a config profile (--profile); once the command
+	// starts it names the profile in effect, which Profile holds.
+	ProfileName string
+	Profile     *config.Profile
 }
 
 // NewApp builds the default application wiring.
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewApplyCommand builds the apply subcommand.
+func NewApplyCommand(app *App) *cobra.Command {
+	var dbPath string
+	var authToken string
+
+	cmd := &cobra.Command{
+		Use:   "apply [OPTIONS...] PLAN",
+		Short: "Apply an update plan",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := readUpdatePlan(args[0])
+			if err != nil {
+				return err
+			}
+
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			if err := checkPlanFresh(store, plan); err != nil {
+				return err
+			}
+			// The tasks are unchanged since planning, but the --when checks
+			// depend on the day, so run them again.
+			tasks := make([]db.Task, 0, len(plan.Tasks))
+			for _, task := range plan.Tasks {
+				tasks = append(tasks, task.Before)
+			}
+			if err := checkWhenTargets(tasks, plan.Options, plan.AllowNonToday); err != nil {
+				return err
+			}
+			if app.DryRun {
+				printPlanDiff(app.Out, plan)
+				fmt.Fprintln(app.Out)
+			}
+
+			opts := plan.Options
+			token, err := resolveAuthToken(app, authToken)
+			if err != nil {
+				return err
+			}
+			opts.AuthToken = token
+
+			entry := ActionEntry{
+				Type:  ActionUpdate,
+				Items: make([]ActionItem, 0, len(plan.Tasks)),
+			}
+			urls := make([]string, 0, len(plan.Tasks))
+			for _, task := range plan.Tasks {
+				opts.ID = task.Before.UUID
+				url, err := things.BuildUpdateURL(opts, plan.Input)
+				if err != nil {
+					return err
+				}
+				urls = append(urls, url)
+				item := taskToActionItem(task.Before)
+				expected := task.After
+				item.Expected = &expected
+				entry.Items = append(entry.Items, item)
+				entry.Redo = append(entry.Redo, urlStep(url))
+			}
+			logAction(app, entry)
+
+			return openURLs(app, urls)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
+	flags.StringVar(&authToken, "auth-token", "", "Things URL scheme authorization token")
+
+	return cmd
+}
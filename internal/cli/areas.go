@@ -9,6 +9,7 @@ import (
 func NewAreasCommand(app *App) *cobra.Command {
 	var dbPath string
 	var asJSON bool
+	var format string
 	var noHeader bool
 	var recursive bool
 	var onlyProjects bool
@@ -26,6 +27,10 @@ func NewAreasCommand(app *App) *cobra.Command {
 			if onlyProjects && !recursive {
 				recursive = true
 			}
+			format, err = resolveOutlineFormat(format, asJSON)
+			if err != nil {
+				return err
+			}
 
 			if recursive {
 				status := db.StatusIncomplete
@@ -37,20 +42,24 @@ func NewAreasCommand(app *App) *cobra.Command {
 				if err != nil {
 					return formatDBError(err)
 				}
-				return printTree(app.Out, items, asJSON)
+				return printTree(app.Out, items, format)
 			}
 
 			areas, err := store.Areas()
 			if err != nil {
 				return formatDBError(err)
 			}
-			return printAreas(app.Out, areas, asJSON, noHeader)
+			if isOutlineFormat(format) {
+				return printTree(app.Out, areaTreeItems(areas), format)
+			}
+			return printAreas(app.Out, areas, format == "json", noHeader)
 		},
 	}
 
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: table, json, markdown, org, taskpaper")
 	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Suppress header row")
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Include nested projects/headings/todos")
 	cmd.Flags().BoolVarP(&onlyProjects, "only-projects", "e", false, "Only include areas and projects")
@@ -20,7 +20,10 @@ func NewAuthCommand(app *App) *cobra.Command {
 		Use:   "auth",
 		Short: "Show Things auth token status and setup help",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token := authTokenFromEnv()
+			token, source, err := configuredAuthToken(app)
+			if err != nil {
+				return err
+			}
 			if token == "" {
 				fmt.Fprintln(app.Out, "Things auth token: not set.")
 				fmt.Fprintln(app.Out)
@@ -28,7 +31,7 @@ func NewAuthCommand(app *App) *cobra.Command {
 				return nil
 			}
 
-			fmt.Fprintln(app.Out, "Things auth token: set (THINGS_AUTH_TOKEN).")
+			fmt.Fprintf(app.Out, "Things auth token: set (%s).\n", source)
 			fmt.Fprintln(app.Out, "Use update/update-project, or pass --auth-token to override.")
 			return nil
 		},
@@ -17,6 +17,7 @@ func NewCreatedTodayCommand(app *App) *cobra.Command {
 	var selectRaw string
 	var asJSON bool
 	var noHeader bool
+	var templateFlags taskTemplateFlags
 
 	cmd := &cobra.Command{
 		Use:   "createdtoday",
@@ -31,10 +32,13 @@ func NewCreatedTodayCommand(app *App) *cobra.Command {
 			now := time.Now()
 			start := now.Add(-24 * time.Hour)
 			opts.HasURLSet = cmd.Flags().Changed("has-url")
-			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader)
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
 			if err != nil {
 				return err
 			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
 			forcePost := opts.Query != "" || opts.Sort != "" || opts.Offset > 0
 			tasks, err := fetchTasks(store, func(filter db.TaskFilter) ([]db.Task, error) {
 				return store.TasksCreatedBetween(start, now, filter)
@@ -49,7 +53,7 @@ func NewCreatedTodayCommand(app *App) *cobra.Command {
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	addTaskQueryFlags(cmd, &opts, true, true)
-	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
 
 	return cmd
 }
@@ -64,6 +68,7 @@ func NewLogTodayCommand(app *App) *cobra.Command {
 	var selectRaw string
 	var asJSON bool
 	var noHeader bool
+	var templateFlags taskTemplateFlags
 
 	cmd := &cobra.Command{
 		Use:   "logtoday",
@@ -77,10 +82,13 @@ func NewLogTodayCommand(app *App) *cobra.Command {
 
 			start, end := dayBounds()
 			opts.HasURLSet = cmd.Flags().Changed("has-url")
-			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader)
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
 			if err != nil {
 				return err
 			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
 			forcePost := opts.Query != "" || opts.Sort != "" || opts.Offset > 0
 			tasks, err := fetchTasks(store, func(filter db.TaskFilter) ([]db.Task, error) {
 				return store.TasksCompletedBetween(start, end, filter)
@@ -95,7 +103,7 @@ func NewLogTodayCommand(app *App) *cobra.Command {
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	addTaskQueryFlags(cmd, &opts, true, true)
-	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
 
 	return cmd
 }
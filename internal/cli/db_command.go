@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// NewDBCommand builds the db command group.
+func NewDBCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "db <backup|backups|restore-backup>",
+		Short: "Manage database backups",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printHelp(app.Out, formatHelpText(dbHelp, isTTY(app.Out)))
+			return ErrHelpPrinted
+		},
+	}
+	cmd.AddCommand(newDBBackupCommand(app))
+	cmd.AddCommand(newDBBackupsCommand(app))
+	cmd.AddCommand(newDBRestoreBackupCommand(app))
+	return cmd
+}
+
+func newDBBackupCommand(app *App) *cobra.Command {
+	var dbPath string
+
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Snapshot the database into the backups directory",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := db.BackupDir()
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			keep, err := db.BackupKeep()
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would back up database to %s\n", dir)
+				return nil
+			}
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			path, err := store.Backup(dir, keep)
+			if err != nil {
+				return formatDBError(err)
+			}
+			fmt.Fprintln(app.Out, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	return cmd
+}
+
+func newDBBackupsCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backups",
+		Short: "List database backups, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := db.BackupDir()
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			backups, err := db.Backups(dir)
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			if len(backups) == 0 {
+				fmt.Fprintf(app.Err, "No backups in %s\n", dir)
+				return nil
+			}
+			w := tabwriter.NewWriter(app.Out, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "CREATED\tSIZE\tPATH")
+			for _, backup := range backups {
+				fmt.Fprintf(w, "%s\t%d\t%s\n", backup.Created.Format("2006-01-02 15:04:05"), backup.Size, backup.Path)
+			}
+			return w.Flush()
+		},
+	}
+	return cmd
+}
+
+func newDBRestoreBackupCommand(app *App) *cobra.Command {
+	var dbPath string
+	var yes bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "restore-backup [FILE]",
+		Short: "Overwrite the database with a backup (latest by default)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			dir, err := db.BackupDir()
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			source := ""
+			if len(args) == 1 {
+				source = strings.TrimSpace(args[0])
+			}
+			if source == "" {
+				backups, err := db.Backups(dir)
+				if err != nil {
+					return fmt.Errorf("Error: %v", err)
+				}
+				if len(backups) == 0 {
+					return fmt.Errorf("Error: no backups found in %s", dir)
+				}
+				source = backups[0].Path
+			}
+
+			target, err := db.ResolveDatabasePath(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would restore %s over %s\n", source, target)
+				return nil
+			}
+			if !yes {
+				return fmt.Errorf("Error: restoring overwrites %s (rerun with --yes to apply)", target)
+			}
+			// Things keeps the database open and would write over the
+			// restored copy.
+			if !force && thingsRunning() {
+				return fmt.Errorf("Error: Things is running; quit it before restoring (or rerun with --force)")
+			}
+
+			store, err := db.OpenWritable(target)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer closeWriteStore(app, store, &err)
+
+			// Snapshot the current state without rotation so the backup being
+			// restored cannot be pruned out from under us.
+			saved, err := store.Backup(dir, 0)
+			if err != nil {
+				return formatDBError(err)
+			}
+			if err := store.RestoreBackup(source); err != nil {
+				return formatDBError(err)
+			}
+			fmt.Fprintf(app.Out, "Restored %s\n", source)
+			fmt.Fprintf(app.Err, "Note: previous database saved to %s\n", saved)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm overwriting the database")
+	cmd.Flags().BoolVar(&force, "force", false, "Restore even while Things is running")
+	return cmd
+}
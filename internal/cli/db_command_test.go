@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/repeat"
+)
+
+func TestDirectWriteBackupAndRestore(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+	addRepeatColumns(t, conn)
+
+	update, err := repeat.BuildUpdate(repeat.Spec{
+		Mode:   repeat.ModeSchedule,
+		Unit:   repeat.UnitWeek,
+		Every:  1,
+		Anchor: time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatalf("build rule: %v", err)
+	}
+	if _, err := conn.Exec(`UPDATE TMTask SET rt1_recurrenceRule = ?, rt1_instanceCreationPaused = 0 WHERE uuid = 'T1'`, update.RecurrenceRule); err != nil {
+		t.Fatalf("set rule: %v", err)
+	}
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		out := &bytes.Buffer{}
+		app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		err := root.Execute()
+		return out.String(), err
+	}
+	paused := func() int {
+		t.Helper()
+		var value int
+		if err := conn.QueryRow(`SELECT rt1_instanceCreationPaused FROM TMTask WHERE uuid = 'T1'`).Scan(&value); err != nil {
+			t.Fatalf("select paused: %v", err)
+		}
+		return value
+	}
+
+	if _, err := run("repeat", "pause", "--db", dbPath, "--id", "T1"); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if paused() != 1 {
+		t.Fatalf("expected template paused")
+	}
+	out, err := run("db", "backups")
+	if err != nil {
+		t.Fatalf("backups: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 {
+		t.Fatalf("expected one backup listed, got:\n%s", out)
+	}
+
+	if _, err := run("db", "restore-backup", "--db", dbPath); err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected --yes to be required, got %v", err)
+	}
+	defer func(running func() bool) { thingsRunning = running }(thingsRunning)
+	thingsRunning = func() bool { return true }
+	if _, err := run("db", "restore-backup", "--db", dbPath, "--yes"); err == nil || !strings.Contains(err.Error(), "Things is running") {
+		t.Fatalf("expected restore to refuse while Things runs, got %v", err)
+	}
+	if paused() != 1 {
+		t.Fatalf("expected refused restore to leave the database alone")
+	}
+	if _, err := run("db", "restore-backup", "--db", dbPath, "--yes", "--force"); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if paused() != 0 {
+		t.Fatalf("expected restore to undo the pause")
+	}
+	entries, err := os.ReadDir(os.Getenv("THINGS_BACKUP_DIR"))
+	if err != nil {
+		t.Fatalf("read backups: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected pre-restore snapshot alongside the original, got %d files", len(entries))
+	}
+}
+
+func TestDirectWriteSkipsBackupWithoutWrite(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	addRepeatColumns(t, conn)
+	conn.Close()
+
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"repeat", "pause", "--db", dbPath, "--id", "ANY1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "does not repeat") {
+		t.Fatalf("expected does-not-repeat error, got %v", err)
+	}
+	if entries, err := os.ReadDir(os.Getenv("THINGS_BACKUP_DIR")); err == nil && len(entries) > 0 {
+		t.Fatalf("expected no backup without a write, got %d", len(entries))
+	}
+}
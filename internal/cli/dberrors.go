@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,9 +12,20 @@ func formatDBError(err error) error {
 	if err == nil {
 		return nil
 	}
+	if errors.Is(err, ErrExplainPrinted) {
+		return err
+	}
 	if err == db.ErrDatabaseNotFound {
 		return fmt.Errorf("Error: Things database not found. Set THINGSDB or use --db to specify the path")
 	}
+	var schemaErr *db.SchemaError
+	if errors.As(err, &schemaErr) {
+		version := ""
+		if schemaErr.Version != "" {
+			version = fmt.Sprintf(" (database version %s)", schemaErr.Version)
+		}
+		return fmt.Errorf("Error: this Things database%s is missing columns things3-cli needs: %s. things3-cli supports database version %s; update things3-cli, and run `things doctor` for details", version, strings.Join(schemaErr.Missing, ", "), db.SupportedSchemaVersion)
+	}
 	msg := err.Error()
 	if strings.HasPrefix(msg, "Error:") {
 		return err
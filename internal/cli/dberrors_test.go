@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestUnsupportedSchemaError(t *testing.T) {
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`DROP TABLE TMTaskTag`); err != nil {
+		t.Fatalf("drop table: %v", err)
+	}
+	conn.Close()
+
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"tasks", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	err = root.Execute()
+	if err == nil {
+		t.Fatalf("expected schema error")
+	}
+	for _, want := range []string{"missing columns things3-cli needs: TMTaskTag.tags, TMTaskTag.tasks", "things doctor"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("expected %q in %q", want, err.Error())
+		}
+	}
+}
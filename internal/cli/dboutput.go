@@ -114,13 +114,30 @@ func printItem(out io.Writer, item *db.Item, asJSON bool, noHeader bool) error {
 	}
 	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 		item.Type, item.UUID, item.Title, status, trashed, item.ProjectTitle, item.AreaTitle, item.HeadingTitle, visible, item.Shortcut, item.ParentID)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(item.Occurrences) == 0 {
+		return nil
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	if !noHeader {
+		fmt.Fprintln(w, "NEXT\tDEADLINE")
+	}
+	for _, occurrence := range item.Occurrences {
+		fmt.Fprintf(w, "%s\t%s\n", occurrence.Start, occurrence.Deadline)
+	}
 	return w.Flush()
 }
 
-func printTree(out io.Writer, items []db.TreeItem, asJSON bool) error {
-	if asJSON {
+func printTree(out io.Writer, items []db.TreeItem, format string) error {
+	switch {
+	case format == "json":
 		enc := json.NewEncoder(out)
 		return enc.Encode(items)
+	case isOutlineFormat(format):
+		return writeOutline(out, treeOutline(items), format)
 	}
 	printTreeItems(out, items, "")
 	return nil
@@ -138,3 +155,18 @@ func printTreeItems(out io.Writer, items []db.TreeItem, indent string) {
 		}
 	}
 }
+
+func printHeadings(out io.Writer, headings []db.Heading, asJSON bool, noHeader bool) error {
+	if asJSON {
+		enc := json.NewEncoder(out)
+		return enc.Encode(headings)
+	}
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	if !noHeader {
+		fmt.Fprintln(w, "UUID\tTITLE\tPROJECT\tOPEN\tARCHIVED")
+	}
+	for _, h := range headings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\n", h.UUID, h.Title, h.ProjectTitle, h.OpenTasks, h.Archived)
+	}
+	return w.Flush()
+}
@@ -9,6 +9,7 @@ import (
 
 func writeTestDB(t *testing.T) string {
 	t.Helper()
+	t.Setenv("THINGS_BACKUP_DIR", filepath.Join(t.TempDir(), "backups"))
 	path := filepath.Join(t.TempDir(), "Things.sqlite3")
 	conn, err := sql.Open("sqlite", path)
 	if err != nil {
@@ -75,6 +76,10 @@ func writeTestDB(t *testing.T) string {
 	if _, err := conn.Exec(`INSERT INTO TMTask (uuid, type, status, trashed, title, project, area, heading, notes) VALUES ('H1', ?, ?, 0, 'Heading', 'P1', 'A1', '', '');`, 2, 0); err != nil {
 		t.Fatalf("insert heading: %v", err)
 	}
+	// Things leaves project unset on todos under a heading.
+	if _, err := conn.Exec(`INSERT INTO TMTask (uuid, type, status, trashed, title, heading, notes, start, "index", creationDate) VALUES ('HT1', ?, ?, 0, 'Heading Task', 'H1', '', 1, 1, ?);`, 0, 0, nowUnix); err != nil {
+		t.Fatalf("insert heading task: %v", err)
+	}
 	if _, err := conn.Exec(`INSERT INTO TMTag (uuid, title) VALUES ('TAG1', 'urgent');`); err != nil {
 		t.Fatalf("insert tag: %v", err)
 	}
@@ -134,3 +139,21 @@ func thingsDate(t time.Time) int {
 func floatPtr(v float64) *float64 {
 	return &v
 }
+
+// addRepeatColumns adds the remaining rt1_* columns that repeat updates
+// write, which the base fixture leaves out.
+func addRepeatColumns(t *testing.T, conn *sql.DB) {
+	t.Helper()
+	for _, column := range []string{
+		"rt1_repeatingTemplate TEXT",
+		"rt1_instanceCreationStartDate INTEGER",
+		"rt1_instanceCreationPaused INTEGER",
+		"rt1_instanceCreationCount INTEGER",
+		"rt1_afterCompletionReferenceDate INTEGER",
+		"rt1_nextInstanceStartDate INTEGER",
+	} {
+		if _, err := conn.Exec("ALTER TABLE TMTask ADD COLUMN " + column); err != nil {
+			t.Fatalf("add column: %v", err)
+		}
+	}
+}
@@ -16,8 +16,9 @@ func NewDeleteCommand(app *App) *cobra.Command {
 	var confirm string
 	var yes bool
 	opts := TaskQueryOptions{
-		Status: "incomplete",
-		Limit:  200,
+		Status:     "incomplete",
+		Limit:      200,
+		ExactMatch: true,
 	}
 
 	cmd := &cobra.Command{
@@ -83,17 +84,6 @@ func NewDeleteCommand(app *App) *cobra.Command {
 				}
 			}
 
-			entry := ActionEntry{
-				Type:  ActionTrash,
-				Items: make([]ActionItem, 0, len(tasks)),
-			}
-			for _, task := range tasks {
-				entry.Items = append(entry.Items, taskToActionItem(task))
-			}
-			if err := appendAction(entry); err != nil {
-				fmt.Fprintf(app.Err, "Warning: failed to write action log: %v\n", err)
-			}
-
 			ids := make([]string, 0, len(tasks))
 			for _, task := range tasks {
 				ids = append(ids, task.UUID)
@@ -102,6 +92,17 @@ func NewDeleteCommand(app *App) *cobra.Command {
 			if err != nil {
 				return err
 			}
+
+			entry := ActionEntry{
+				Type:  ActionTrash,
+				Items: make([]ActionItem, 0, len(tasks)),
+				Redo:  []ActionStep{{Script: script}},
+			}
+			for _, task := range tasks {
+				entry.Items = append(entry.Items, taskToActionItem(task))
+			}
+			logAction(app, entry)
+
 			return runScript(app, script)
 		},
 	}
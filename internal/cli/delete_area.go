@@ -8,6 +8,7 @@ import (
 // NewDeleteAreaCommand builds the delete-area subcommand.
 func NewDeleteAreaCommand(app *App) *cobra.Command {
 	opts := things.DeleteAreaOptions{}
+	var dbPath string
 	var confirm string
 
 	cmd := &cobra.Command{
@@ -28,11 +29,24 @@ func NewDeleteAreaCommand(app *App) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return runScript(app, script)
+			area, found := lookupArea(app, dbPath, opts.ID, rawInput)
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+			if found {
+				logAction(app, ActionEntry{
+					Type:  ActionDeleteArea,
+					Items: []ActionItem{area},
+					Redo:  []ActionStep{{Script: script}},
+				})
+			}
+			return nil
 		},
 	}
 
 	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
 	flags.StringVar(&opts.ID, "id", "", "ID of the area to delete")
 	flags.StringVar(&confirm, "confirm", "", "Confirm deletion by typing the area ID or title")
 
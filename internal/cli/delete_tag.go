@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewDeleteTagCommand builds the delete-tag subcommand.
+func NewDeleteTagCommand(app *App) *cobra.Command {
+	opts := things.DeleteTagOptions{}
+	var confirm string
+
+	cmd := &cobra.Command{
+		Use:   "delete-tag [OPTIONS...] [--] [-|TITLE]",
+		Short: "Delete an existing tag",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rawInput, err := readInput(app.In, args)
+			if err != nil {
+				return err
+			}
+
+			target := deleteConfirmTarget(opts.ID, rawInput)
+			if err := confirmDelete(app, "tag", target, confirm); err != nil {
+				return err
+			}
+
+			script, err := things.BuildDeleteTagScript(opts, rawInput)
+			if err != nil {
+				return err
+			}
+			return runScript(app, script)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.ID, "id", "", "ID of the tag to delete")
+	flags.StringVar(&confirm, "confirm", "", "Confirm deletion by typing the tag ID or title")
+
+	return cmd
+}
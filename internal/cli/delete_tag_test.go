@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDeleteTagCommandRequiresConfirm(t *testing.T) {
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"delete-tag", "Old"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected confirmation error")
+	}
+	if runner.script != "" {
+		t.Fatalf("expected no script execution")
+	}
+
+	root = NewRoot(app)
+	root.SetArgs([]string{"delete-tag", "--confirm", "Old", "Old"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if script := requireScript(t, runner); !strings.Contains(script, "delete targetTag") {
+		t.Fatalf("expected delete in script, got %q", script)
+	}
+}
@@ -129,3 +129,30 @@ func TestDeleteCommandQueryWithYes(t *testing.T) {
 		t.Fatalf("expected trash script, got %q", script)
 	}
 }
+
+func TestDeleteCommandProjectTypoFails(t *testing.T) {
+	dbPath := writeTestDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{
+		In:       strings.NewReader(""),
+		Out:      &bytes.Buffer{},
+		Err:      &bytes.Buffer{},
+		Scripter: runner,
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"--dry-run", "delete", "--db", dbPath, "--project", "Projct One", "--yes"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "project not found: Projct One") {
+		t.Fatalf("expected project not found error, got %v", err)
+	}
+	if runner.script != "" {
+		t.Fatalf("expected no script execution")
+	}
+	if out := app.Out.(*bytes.Buffer).String(); strings.Contains(out, "Task One") {
+		t.Fatalf("expected no todos selected, got %q", out)
+	}
+}
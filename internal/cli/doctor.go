@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// Doctor check statuses.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+const fullDiskAccessFix = "Grant your terminal Full Disk Access: System Settings -> Privacy & Security -> Full Disk Access, then restart the terminal."
+
+// doctorCheck is one diagnostic result.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+// doctorReport is the --json output of things doctor.
+type doctorReport struct {
+	OK             bool          `json:"ok"`
+	DatabasePath   string        `json:"database_path,omitempty"`
+	DatabaseSource string        `json:"database_source,omitempty"`
+	Checks         []doctorCheck `json:"checks"`
+}
+
+// NewDoctorCommand builds the doctor subcommand.
+func NewDoctorCommand(app *App) *cobra.Command {
+	var dbPath string
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor [OPTIONS...]",
+		Short: "Check the environment and Things database compatibility",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report := runDoctor(app, dbPath)
+			if asJSON {
+				if err := json.NewEncoder(app.Out).Encode(report); err != nil {
+					return err
+				}
+			} else {
+				printDoctorReport(app.Out, report)
+			}
+			failed := 0
+			for _, check := range report.Checks {
+				if check.Status == doctorFail {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("Error: %d doctor check(s) failed", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+
+	return cmd
+}
+
+func runDoctor(app *App, dbPath string) doctorReport {
+	report := doctorReport{}
+	report.Checks = append(report.Checks, doctorDatabaseChecks(&report, dbPath)...)
+	report.Checks = append(report.Checks, doctorAuthCheck(app))
+	report.Checks = append(report.Checks, doctorCommandCheck("open", "OPEN", "open"))
+	osascriptCheck := doctorCommandCheck("osascript", "OSASCRIPT", "osascript")
+	report.Checks = append(report.Checks, osascriptCheck)
+	if osascriptCheck.Status == doctorOK {
+		report.Checks = append(report.Checks, doctorAutomationCheck())
+	}
+	report.Checks = append(report.Checks, doctorThingsVersionCheck())
+
+	report.OK = true
+	for _, check := range report.Checks {
+		if check.Status == doctorFail {
+			report.OK = false
+		}
+	}
+	return report
+}
+
+func doctorDatabaseChecks(report *doctorReport, dbPath string) []doctorCheck {
+	path, source, err := db.ResolveDatabasePathSource(dbPath)
+	if err != nil {
+		check := doctorCheck{Name: "database", Status: doctorFail, Detail: err.Error()}
+		if errors.Is(err, db.ErrDatabaseNotFound) {
+			check.Detail = "no Things database found under ~/Library/Group Containers"
+			check.Fix = "Install and open Things 3 once, or point at the database with THINGSDB or --db. " + fullDiskAccessFix
+		}
+		return []doctorCheck{check}
+	}
+	report.DatabasePath = path
+	report.DatabaseSource = source
+	checks := []doctorCheck{{
+		Name:   "database",
+		Status: doctorOK,
+		Detail: fmt.Sprintf("%s (found via %s)", path, source),
+	}}
+
+	file, err := os.Open(path)
+	if err != nil {
+		check := doctorCheck{Name: "database access", Status: doctorFail, Detail: err.Error()}
+		switch {
+		case os.IsPermission(err):
+			check.Detail = "permission denied reading the database"
+			check.Fix = fullDiskAccessFix
+		case os.IsNotExist(err):
+			check.Detail = fmt.Sprintf("%s does not exist", path)
+			check.Fix = fmt.Sprintf("Fix the path given by %s, or unset it to use the default location.", source)
+		}
+		return append(checks, check)
+	}
+	_ = file.Close()
+
+	store, err := db.Open(path)
+	if err != nil {
+		return append(checks, doctorCheck{
+			Name:   "database access",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    fullDiskAccessFix,
+		})
+	}
+	defer store.Close()
+	checks = append(checks, doctorCheck{Name: "database access", Status: doctorOK, Detail: "opened read-only"})
+
+	missing, err := store.MissingTaskQueryColumns()
+	switch {
+	case err != nil:
+		checks = append(checks, doctorCheck{Name: "schema", Status: doctorFail, Detail: err.Error(), Fix: fullDiskAccessFix})
+	case len(missing) > 0:
+		checks = append(checks, doctorCheck{
+			Name:   "schema",
+			Status: doctorFail,
+			Detail: "missing columns: " + strings.Join(missing, ", "),
+			Fix:    "This Things version changed the database layout. Update things3-cli, or report the missing columns along with your Things version.",
+		})
+	default:
+		detail := "all columns used by task queries are present"
+		if schema, err := store.Schema(); err == nil && schema.Version != "" {
+			detail = fmt.Sprintf("database version %s; %s", schema.Version, detail)
+		}
+		checks = append(checks, doctorCheck{Name: "schema", Status: doctorOK, Detail: detail})
+	}
+	return checks
+}
+
+func doctorAuthCheck(app *App) doctorCheck {
+	token, source, err := configuredAuthToken(app)
+	if err != nil {
+		return doctorCheck{
+			Name:   "auth token",
+			Status: doctorFail,
+			Detail: strings.TrimPrefix(err.Error(), "Error: "),
+			Fix:    "Fix the token source in the profile, or remove it to fall back to THINGS_AUTH_TOKEN.",
+		}
+	}
+	if token == "" {
+		return doctorCheck{
+			Name:   "auth token",
+			Status: doctorWarn,
+			Detail: "THINGS_AUTH_TOKEN is not set (needed by update and update-project)",
+			Fix:    authSetupInstructions,
+		}
+	}
+	return doctorCheck{Name: "auth token", Status: doctorOK, Detail: "set via " + source}
+}
+
+// doctorCommandCheck verifies that a helper command is on PATH, honoring the
+// environment variable that overrides it.
+func doctorCommandCheck(name string, envVar string, fallback string) doctorCheck {
+	command := os.Getenv(envVar)
+	if command == "" {
+		command = fallback
+	}
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return doctorCheck{
+			Name:   name,
+			Status: doctorFail,
+			Detail: fmt.Sprintf("%s not found on PATH", command),
+			Fix:    fmt.Sprintf("things3-cli needs macOS. If %s lives elsewhere, set %s to its path.", fallback, envVar),
+		}
+	}
+	return doctorCheck{Name: name, Status: doctorOK, Detail: path}
+}
+
+// doctorAutomationCheck asks Things for its version over Apple Events, which
+// fails with -1743 when the terminal may not control Things. This launches
+// Things if it is not running.
+func doctorAutomationCheck() doctorCheck {
+	command := os.Getenv("OSASCRIPT")
+	if command == "" {
+		command = "osascript"
+	}
+	script := fmt.Sprintf("tell application id %q to get version", thingsBundleID)
+	out, err := exec.Command(command, "-e", script).CombinedOutput()
+	if err == nil {
+		return doctorCheck{Name: "automation", Status: doctorOK, Detail: "allowed to control Things"}
+	}
+	output := strings.TrimSpace(string(out))
+	if strings.Contains(output, "-1743") {
+		return doctorCheck{
+			Name:   "automation",
+			Status: doctorFail,
+			Detail: "not allowed to send Apple Events to Things",
+			Fix:    "Allow your terminal to control Things: System Settings -> Privacy & Security -> Automation. If it is not listed, run `tccutil reset AppleEvents` and retry to get the prompt again.",
+		}
+	}
+	if output == "" {
+		output = err.Error()
+	}
+	return doctorCheck{
+		Name:   "automation",
+		Status: doctorWarn,
+		Detail: output,
+		Fix:    "Make sure Things 3 is installed, then rerun things doctor.",
+	}
+}
+
+func doctorThingsVersionCheck() doctorCheck {
+	version := things.ThingsVersion()
+	if version == "UNKNOWN" {
+		return doctorCheck{
+			Name:   "things app",
+			Status: doctorWarn,
+			Detail: "Things3.app not found in /Applications",
+			Fix:    "Install Things 3 from the Mac App Store.",
+		}
+	}
+	return doctorCheck{Name: "things app", Status: doctorOK, Detail: "version " + version}
+}
+
+func printDoctorReport(out io.Writer, report doctorReport) {
+	for _, check := range report.Checks {
+		fmt.Fprintf(out, "[%s] %s: %s\n", check.Status, check.Name, check.Detail)
+		if check.Status == doctorOK || check.Fix == "" {
+			continue
+		}
+		for _, line := range strings.Split(check.Fix, "\n") {
+			if line == "" {
+				fmt.Fprintln(out)
+				continue
+			}
+			fmt.Fprintf(out, "    %s\n", line)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runDoctorCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs(append([]string{"doctor"}, args...))
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestDoctorJSONReport(t *testing.T) {
+	dbPath := writeTestDB(t)
+	t.Setenv("THINGS_AUTH_TOKEN", "")
+	t.Setenv("THINGS_VERSION", "3.21")
+	t.Setenv("OPEN", "true")
+	t.Setenv("OSASCRIPT", "true")
+
+	out, err := runDoctorCommand(t, "--db", dbPath, "--json")
+	if err != nil {
+		t.Fatalf("doctor failed: %v\n%s", err, out)
+	}
+	var report doctorReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("decode report: %v\n%s", err, out)
+	}
+	if !report.OK || report.DatabasePath != dbPath || report.DatabaseSource != "--db" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	for _, name := range []string{"database", "database access", "schema", "open", "osascript", "automation", "things app"} {
+		if statuses[name] != doctorOK {
+			t.Fatalf("expected %s ok, got %q (%+v)", name, statuses[name], report.Checks)
+		}
+	}
+	if statuses["auth token"] != doctorWarn {
+		t.Fatalf("expected auth token warning, got %q", statuses["auth token"])
+	}
+}
+
+func TestDoctorReportsFailuresWithFixes(t *testing.T) {
+	dir := t.TempDir()
+	denied := filepath.Join(dir, "osascript")
+	script := "#!/bin/sh\necho 'execution error: Not authorized to send Apple events to Things. (-1743)' >&2\nexit 1\n"
+	if err := os.WriteFile(denied, []byte(script), 0o755); err != nil {
+		t.Fatalf("write script: %v", err)
+	}
+	t.Setenv("THINGSDB", filepath.Join(dir, "missing.sqlite"))
+	t.Setenv("THINGS_AUTH_TOKEN", "token")
+	t.Setenv("THINGS_VERSION", "3.21")
+	t.Setenv("OPEN", "true")
+	t.Setenv("OSASCRIPT", denied)
+
+	out, err := runDoctorCommand(t)
+	if err == nil || !strings.Contains(err.Error(), "2 doctor check(s) failed") {
+		t.Fatalf("expected two failed checks, got %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "found via THINGSDB") {
+		t.Fatalf("expected database source, got:\n%s", out)
+	}
+	if !strings.Contains(out, "[fail] database access:") || !strings.Contains(out, "unset it to use the default location") {
+		t.Fatalf("expected missing database fix, got:\n%s", out)
+	}
+	if !strings.Contains(out, "[fail] automation:") || !strings.Contains(out, "Privacy & Security -> Automation") {
+		t.Fatalf("expected automation fix, got:\n%s", out)
+	}
+}
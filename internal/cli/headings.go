@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// NewHeadingsCommand builds the headings command.
+func NewHeadingsCommand(app *App) *cobra.Command {
+	var dbPath string
+	var project string
+	var includeArchived bool
+	var asJSON bool
+	var noHeader bool
+
+	cmd := &cobra.Command{
+		Use:   "headings",
+		Short: "List project headings from the Things database",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			projectID := ""
+			if project != "" {
+				projectID, err = store.MatchProjectID(project)
+				projectID, err = resolvePicked(app.In, app.Err, projectID, err)
+				if err != nil {
+					return fmt.Errorf("Error: %s", err)
+				}
+			}
+			headings, err := store.Headings(projectID, includeArchived)
+			if err != nil {
+				return formatDBError(err)
+			}
+			return printHeadings(app.Out, headings, asJSON, noHeader)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Only list headings of this project (title or ID)")
+	cmd.Flags().BoolVar(&includeArchived, "archived", false, "Include archived headings")
+	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Suppress header row")
+
+	return cmd
+}
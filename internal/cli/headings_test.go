@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHeadingsList(t *testing.T) {
+	dbPath := writeTestDB(t)
+	useTempActionLog(t)
+
+	out, err := runWithArgs(t, "headings", "--db", dbPath, "--project", "P1", "--no-header")
+	if err != nil {
+		t.Fatalf("headings: %v", err)
+	}
+	if !strings.Contains(out, "H1") || !strings.Contains(out, "Heading") {
+		t.Fatalf("expected heading H1, got %q", out)
+	}
+}
@@ -15,13 +15,30 @@ COMMANDS
   add            - add new todo
   update         - update exiting todo
   delete         - delete an existing todo
-  undo           - undo the last bulk action
+  restore        - restore trashed todos in place
+  complete       - mark todos completed
+  cancel         - mark todos canceled
+  reopen         - reopen completed or canceled todos
+  undo           - undo a logged action
+  redo           - redo an undone action
+  history        - list logged actions
+  apply          - apply an update plan
   add-area       - add new area
   add-project    - add new project
   update-area    - update exiting area
   delete-area    - delete an area
   update-project - update exiting project
   delete-project - delete an existing project
+  add-tag        - add new tag
+  update-tag     - rename, reparent, or set a shortcut for a tag
+  delete-tag     - delete a tag
+  merge-tags     - merge one tag into another
+  move           - move todos to a project or heading
+  repeat         - pause, resume, or skip repeating templates
+  db             - back up or restore the Things database
+  query          - save and run named queries
+  import-json    - create or update items from a Things JSON payload
+  template       - apply or export project templates
   show           - show an area, project, tag, or todo from the Things database
   search         - search tasks in the Things database
   inbox          - list inbox tasks from the Things database
@@ -41,8 +58,10 @@ COMMANDS
   projects       - list projects from the Things database
   areas          - list areas from the Things database
   tags           - list tags from the Things database
+  headings       - list project headings from the Things database
   tasks          - list todos from the Things database
   auth           - show Things auth token status and setup help
+  doctor         - check the environment and database compatibility
   help           - show documentation for the given command
 
 GLOBAL OPTIONS
@@ -58,6 +77,26 @@ GLOBAL OPTIONS
   --dry-run
     Print the Things URL without opening it.
 
+  --profile NAME
+    Use the named profile from the config file (default: {{BT}}THINGS_PROFILE{{BT}},
+    then {{BT}}default_profile{{BT}}).
+
+CONFIGURATION
+  Profiles live in {{BT}}config.toml{{BT}} in the things3-cli config directory, next to
+  {{BT}}actions.jsonl{{BT}} (override the path with {{BT}}THINGS_CONFIG{{BT}}). A profile sets:
+
+    db                  database path, like --db
+    auth_token          literal auth token
+    auth_token_env      environment variable holding the token
+    auth_token_command  shell command printing the token
+    format, select,     defaults for task listings (--format, --select,
+    sort, limit         --sort, --limit)
+    [aliases]           name = "command line" shortcuts
+    [commands."NAME"]   format/select/sort/limit for one command
+
+  Profile values act as flag defaults: flags given on the command line win,
+  and profile values take precedence over {{BT}}THINGSDB{{BT}} and {{BT}}THINGS_AUTH_TOKEN{{BT}}.
+
 AUTHOR
   Ossian Hempel
 
@@ -82,7 +121,8 @@ SYNOPSIS
   things auth
 
 DESCRIPTION
-  Prints whether {{BT}}THINGS_AUTH_TOKEN{{BT}} is set. If missing, prints setup
+  Prints whether an auth token is set and where it comes from (the active
+  profile or {{BT}}THINGS_AUTH_TOKEN{{BT}}). If missing, prints setup
   steps for the Things URL scheme authorization token.
 
 NOTES
@@ -95,6 +135,47 @@ NOTES
   Tip: add the export to your shell profile (e.g. ~/.zshrc) to persist it.
 `
 
+const doctorHelp = `Usage: things doctor [OPTIONS...]
+
+NAME
+  things doctor - check the environment and database compatibility
+
+SYNOPSIS
+  things doctor [OPTIONS...]
+
+DESCRIPTION
+  Runs every setup check at once and prints a fix for each problem:
+
+    database         the resolved path and how it was found ({{BT}}--db{{BT}},
+                     {{BT}}THINGSDB{{BT}}, the ThingsData-* folder, or the legacy
+                     location)
+    database access  the file can be read (Full Disk Access)
+    schema           the columns task listings query are present
+    auth token       {{BT}}THINGS_AUTH_TOKEN{{BT}} is set (a warning only; needed
+                     by update and update-project)
+    open, osascript  the helper commands are on PATH
+    automation       the terminal may control Things over Apple Events
+    things app       the installed Things version
+
+  The automation check asks Things for its version, which launches Things if
+  it is not running and may show the macOS permission prompt.
+
+  Exits non-zero when any check fails; warnings do not affect the exit code.
+
+OPTIONS
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --json, -j
+    Output JSON: {{BT}}ok{{BT}}, {{BT}}database_path{{BT}}, {{BT}}database_source{{BT}},
+    and {{BT}}checks{{BT}} (each with name, status, detail, and fix).
+
+EXAMPLES
+  things doctor
+
+  things doctor --json
+`
+
 const projectsHelp = `Usage: things projects [OPTIONS...]
 
 NAME
@@ -115,7 +196,7 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --include-trashed
     Include trashed projects.
@@ -126,6 +207,10 @@ OPTIONS
   --json
     Output JSON.
 
+  --format=FORMAT
+    Output format: table, json, markdown, org, taskpaper. The outline formats
+    print projects and areas as headings; add --recursive to include todos.
+
   --no-header
     Suppress the header row.
 
@@ -158,6 +243,10 @@ OPTIONS
   --json
     Output JSON.
 
+  --format=FORMAT
+    Output format: table, json, markdown, org, taskpaper. The outline formats
+    print projects and areas as headings; add --recursive to include todos.
+
   --no-header
     Suppress the header row.
 
@@ -193,6 +282,10 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --tree
+    Show the tag hierarchy, indented under parent tags, with each tag's own
+    usage and the total including its descendants.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -218,19 +311,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -263,7 +366,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -272,7 +375,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -283,6 +386,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -308,19 +422,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -353,7 +477,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -362,7 +486,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -373,6 +497,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -398,19 +533,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -443,7 +588,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -452,7 +597,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -463,6 +608,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -488,19 +644,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -533,7 +699,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -542,7 +708,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -553,6 +719,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -578,19 +755,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -623,7 +810,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -632,7 +819,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -643,6 +830,28 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
+  --show-rule
+    Decode each task's repeat rule and show it in a RULE column (and as
+    {{BT}}repeat_rule{{BT}} in JSON), e.g. "every 2 weeks on Monday, after
+    completion, until 2027-01-01".
+
+  --next=N
+    Show the next N dates each template will produce in a NEXT column (and
+    as {{BT}}occurrences{{BT}} in JSON). Honors the rule's end date; with
+    repeating deadlines each date shows when the copy appears and when it is
+    due. After-completion rules assume each copy is completed on its date.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -667,19 +876,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -712,7 +931,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -722,7 +941,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -733,6 +952,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -757,19 +987,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -802,7 +1042,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -812,7 +1052,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -823,6 +1063,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -848,19 +1099,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: any.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -893,7 +1154,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -902,7 +1163,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -913,6 +1174,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -938,19 +1210,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: any.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -983,7 +1265,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -993,7 +1275,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1004,6 +1286,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1028,19 +1321,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: any.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -1073,7 +1376,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -1083,7 +1386,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1094,6 +1397,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1118,19 +1432,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: completed.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -1163,7 +1487,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -1173,7 +1497,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1184,6 +1508,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1208,19 +1543,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: canceled.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -1253,7 +1598,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -1263,7 +1608,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1274,6 +1619,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1298,19 +1654,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: any.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -1343,7 +1709,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -1353,7 +1719,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1364,6 +1730,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1388,19 +1765,29 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --search=TEXT
     Case-insensitive substring match on title or notes.
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -1433,7 +1820,7 @@ OPTIONS
     Sort by fields (e.g. created,-deadline,title).
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
 
   --include-trashed
@@ -1443,7 +1830,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1454,6 +1841,17 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1479,15 +1877,26 @@ OPTIONS
     Limit number of results (0 = no limit). Default: 200.
 
   --recursive
-    Include checklist items in JSON output.
-
+    Include checklist items in JSON and outline output.
 
   --json
     Output JSON.
 
+  --format=FORMAT
+    Output format: table, json, markdown, org, taskpaper. The outline formats
+    print each section as a top-level heading.
+
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task of the Inbox through Logbook sections with a Go
+    text/template, as in {{BT}}things help tasks{{BT}}. The No Area and Areas
+    sections are left out. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1591,6 +2000,10 @@ OPTIONS
   --repeat-until=DATE
     Stop repeating after the given date (YYYY-MM-DD). Optional.
 
+  --repeat-on=DAYS|POSITION
+    Weekly: a weekday list such as mon,wed,fri. Monthly: an nth weekday such
+    as 2nd-tue or last-fri, or last-day. Defaults to the anchor date.
+
   --repeat-deadline=DAYS
     Add repeating deadlines; each copy appears in Today DAYS earlier.
 
@@ -1647,7 +2060,15 @@ DESCRIPTION
   If {{BT}}-{{BT}} is given as a title, it is read from STDIN. When titles have
   multiple lines of text, the first is set as the area's title.
 
+  The new area's ID is looked up in the database and logged, so
+  {{BT}}things undo{{BT}} deletes that area and never another one with the
+  same title. Without a readable database the action cannot be undone.
+
 OPTIONS
+  --db=PATH
+    Path to the Things database, used to record the action for
+    {{BT}}things undo{{BT}}. Overrides the THINGSDB environment variable.
+
   --tags=TAG1[,TAG2,TAG3...]
     Comma separated strings corresponding to the titles of tags. Optional.
 
@@ -1679,6 +2100,10 @@ DESCRIPTION
   precedence over the {{BT}}--notes={{BT}} option.
 
 OPTIONS
+  --db=PATH
+    Path to the Things database, used to apply repeat rules. Overrides the
+    THINGSDB environment variable.
+
   --area-id=AREAID
     The ID of an area to add to. Takes precedence over area. Optional.
 
@@ -1723,7 +2148,7 @@ OPTIONS
     specified. Optional.
 
   --repeat=UNIT
-    Create a repeating template. Units: day, week, month, year.
+    Create a repeating project template. Units: day, week, month, year.
 
   --repeat-mode=MODE
     Repeat mode: after-completion (default) or schedule.
@@ -1737,6 +2162,10 @@ OPTIONS
   --repeat-until=DATE
     Stop repeating after the given date (YYYY-MM-DD). Optional.
 
+  --repeat-on=DAYS|POSITION
+    Weekly: a weekday list such as mon,wed,fri. Monthly: an nth weekday such
+    as 2nd-tue or last-fri, or last-day. Defaults to the anchor date.
+
   --repeat-deadline=DAYS
     Add repeating deadlines; each copy appears in Today DAYS earlier.
 
@@ -1747,8 +2176,19 @@ OPTIONS
   --allow-unsafe-title
     Allow titles that look like flag assignments (for example, "tag=work").
 
+REPEATING PROJECTS
+  With {{BT}}--repeat{{BT}} the project is created through the URL scheme and the
+  rule is then written to the database (Full Disk Access required). The
+  project becomes the repeating template. Things creates each instance
+  itself, as a new project linked back to the template, and fills it from the
+  template; things3-cli never creates instances or copies headings and todos.
+  Edit the template to change what later instances contain.
+
 EXAMPLES
   things add-project "Take over the world"
+
+  things add-project "Monthly close" --repeat=month --repeat-on=last-day \
+    --repeat-mode=schedule --todo="Reconcile accounts" --todo="Send invoices"
 `
 
 const showHelp = `Usage: things show [OPTIONS...] [--] [-|QUERY]
@@ -1761,9 +2201,13 @@ SYNOPSIS
   things show [--] <-|QUERY>
 
 DESCRIPTION
-  Looks up a single item in the local Things database. If a query is
-  provided, it must match exactly (case-insensitive) and return a single
-  result. Use {{BT}}things search{{BT}} for partial matching.
+  Looks up a single item in the local Things database. QUERY is matched
+  against titles: an exact (case-insensitive) title wins, and otherwise open
+  items are matched by prefix, by the start of each word, or within a typo or
+  two. When several items fit equally well, you choose one from a numbered
+  list if STDIN is a terminal; otherwise the command fails and lists the
+  candidates with their IDs. Use {{BT}}things search{{BT}} to list every
+  partial match.
 
   If {{BT}}-{{BT}} is given as a query, it is read from STDIN.
 
@@ -1781,6 +2225,12 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --occurrences=N
+    For a repeating todo or project (or an instance of one), list the next N
+    dates its template will produce, with deadlines when the rule adds them.
+    Stops at the rule's end date. After-completion rules are estimated by
+    assuming each copy is completed on the day it starts.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
@@ -1791,6 +2241,8 @@ EXAMPLES
   things show "Project One"
 
   echo "Home" | things show -
+
+  things show --id=1234567890AB --occurrences=5
 `
 
 const searchHelp = `Usage: things search [OPTIONS...] [--] <-|QUERY>
@@ -1810,6 +2262,18 @@ DESCRIPTION
 
   If {{BT}}-{{BT}} is given as a query, it is read from STDIN.
 
+  With {{BT}}--ranked{{BT}}, QUERY is looked up in a full-text index of todo
+  titles, notes, and checklist items instead. Every word must match, words
+  match their stems ("meeting" finds "meetings"), and a trailing {{BT}}*{{BT}}
+  matches a prefix. Results are ordered by BM25 relevance, with titles
+  weighted above checklist items and notes, and show a snippet of the best
+  matching passage. Other filters, including {{BT}}--query{{BT}}, narrow the
+  ranked matches.
+
+  The index is a separate SQLite file outside the Things container. It is
+  built on the first ranked search and afterwards only re-reads todos whose
+  modification date changed, so later searches stay fast.
+
 OPTIONS
   --db=PATH
     Path to the Things database. Overrides the THINGSDB environment variable.
@@ -1818,16 +2282,35 @@ OPTIONS
     Filter by status: incomplete, completed, canceled, any. Default: incomplete.
 
   --project=PROJECT
-    Filter by project title or ID.
+    Filter by project title (exact or partial) or ID.
 
   --area=AREA
-    Filter by area title or ID.
+    Filter by area title (exact or partial) or ID.
 
   --tag=TAG
-    Filter by tag title or ID.
+    Filter by tag title (exact or partial) or ID.
+
+  --ranked
+    Search the full-text index and order results by relevance. The default
+    table fields become uuid, title, project, and snippet; matched terms are
+    bold on a terminal and [bracketed] otherwise. JSON output adds score and
+    snippet.
+
+  --reindex
+    Rebuild the full-text index from scratch before a ranked search.
+
+  --tag-recursive
+    Also match tasks tagged with any descendant of the tag (applies to
+    {{BT}}--tag{{BT}} and {{BT}}tag:{{BT}} queries).
 
   --query=QUERY
     Rich query with boolean ops, fields, and regex (e.g. title:/regex/ AND tag:work).
+    Supports comparisons such as deadline<+3d, status:completed, and @NAME
+    saved queries (see {{BT}}things help query{{BT}}).
+
+  --explain
+    Print the SQL the listing compiles to, and which steps run in Go,
+    instead of running the command.
 
   --limit=N
     Limit number of results (0 = no limit). Default: 200.
@@ -1857,10 +2340,11 @@ OPTIONS
     Filter tasks with URLs in notes.
 
   --sort=FIELDS
-    Sort by fields (e.g. created,-deadline,title).
+    Sort by fields (e.g. created,-deadline,title). With --ranked, replaces
+    the relevance order.
 
   --recursive
-    Include checklist items in JSON output.
+    Include checklist items in JSON, outline, and template output.
 
   --include-trashed
     Include trashed tasks.
@@ -1869,7 +2353,7 @@ OPTIONS
     Include completed, canceled, and trashed tasks.
 
   --format=FORMAT
-    Output format: table, json, jsonl, csv.
+    Output format: table, json, jsonl, csv, markdown, org, taskpaper.
 
   --select=FIELDS
     Select fields (comma-separated).
@@ -1880,13 +2364,33 @@ OPTIONS
   --no-header
     Suppress the header row.
 
+  --template=TEMPLATE
+    Render each task with a Go text/template, one task per line, e.g.
+    {{BT}}--template '{{.Title}} ({{.Deadline | default "none"}})'{{BT}}.
+    Fields are those of the JSON output in Go form (.Title, .Deadline, .Tags,
+    .Checklist, ...).
+    Helpers: default, date LAYOUT, relative, join SEP, truncate N, upper,
+    lower, status. .Checklist is loaded when the template uses it.
+
+  --template-file=PATH
+    Read the --template text from a file.
+
 NOTES
   The database lives in the Things app sandbox. You may need to grant your
   terminal Full Disk Access to read it.
 
+ENVIRONMENT
+  THINGS_SEARCH_INDEX
+    Path of the full-text index file. Default: things3-cli/search-index.sqlite
+    in the user cache directory (~/Library/Caches on macOS).
+
 EXAMPLES
   things search "Work"
 
+  things search --ranked "quarterly budget"
+
+  things search --ranked --all --query "project:Meetings" "roadmap*"
+
   echo "Home" | things search -
 `
 
@@ -1904,6 +2408,11 @@ DESCRIPTION
   matching todos. Use {{BT}}--dry-run{{BT}} to preview and {{BT}}--yes{{BT}}
   to confirm bulk updates.
 
+  With {{BT}}--plan=FILE{{BT}}, nothing is changed. Instead the matching todos,
+  their current state, and their intended state are written to FILE and a
+  field-level diff is printed. Review the plan and run
+  {{BT}}things apply FILE{{BT}} to perform the update.
+
   Repeating schedules are updated via the Things database and require
   {{BT}}--id{{BT}} (bulk updates are not supported).
 
@@ -1939,6 +2448,10 @@ OPTIONS
   --yes
     Confirm bulk update.
 
+  --plan=FILE
+    Write a reviewable update plan to FILE (or STDOUT for {{BT}}-{{BT}}) instead
+    of updating. Apply it later with {{BT}}things apply FILE{{BT}}.
+
   --allow-unsafe-title
     Allow titles that look like flag assignments (for example, "tag=work").
 
@@ -2020,6 +2533,10 @@ OPTIONS
     the todo is not in a project with the specified heading. Can be used
     together with list or list-id.
 
+  --heading-id=ID
+    The ID of a heading to move the todo under. Takes precedence over
+    {{BT}}--heading={{BT}}. See {{BT}}things headings{{BT}} for IDs.
+
   --list=LIST
     The title of a project or area to move the todo into. Ignored if
     {{BT}}--list-id={{BT}} is present.
@@ -2057,6 +2574,10 @@ OPTIONS
   --repeat-until=DATE
     Stop repeating after the given date (YYYY-MM-DD). Optional.
 
+  --repeat-on=DAYS|POSITION
+    Weekly: a weekday list such as mon,wed,fri. Monthly: an nth weekday such
+    as 2nd-tue or last-fri, or last-day. Defaults to the anchor date.
+
   --repeat-deadline=DAYS
     Add repeating deadlines; each copy appears in Today DAYS earlier.
 
@@ -2135,23 +2656,104 @@ EXAMPLES
 const undoHelp = `Usage: things undo [OPTIONS...]
 
 NAME
-  things undo - undo the last bulk action
+  things undo - undo a logged action
 
 SYNOPSIS
   things undo [OPTIONS...]
 
 DESCRIPTION
-  Replays the last bulk update or trash action recorded by things3-cli.
-  Undoing updates requires a Things URL scheme token. Undoing trash recreates
-  tasks as new items.
+  Reverts an action recorded by things3-cli. Without {{BT}}--id{{BT}}, the most
+  recent action that has not been undone is reverted. See
+  {{BT}}things history{{BT}} for entry IDs and {{BT}}things redo{{BT}} to re-apply
+  an undone action.
+
+  Logged actions: update, update-project, bulk delete (trash), restore,
+  complete, cancel, reopen, add, add-project, add-area, update-area,
+  delete-area, apply, and repeat rule changes.
+
+  Before reverting, the current database state is compared with what the
+  action left behind. If an item was edited since, undo refuses to run
+  unless {{BT}}--force{{BT}} is given.
+
+  Undoing updates requires a Things URL scheme token; todos the update
+  completed are reopened via AppleScript. Undoing trash moves the
+  original todos out of Trash (see {{BT}}things restore{{BT}}), so their IDs,
+  checklists, and repeat rules are kept. Undoing add moves the created item
+  to Trash. Undoing delete-area recreates an empty area.
 
 OPTIONS
+  --id=ENTRY
+    The ID of the logged action to undo. Default: the most recent one.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
   --auth-token=TOKEN
     The Things URL scheme authorization token. If not provided, uses
     THINGS_AUTH_TOKEN.
 
   --yes
     Confirm undo for multiple tasks.
+
+  --force
+    Undo even if items changed since the action was logged.
+`
+
+const redoHelp = `Usage: things redo [OPTIONS...]
+
+NAME
+  things redo - redo an undone action
+
+SYNOPSIS
+  things redo [OPTIONS...]
+
+DESCRIPTION
+  Re-applies an action that was reverted with {{BT}}things undo{{BT}}. Without
+  {{BT}}--id{{BT}}, the most recently undone action is redone.
+
+  Before re-applying, the current database state is compared with the state
+  the undo restored. If an item was edited since, redo refuses to run unless
+  {{BT}}--force{{BT}} is given. Redoing add creates the item again.
+
+OPTIONS
+  --id=ENTRY
+    The ID of the logged action to redo. Default: the most recently undone.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --auth-token=TOKEN
+    The Things URL scheme authorization token. If not provided, uses
+    THINGS_AUTH_TOKEN.
+
+  --yes
+    Confirm redo for multiple tasks.
+
+  --force
+    Redo even if items changed since the action was undone.
+`
+
+const historyHelp = `Usage: things history [OPTIONS...]
+
+NAME
+  things history - list logged actions
+
+SYNOPSIS
+  things history [OPTIONS...]
+
+DESCRIPTION
+  Lists actions recorded by things3-cli, newest first, with the IDs used by
+  {{BT}}things undo --id{{BT}} and {{BT}}things redo --id{{BT}}.
+
+OPTIONS
+  --limit=N
+    Limit number of entries. Use 0 for all. Default: 20.
+
+  -j, --json
+    Output JSON.
+
+  --no-header
+    Suppress the header row.
 `
 
 const updateAreaHelp = `Usage: things update-area [OPTIONS...] [--] [-|TITLE]
@@ -2171,6 +2773,10 @@ DESCRIPTION
   from STDIN.
 
 OPTIONS
+  --db=PATH
+    Path to the Things database, used to record the action for
+    {{BT}}things undo{{BT}}. Overrides the THINGSDB environment variable.
+
   --id=ID
     The ID of the area to update. Optional if a title is provided.
 
@@ -2213,6 +2819,10 @@ DESCRIPTION
   from STDIN.
 
 OPTIONS
+  --db=PATH
+    Path to the Things database, used to record the action for
+    {{BT}}things undo{{BT}}. Overrides the THINGSDB environment variable.
+
   --id=ID
     The ID of the area to delete. Optional if a title is provided.
 
@@ -2285,6 +2895,10 @@ AUTHORIZATION
     3. Copy the token (or enable "Allow 'things' CLI to access Things").
 
 OPTIONS
+  --db=PATH
+    Path to the Things database, used to record the action for
+    {{BT}}things undo{{BT}}. Overrides the THINGSDB environment variable.
+
   --auth-token=TOKEN
     The Things URL scheme authorization token. Required. See below for more
     information on authorization. If not provided, uses THINGS_AUTH_TOKEN.
@@ -2367,6 +2981,41 @@ OPTIONS
     Title of a todo to add to the project. Can be specified more than once
     to add multiple todos. Optional.
 
+  --repeat=UNIT
+    Turn the project into a repeating template. Units: day, week, month,
+    year. Requires {{BT}}--id{{BT}}; no auth token is needed when only repeat
+    flags are given.
+
+  --repeat-mode=MODE
+    Repeat mode: after-completion (default) or schedule.
+
+  --repeat-every=N
+    Repeat every N units. Default: 1.
+
+  --repeat-start=DATE
+    Anchor date for the repeat rule (YYYY-MM-DD). Defaults to today.
+
+  --repeat-until=DATE
+    Stop repeating after the given date (YYYY-MM-DD). Optional.
+
+  --repeat-on=DAYS|POSITION
+    Weekly: a weekday list such as mon,wed,fri. Monthly: an nth weekday such
+    as 2nd-tue or last-fri, or last-day. Defaults to the anchor date.
+
+  --repeat-deadline=DAYS
+    Add repeating deadlines; each copy appears in Today DAYS earlier.
+
+  --repeat-clear
+    Remove the repeating schedule.
+
+REPEATING PROJECTS
+  Repeat rules are written directly to the database (Full Disk Access
+  required) and can be undone with {{BT}}things undo{{BT}}. Only the template
+  row is written: Things creates each instance itself, as a new project linked
+  back to the template, and fills it from the template. things3-cli never
+  creates instances or copies headings and todos. Projects containing
+  repeating todos are rejected, since Things does not allow nested repeats.
+
 EXAMPLES
   things update-project --id=8TN1bbz946oBsRBGiQ2XBN "The new project title"
 
@@ -2387,6 +3036,767 @@ EXAMPLES
   things update --id=8TN1bbz946oBsRBGiQ2XBN --reveal
     "Ship this project"
 
+  things update-project --id=8TN1bbz946oBsRBGiQ2XBN --repeat=month \
+    --repeat-every=3 --repeat-mode=schedule
+
 SEE ALSO
   Authorization: https://culturedcode.com/things/support/articles/2803573/#overview-authorization
 `
+
+const importJSONHelp = `Usage: things import-json [OPTIONS...] [-|FILE]
+
+NAME
+  things import-json - create or update items from a Things JSON payload
+
+SYNOPSIS
+  things import-json [OPTIONS...] [-|FILE]
+
+DESCRIPTION
+  Sends a payload to the Things {{BT}}json{{BT}} URL command, which can create
+  projects with headings, todos, and checklist items in a single call, or
+  update existing todos and projects.
+
+  The payload is read from FILE, or from STDIN when FILE is omitted or
+  {{BT}}-{{BT}}. It must be a JSON array of objects (or a single object) in the
+  Things JSON format. Supported object types are {{BT}}to-do{{BT}},
+  {{BT}}project{{BT}}, {{BT}}heading{{BT}} (inside project items), and
+  {{BT}}checklist-item{{BT}} (inside to-do checklist items). Objects with
+  {{BT}}"operation": "update"{{BT}} and an {{BT}}id{{BT}} update existing items.
+
+  Large payloads are split into several URLs. Top-level objects are never
+  split, so a single project must fit within {{BT}}--max-url-length{{BT}}.
+
+AUTHORIZATION
+  Update operations require a Things URL scheme token. Run {{BT}}things auth{{BT}}
+  for setup, set {{BT}}THINGS_AUTH_TOKEN{{BT}}, or pass {{BT}}--auth-token{{BT}}.
+
+OPTIONS
+  --auth-token=TOKEN
+    The Things URL scheme authorization token. Required when the payload
+    contains update operations. If not provided, uses THINGS_AUTH_TOKEN.
+
+  --reveal
+    Whether or not to navigate to and show the first created item.
+    Default: false. Optional.
+
+  --max-url-length=N
+    Maximum length of a single Things URL before the payload is split.
+    Default: 32000.
+
+EXAMPLES
+  things import-json release.json
+
+  cat <<'JSON' | things import-json --dry-run
+  [{"type": "project", "attributes": {"title": "Release 1.2",
+    "items": [{"type": "heading", "attributes": {"title": "Prep"}},
+              {"type": "to-do", "attributes": {"title": "Tag build"}}]}}]
+  JSON
+
+  echo '[{"type": "to-do", "operation": "update", "id": "ABC123",
+    "attributes": {"when": "today"}}]' | things import-json
+
+SEE ALSO
+  https://culturedcode.com/things/support/articles/2803573/#json
+`
+
+const templateHelp = `Usage: things template apply [OPTIONS...] [-|FILE]
+       things template export --id=PROJECT [OPTIONS...]
+
+NAME
+  things template - apply or export project templates
+
+SYNOPSIS
+  things template apply [OPTIONS...] [-|FILE]
+  things template export --id=PROJECT [OPTIONS...]
+
+DESCRIPTION
+  {{BT}}template apply{{BT}} creates a project from a YAML or JSON template,
+  including its headings, todos, and checklist items, using the Things
+  {{BT}}json{{BT}} URL command. The template is read from FILE, or from STDIN
+  when FILE is omitted or {{BT}}-{{BT}}.
+
+  {{BT}}template export{{BT}} captures an existing project from the Things
+  database as a template. Open todos are included; dates are written as
+  offsets relative to {{BT}}--base{{BT}}.
+
+TEMPLATE FORMAT
+  title: "Release {{version}}"
+  vars:
+    version: "1.0"
+  area: Work
+  tags: [release]
+  deadline: +2w
+  todos:
+    - title: Write changelog
+  headings:
+    - title: Prep
+      todos:
+        - title: Tag {{version}}
+          when: +1d
+          checklist: [Build, Sign]
+
+  Keys: title (required), notes, area, area_id, tags, when, deadline,
+  todos, headings, and vars. Todos accept title, notes, tags, when,
+  deadline, and checklist.
+
+VARIABLES
+  {{BT}}{{name}}{{BT}} placeholders in text fields are replaced with values from
+  {{BT}}--var name=value{{BT}}, falling back to defaults under {{BT}}vars{{BT}}.
+  Missing variables are reported as an error.
+
+DATES
+  {{BT}}when{{BT}} and {{BT}}deadline{{BT}} accept Things keywords (today, tomorrow,
+  evening, anytime, someday), YYYY-MM-DD dates, offsets such as +3d, -1w,
+  +2m, or +1y relative to {{BT}}--start{{BT}}, or a date followed by offsets
+  ("2026-03-01 -2d").
+
+APPLY OPTIONS
+  --var=NAME=VALUE
+    Set a template variable. Repeatable.
+
+  --start=DATE
+    Base date for relative offsets. Default: today.
+
+  --area=AREA
+    Override the template area.
+
+  --reveal
+    Whether or not to navigate to and show the created project.
+    Default: false. Optional.
+
+  --max-url-length=N
+    Maximum length of a single Things URL. Default: 32000.
+
+EXPORT OPTIONS
+  --id=PROJECT
+    Project ID or title to export. Required.
+
+  --base=DATE
+    Base date for relative offsets. Default: today.
+
+  --format=FORMAT
+    Output format: yaml or json. Default: yaml.
+
+  -o, --output=FILE
+    Write the template to FILE instead of STDOUT.
+
+  -d, --db=PATH
+    Path to the Things database. Overrides THINGSDB.
+
+EXAMPLES
+  things template apply release.yaml --var version=1.2
+
+  things template apply --dry-run --start 2026-03-01 sprint.yaml
+
+  things template export --id "Release 1.1" -o release.yaml
+`
+
+const applyHelp = `Usage: things apply [OPTIONS...] PLAN
+
+NAME
+  things apply - apply an update plan
+
+SYNOPSIS
+  things apply [OPTIONS...] PLAN
+
+DESCRIPTION
+  Applies a plan written by {{BT}}things update --plan=PLAN{{BT}}.
+
+  Before anything is changed, each task's modification time is compared with
+  the time recorded in the plan. If any task changed since the plan was
+  created, nothing is applied; re-run {{BT}}things update --plan{{BT}} to
+  refresh it. The {{BT}}--when{{BT}} checks of {{BT}}things update{{BT}} run
+  again too, so a plan that moves todos to This Evening is refused once they
+  are no longer scheduled for today (unless it was made with
+  {{BT}}--allow-non-today{{BT}}).
+
+  Use {{BT}}--dry-run{{BT}} to print the field-level diff and the Things URLs
+  without opening them. Applied plans are recorded for {{BT}}things undo{{BT}}.
+
+AUTHORIZATION
+  Applying a plan requires a Things URL scheme token. Run {{BT}}things auth{{BT}}
+  for setup, set {{BT}}THINGS_AUTH_TOKEN{{BT}}, or pass {{BT}}--auth-token{{BT}}.
+
+OPTIONS
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --auth-token=TOKEN
+    The Things URL scheme authorization token. If not provided, uses
+    THINGS_AUTH_TOKEN.
+
+EXAMPLES
+  things update --tag=errand --when=tomorrow --plan=errands.json
+  things apply --dry-run errands.json
+  things apply errands.json
+`
+
+const restoreHelp = `Usage: things restore [OPTIONS...]
+
+NAME
+  things restore - restore trashed todos in place
+
+SYNOPSIS
+  things restore --id=ID [OPTIONS...]
+  things restore [QUERY OPTIONS...] [--yes]
+
+DESCRIPTION
+  Moves todos out of Trash using AppleScript. The original items are kept,
+  so their IDs, creation dates, checklists, and repeat rules survive. Each
+  todo goes back to its previous project or area and to the Inbox, Anytime,
+  or Someday list it was in; scheduled todos are rescheduled to their start
+  date. AppleScript cannot place todos under a heading, so todos that were
+  under one return to the heading's project and a warning names the heading.
+  You may be prompted to grant Things automation permission to your
+  terminal.
+
+  Without {{BT}}--id{{BT}}, query options select trashed todos. Use
+  {{BT}}--dry-run{{BT}} to preview and {{BT}}--yes{{BT}} to restore more than
+  one. Restores are recorded for {{BT}}things undo{{BT}}.
+
+OPTIONS
+  --id=ID
+    The ID of the trashed todo to restore.
+
+  --yes
+    Confirm restoring multiple todos.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --status=STATUS, --project=PROJECT, --area=AREA, --tag=TAG,
+  --search=TEXT, --query=QUERY, --limit=N, ...
+    Query options as in {{BT}}things tasks{{BT}}, applied to trashed todos.
+
+EXAMPLES
+  things restore --id=ABC123
+  things restore --dry-run --search "groceries"
+  things restore --tag errand --yes
+`
+
+const completeHelp = `Usage: things complete [OPTIONS...]
+
+NAME
+  things complete - mark todos completed
+
+SYNOPSIS
+  things complete --id=ID [OPTIONS...]
+  things complete [QUERY OPTIONS...] [--yes]
+
+DESCRIPTION
+  Marks todos completed using AppleScript.
+  You may be prompted to grant Things automation permission to your
+  terminal. No auth token is needed.
+
+  Without {{BT}}--id{{BT}}, query options select todos (default status:
+  incomplete). Todos that already have the target status are skipped. Use
+  {{BT}}--dry-run{{BT}} to preview and {{BT}}--yes{{BT}} to change more than
+  one. Changes are recorded for {{BT}}things undo{{BT}}.
+
+OPTIONS
+  --id=ID
+    The ID of the todo to complete.
+
+  --yes
+    Confirm changing multiple todos.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --status=STATUS, --project=PROJECT, --area=AREA, --tag=TAG,
+  --search=TEXT, --query=QUERY, --limit=N, ...
+    Query options as in {{BT}}things tasks{{BT}}.
+
+EXAMPLES
+  things complete --id=ABC123
+  things complete --project "Groceries" --yes
+`
+
+const cancelHelp = `Usage: things cancel [OPTIONS...]
+
+NAME
+  things cancel - mark todos canceled
+
+SYNOPSIS
+  things cancel --id=ID [OPTIONS...]
+  things cancel [QUERY OPTIONS...] [--yes]
+
+DESCRIPTION
+  Marks todos canceled using AppleScript.
+  You may be prompted to grant Things automation permission to your
+  terminal. No auth token is needed.
+
+  Without {{BT}}--id{{BT}}, query options select todos (default status:
+  incomplete). Todos that already have the target status are skipped. Use
+  {{BT}}--dry-run{{BT}} to preview and {{BT}}--yes{{BT}} to change more than
+  one. Changes are recorded for {{BT}}things undo{{BT}}.
+
+OPTIONS
+  --id=ID
+    The ID of the todo to cancel.
+
+  --yes
+    Confirm changing multiple todos.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --status=STATUS, --project=PROJECT, --area=AREA, --tag=TAG,
+  --search=TEXT, --query=QUERY, --limit=N, ...
+    Query options as in {{BT}}things tasks{{BT}}.
+
+EXAMPLES
+  things cancel --id=ABC123
+  things cancel --tag someday-maybe --dry-run
+`
+
+const reopenHelp = `Usage: things reopen [OPTIONS...]
+
+NAME
+  things reopen - reopen completed or canceled todos
+
+SYNOPSIS
+  things reopen --id=ID [OPTIONS...]
+  things reopen [QUERY OPTIONS...] [--yes]
+
+DESCRIPTION
+  Sets completed or canceled todos back to open using AppleScript. The
+  Things URL scheme cannot do this.
+  You may be prompted to grant Things automation permission to your
+  terminal. No auth token is needed.
+
+  Without {{BT}}--id{{BT}}, query options select todos (default status:
+  any). Todos that already have the target status are skipped. Use
+  {{BT}}--dry-run{{BT}} to preview and {{BT}}--yes{{BT}} to change more than
+  one. Changes are recorded for {{BT}}things undo{{BT}}.
+
+OPTIONS
+  --id=ID
+    The ID of the todo to reopen.
+
+  --yes
+    Confirm changing multiple todos.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --status=STATUS, --project=PROJECT, --area=AREA, --tag=TAG,
+  --search=TEXT, --query=QUERY, --limit=N, ...
+    Query options as in {{BT}}things tasks{{BT}}.
+
+EXAMPLES
+  things reopen --id=ABC123
+  things reopen --status completed --search "invoice" --yes
+`
+
+const addTagHelp = `Usage: things add-tag [OPTIONS...] [-|TITLE]
+
+NAME
+  things add-tag - add new tag
+
+SYNOPSIS
+  things add-tag [OPTIONS...] [-|TITLE]
+
+DESCRIPTION
+  Creates a new tag using AppleScript. You may be prompted to grant Things
+  automation permission to your terminal.
+
+  If {{BT}}-{{BT}} is given as a title, it is read from STDIN.
+
+OPTIONS
+  --parent=TAG
+    Create the tag under this parent tag (by title).
+
+  --shortcut=KEY
+    Keyboard shortcut for the tag.
+
+  --allow-unsafe-title
+    Allow titles that look like flag assignments (e.g. tag=work).
+
+EXAMPLES
+  things add-tag "Errands"
+
+  things add-tag --parent "Errands" --shortcut g "Groceries"
+`
+
+const updateTagHelp = `Usage: things update-tag [OPTIONS...] [--] [-|TITLE]
+
+NAME
+  things update-tag - rename, reparent, or set a shortcut for a tag
+
+SYNOPSIS
+  things update-tag [OPTIONS...] [--] [-|TITLE]
+
+DESCRIPTION
+  Updates an existing tag using AppleScript. You may be prompted to grant
+  Things automation permission to your terminal.
+
+  The tag can be identified by {{BT}}--id={{BT}} or by title from the
+  positional argument/STDIN. Renaming a tag keeps it on every item that uses
+  it.
+
+OPTIONS
+  --id=ID
+    The ID of the tag to update. Optional if a title is provided.
+
+  --title=TITLE
+    New title for the tag.
+
+  --parent=TAG
+    Move the tag under this parent tag (by title).
+
+  --no-parent
+    Move the tag to the top level.
+
+  --shortcut=KEY
+    Keyboard shortcut for the tag.
+
+EXAMPLES
+  things update-tag --title "Calls" "Phone"
+
+  things update-tag --parent "Work" "Meetings"
+
+  things update-tag --id=ABC123 --no-parent
+`
+
+const deleteTagHelp = `Usage: things delete-tag [OPTIONS...] [--] [-|TITLE]
+
+NAME
+  things delete-tag - delete a tag
+
+SYNOPSIS
+  things delete-tag [OPTIONS...] [--] [-|TITLE]
+
+DESCRIPTION
+  Deletes an existing tag using AppleScript. The tag is removed from every
+  item that uses it; see {{BT}}things merge-tags{{BT}} to move those items to
+  another tag instead.
+
+  When running interactively, you will be prompted to confirm the deletion.
+  For non-interactive use, pass {{BT}}--confirm={{BT}} with the tag ID or title.
+
+OPTIONS
+  --id=ID
+    The ID of the tag to delete. Optional if a title is provided.
+
+  --confirm=VALUE
+    Confirm deletion by typing the tag ID or title. Required in
+    non-interactive mode. Optional when prompted.
+
+EXAMPLES
+  things delete-tag "Someday"
+
+  things delete-tag --id=ABC123 --confirm=ABC123
+`
+
+const mergeTagsHelp = `Usage: things merge-tags [OPTIONS...] SRC DST
+
+NAME
+  things merge-tags - merge one tag into another
+
+SYNOPSIS
+  things merge-tags [OPTIONS...] SRC DST
+
+DESCRIPTION
+  Replaces SRC with DST on every todo, project, and area tagged SRC, moves
+  child tags of SRC under DST, and then deletes SRC. Tags are matched by ID
+  or title; tagged items are read from the Things database and updated
+  using AppleScript.
+
+  When DST is nested under SRC, DST first moves up to SRC's parent (or the
+  top level). If it sits deeper than a direct child, the child of SRC it
+  sits under moves up with it instead of under DST.
+
+  Use {{BT}}--dry-run{{BT}} to preview the affected items. When running
+  interactively, you will be prompted to confirm by typing the SRC title.
+  For non-interactive use, pass {{BT}}--confirm={{BT}}.
+
+OPTIONS
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --confirm=TITLE
+    Confirm deleting SRC by typing its title. Required in non-interactive
+    mode. Optional when prompted.
+
+EXAMPLES
+  things merge-tags --dry-run errand Errands
+
+  things merge-tags --confirm=errand errand Errands
+`
+
+const headingsHelp = `Usage: things headings [OPTIONS...]
+
+NAME
+  things headings - list project headings from the Things database
+
+SYNOPSIS
+  things headings [OPTIONS...]
+
+DESCRIPTION
+  Lists headings from the local Things database (read-only), with the number
+  of open todos under each. Archived headings are hidden by default.
+
+  Headings cannot be added to an existing project, renamed, or archived from
+  the command line: the Things AppleScript dictionary has no heading object,
+  and the documented {{BT}}json{{BT}} URL command only creates headings as
+  items of a new project ({{BT}}things import-json{{BT}}, {{BT}}things template
+  apply{{BT}}). Use {{BT}}things move --to-heading{{BT}} to file todos under an
+  existing heading.
+
+OPTIONS
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --project=PROJECT
+    Only list headings of this project (title or ID).
+
+  --archived
+    Include archived headings.
+
+  --json
+    Output JSON.
+
+  --no-header
+    Suppress the header row.
+
+EXAMPLES
+  things headings --project "Renovation"
+`
+
+const moveHelp = `Usage: things move [OPTIONS...]
+
+NAME
+  things move - move todos to a project or heading
+
+SYNOPSIS
+  things move --id=ID [--to-project=PROJECT] [--to-heading=HEADING]
+  things move [QUERY OPTIONS...] [--to-project=PROJECT] [--to-heading=HEADING] [--yes]
+
+DESCRIPTION
+  Moves todos using the Things URL scheme ({{BT}}update{{BT}} with
+  {{BT}}list-id{{BT}} and {{BT}}heading-id{{BT}}), which requires an auth token.
+
+  Headings are resolved by ID, or by title within {{BT}}--to-project{{BT}}
+  (or within each todo's current project when no project is given). Without
+  {{BT}}--id{{BT}}, query options select todos; use {{BT}}--yes{{BT}} to move
+  more than one. Moves are recorded for {{BT}}things undo{{BT}}.
+
+OPTIONS
+  --id=ID
+    The ID of the todo to move.
+
+  --to-project=PROJECT
+    Destination project (title or ID).
+
+  --to-heading=HEADING
+    Destination heading (title or ID).
+
+  --yes
+    Confirm moving multiple todos.
+
+  --auth-token=TOKEN
+    The Things URL scheme authorization token.
+
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --status=STATUS, --project=PROJECT, --area=AREA, --tag=TAG,
+  --search=TEXT, --query=QUERY, --limit=N, ...
+    Query options as in {{BT}}things tasks{{BT}}.
+
+EXAMPLES
+  things move --id=ABC123 --to-heading "Kitchen"
+
+  things move --project "Inbox cleanup" --tag kitchen --to-project "Renovation" --to-heading "Kitchen" --yes
+`
+
+const repeatHelp = `Usage: things repeat pause --id=ID [OPTIONS...]
+       things repeat resume --id=ID [OPTIONS...]
+       things repeat skip --id=ID [OPTIONS...]
+
+NAME
+  things repeat - pause, resume, or skip repeating templates
+
+SYNOPSIS
+  things repeat <pause|resume|skip> --id=ID [OPTIONS...]
+
+DESCRIPTION
+  {{BT}}pause{{BT}} stops a repeating todo or project from creating new
+  instances without removing its rule; {{BT}}resume{{BT}} lets it continue.
+  {{BT}}skip{{BT}} moves the next instance forward by one interval, following
+  the rule's weekdays or monthly position for scheduled repeats, and resets
+  the template's deadline as setting the rule does.
+
+  ID may be the template or one of its instances. Changes are written directly
+  to the Things database (Full Disk Access required) and recorded in the
+  action log, so {{BT}}things undo{{BT}} restores the previous state. Undo
+  refuses when the rule, its next or first instance date, its pause state, or
+  the template's deadline changed since (use {{BT}}--force{{BT}}).
+
+OPTIONS
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --id=ID
+    The ID of the repeating template or one of its instances. Required.
+
+EXAMPLES
+  things repeat pause --id=8TN1bbz946oBsRBGiQ2XBN
+
+  things repeat skip --id=8TN1bbz946oBsRBGiQ2XBN
+
+  things repeating --next 3
+`
+
+const dbHelp = `Usage: things db backup [OPTIONS...]
+       things db backups
+       things db restore-backup [OPTIONS...] [FILE]
+
+NAME
+  things db - back up or restore the Things database
+
+SYNOPSIS
+  things db <backup|backups|restore-backup> [OPTIONS...]
+
+DESCRIPTION
+  Commands that write to the Things database directly (repeat rules,
+  {{BT}}things repeat{{BT}}, and undo/redo of those) save a consistent
+  snapshot with SQLite's {{BT}}VACUUM INTO{{BT}} just before their first
+  write and run {{BT}}PRAGMA integrity_check{{BT}} once the write is done. A
+  failed check makes the command exit non-zero and names the snapshot to
+  restore.
+
+  {{BT}}backup{{BT}} takes a snapshot on demand and prints its path.
+  {{BT}}backups{{BT}} lists snapshots, newest first. {{BT}}restore-backup{{BT}}
+  copies a snapshot over the database using the SQLite backup API; without
+  FILE it restores the newest one. The snapshot is integrity-checked before
+  anything is copied, and the current database is saved as a new snapshot
+  first. Quit Things before restoring so it does not write over the result;
+  {{BT}}restore-backup{{BT}} refuses while Things is running unless
+  {{BT}}--force{{BT}} is given.
+
+  Snapshots live in the backups folder of the things3-cli config directory
+  (~/Library/Application Support/things3-cli/backups, or next to
+  THINGS_CONFIG) and the newest 10 are kept.
+
+OPTIONS
+  --db=PATH
+    Path to the Things database. Overrides the THINGSDB environment variable.
+
+  --yes
+    Confirm overwriting the database (restore-backup). Required unless
+    --dry-run is set.
+
+  --force
+    Restore even while Things is running (restore-backup). Things may write
+    over the restored database, so quit it first when you can.
+
+ENVIRONMENT
+  THINGS_BACKUP_DIR
+    Directory for snapshots.
+
+  THINGS_BACKUP_KEEP
+    Number of snapshots to keep (default 10). Set to 0 to skip automatic
+    snapshots before writes.
+
+EXAMPLES
+  things db backups
+
+  things db restore-backup --dry-run
+
+  things db restore-backup --yes
+`
+
+const queryHelp = `Usage: things query save NAME --query=QUERY [--sort=FIELDS] [--select=FIELDS]
+       things query list [--json]
+       things query run NAME [OPTIONS...]
+       things query delete NAME
+
+NAME
+  things query - save and run named queries
+
+SYNOPSIS
+  things query <save|list|run|delete> [ARGS...]
+
+DESCRIPTION
+  Saves rich {{BT}}--query{{BT}} expressions under a name so they can be reused.
+  Saved queries live in queries.json in the things3-cli config directory
+  (the directory of THINGS_CONFIG when it is set), next to actions.jsonl.
+
+  Any {{BT}}--query{{BT}} (including one being saved) can reference a saved query as
+  an unquoted {{BT}}@NAME{{BT}}, which expands to its expression:
+
+    things query save waiting --query 'tag:waiting OR title:/^waiting/i'
+    things today --query '@waiting AND tag:urgent'
+
+  References may nest; cycles are rejected when saving. In a
+  {{BT}}--query{{BT}}, an @NAME that no saved query has is searched for as text
+  (as "@NAME" in quotes always is) with a warning on stderr. Inside a saved
+  query such a reference is an error, and saving it is refused.
+
+  {{BT}}save{{BT}} stores (or replaces) a query, with an optional default sort and
+  field selection. {{BT}}run{{BT}} lists incomplete todos matching it, like
+  {{BT}}things tasks{{BT}}; a {{BT}}--query{{BT}} passed to run is combined with AND, and
+  {{BT}}--sort{{BT}} or {{BT}}--select{{BT}} override the saved defaults. {{BT}}delete{{BT}} refuses to
+  remove a query other saved queries reference.
+
+QUERY LANGUAGE
+  Terms combine with AND (or juxtaposition), OR, NOT/!, and parentheses.
+  A bare word or "quoted phrase" matches title, notes, tags, project, area,
+  and heading. FIELD:VALUE matches one field:
+
+    title, notes, tag, project, area, heading, id, url
+      Substring match (case-insensitive), or /regex/ with optional i flag.
+      FIELD=VALUE matches the whole value; FIELD!=VALUE negates it.
+
+    repeating:true|false, url:true|false
+
+    status:incomplete|completed|canceled
+      Combine with --status any (or --all) so closed todos are loaded.
+
+    start:inbox|anytime|someday
+      The list a todo starts in.
+
+    checklist:none|any|incomplete|complete
+
+  Date fields (deadline or due, start or start_date, created, modified,
+  stopped or stop_date) take comparisons: FIELD<DATE, <=, >, >=, =, !=, with
+  FIELD:DATE meaning =. DATE is YYYY-MM-DD, "YYYY-MM-DD HH:MM" (quoted),
+  today, yesterday, tomorrow, or an offset from today such as +3d, -2w, +1m,
+  -1y. Dates compare by day unless a time is given. Todos without the date
+  never match a comparison; use FIELD:none or FIELD:any to test presence.
+
+    things tasks --query 'deadline<+7d AND NOT tag:someday'
+    things tasks --status any --query 'status:completed AND stopped>=-1w'
+    things today --query 'created>=-3d OR checklist:incomplete'
+
+  Quote values containing spaces or the characters : < > = ( ).
+
+  Queries are compiled to SQL, so sorting, --offset, and --limit run in the
+  database. Regexes, non-ASCII text, and tag matches with --tag-recursive are
+  matched in Go instead; the rest of the query still narrows the rows SQLite
+  returns. Add --explain to any listing to see the generated SQL.
+
+OPTIONS
+  --query=QUERY
+    Rich query to save (save), or extra conditions (run).
+
+  --sort=FIELDS
+    Default sort for run (save), or the sort to use (run).
+
+  --select=FIELDS
+    Default output fields for run (save), or the fields to print (run).
+
+  --json
+    Output JSON (list).
+
+  run also accepts the filter and output options of {{BT}}things tasks{{BT}}.
+
+EXAMPLES
+  things query save urgent --query 'tag:urgent' --sort deadline --select title,deadline
+
+  things query run urgent --format json
+
+  things query list
+`
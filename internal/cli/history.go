@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCommand builds the history subcommand.
+func NewHistoryCommand(app *App) *cobra.Command {
+	var asJSON bool
+	var noHeader bool
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List logged actions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := readActions()
+			if err != nil {
+				return fmt.Errorf("Error: %s", err)
+			}
+			if limit > 0 && len(entries) > limit {
+				entries = entries[len(entries)-limit:]
+			}
+			// Newest first.
+			for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+				entries[i], entries[j] = entries[j], entries[i]
+			}
+			return printHistory(app.Out, entries, asJSON, noHeader)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Suppress header row")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Limit number of entries (0 = all)")
+
+	return cmd
+}
+
+func printHistory(out io.Writer, entries []ActionEntry, asJSON bool, noHeader bool) error {
+	if asJSON {
+		if entries == nil {
+			entries = []ActionEntry{}
+		}
+		enc := json.NewEncoder(out)
+		return enc.Encode(entries)
+	}
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	if !noHeader {
+		fmt.Fprintln(w, "ID\tTIMESTAMP\tACTION\tITEMS\tSTATE\tSUMMARY")
+	}
+	for _, entry := range entries {
+		state := "done"
+		if entry.Undone {
+			state = "undone"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", entry.ID, entry.Timestamp, entry.Type, len(entry.Items), state, actionSummary(entry))
+	}
+	return w.Flush()
+}
+
+func actionSummary(entry ActionEntry) string {
+	if len(entry.Items) == 0 {
+		return ""
+	}
+	summary := entry.Items[0].Title
+	if len(entry.Items) > 1 {
+		summary = fmt.Sprintf("%s (+%d more)", summary, len(entry.Items)-1)
+	}
+	return summary
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewImportJSONCommand builds the import-json subcommand.
+func NewImportJSONCommand(app *App) *cobra.Command {
+	opts := things.JSONOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "import-json [OPTIONS...] [-|FILE]",
+		Short: "Create or update items from a Things JSON payload",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readJSONPayload(app.In, args)
+			if err != nil {
+				return err
+			}
+			items, err := things.ParseJSONItems(data)
+			if err != nil {
+				return err
+			}
+			if err := things.ValidateJSONItems(items); err != nil {
+				return err
+			}
+			if things.JSONItemsNeedAuth(items) {
+				token, err := resolveAuthToken(app, opts.AuthToken)
+				if err != nil {
+					return err
+				}
+				opts.AuthToken = token
+			}
+
+			urls, err := things.BuildJSONURLs(opts, items)
+			if err != nil {
+				return err
+			}
+			if len(urls) > 1 && app.Debug {
+				fmt.Fprintf(app.Err, "Note: payload split across %d URLs\n", len(urls))
+			}
+			return openURLs(app, urls)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.AuthToken, "auth-token", "", "Things URL scheme authorization token (required for update operations)")
+	flags.BoolVar(&opts.Reveal, "reveal", false, "Reveal the first created item")
+	flags.IntVar(&opts.MaxURLLength, "max-url-length", things.DefaultJSONMaxURLLength, "Split payloads into URLs no longer than this")
+
+	return cmd
+}
+
+func readJSONPayload(in io.Reader, args []string) ([]byte, error) {
+	if len(args) == 0 || args[0] == "-" {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("Error: read %s: %v", args[0], err)
+	}
+	return data, nil
+}
+
+func openURLs(app *App, urls []string) error {
+	for _, url := range urls {
+		if err := openURL(app, url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
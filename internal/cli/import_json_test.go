@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestImportJSONCommandFromStdin(t *testing.T) {
+	launcher := &recordLauncher{}
+	app := &App{
+		In:       strings.NewReader(`[{"type":"project","attributes":{"title":"Launch","items":[{"type":"heading","attributes":{"title":"Prep"}}]}}]`),
+		Out:      &bytes.Buffer{},
+		Err:      &bytes.Buffer{},
+		Launcher: launcher,
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"import-json"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+
+	url := requireOpenURL(t, launcher)
+	if !strings.HasPrefix(url, "things:///json?data=") {
+		t.Fatalf("expected json url, got %q", url)
+	}
+	if !strings.Contains(url, "Launch") {
+		t.Fatalf("expected project title in url, got %q", url)
+	}
+}
+
+func TestImportJSONCommandUpdateRequiresAuthToken(t *testing.T) {
+	t.Setenv("THINGS_AUTH_TOKEN", "")
+	launcher := &recordLauncher{}
+	app := &App{
+		In:       strings.NewReader(`{"type":"to-do","operation":"update","id":"ABC","attributes":{"when":"today"}}`),
+		Out:      &bytes.Buffer{},
+		Err:      &bytes.Buffer{},
+		Launcher: launcher,
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"import-json", "-"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	if err := root.Execute(); err == nil {
+		t.Fatalf("expected error")
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no open invocation")
+	}
+}
+
+func TestImportJSONCommandDryRunSplitsAndRedacts(t *testing.T) {
+	items := make([]string, 0, 6)
+	for i := 0; i < 6; i++ {
+		items = append(items, `{"type":"to-do","operation":"update","id":"ID`+strings.Repeat("x", 60)+`","attributes":{"when":"today"}}`)
+	}
+	path := filepath.Join(t.TempDir(), "payload.json")
+	if err := os.WriteFile(path, []byte("["+strings.Join(items, ",")+"]"), 0o600); err != nil {
+		t.Fatalf("write payload: %v", err)
+	}
+	out := &bytes.Buffer{}
+	launcher := &recordLauncher{}
+	app := &App{
+		In:       strings.NewReader(""),
+		Out:      out,
+		Err:      &bytes.Buffer{},
+		Launcher: launcher,
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"import-json", "--dry-run", "--auth-token", "secret", "--max-url-length", "600", path})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no open invocation in dry-run")
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected payload split across urls, got %q", out.String())
+	}
+	if strings.Contains(out.String(), "secret") {
+		t.Fatalf("expected auth token to be redacted, got %q", out.String())
+	}
+}
@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"fmt"
+
 	"github.com/ossianhempel/things3-cli/internal/db"
 	"github.com/spf13/cobra"
 )
@@ -40,6 +42,9 @@ func NewRepeatingCommand(app *App) *cobra.Command {
 	var selectRaw string
 	var asJSON bool
 	var noHeader bool
+	var templateFlags taskTemplateFlags
+	var showRule bool
+	var next int
 
 	cmd := &cobra.Command{
 		Use:   "repeating",
@@ -52,14 +57,31 @@ func NewRepeatingCommand(app *App) *cobra.Command {
 			defer store.Close()
 
 			opts.HasURLSet = cmd.Flags().Changed("has-url")
-			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader)
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
 			if err != nil {
 				return err
 			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
 			tasks, err := fetchTasks(store, store.Tasks, opts, false, []int{db.TaskTypeTodo})
 			if err != nil {
 				return formatDBError(err)
 			}
+			if next < 0 {
+				return fmt.Errorf("Error: --next must be >= 0")
+			}
+			if showRule || next > 0 {
+				if err := annotateRepeatRules(store, tasks, showRule, next); err != nil {
+					return formatDBError(err)
+				}
+			}
+			if showRule {
+				outputOpts = withTaskField(outputOpts, "repeat_rule")
+			}
+			if next > 0 {
+				outputOpts = withTaskField(outputOpts, "occurrences")
+			}
 			return printTasks(app.Out, tasks, outputOpts)
 		},
 	}
@@ -67,7 +89,9 @@ func NewRepeatingCommand(app *App) *cobra.Command {
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	addTaskQueryFlags(cmd, &opts, true, true)
-	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
+	cmd.Flags().BoolVar(&showRule, "show-rule", false, "Show the decoded repeat rule for each task")
+	cmd.Flags().IntVar(&next, "next", 0, "Show the next N occurrences of each task")
 
 	return cmd
 }
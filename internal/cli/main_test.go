@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain keeps the action log written by commands under test out of the
+// real config directory.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "things3-cli-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", dir)
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
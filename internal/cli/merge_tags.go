@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewMergeTagsCommand builds the merge-tags subcommand.
+func NewMergeTagsCommand(app *App) *cobra.Command {
+	var dbPath string
+	var confirm string
+
+	cmd := &cobra.Command{
+		Use:   "merge-tags [OPTIONS...] SRC DST",
+		Short: "Merge one tag into another",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			source, err := store.TagByInput(args[0])
+			if err != nil {
+				return formatDBError(err)
+			}
+			dest, err := store.TagByInput(args[1])
+			if err != nil {
+				return formatDBError(err)
+			}
+			if source.UUID == dest.UUID {
+				return fmt.Errorf("Error: cannot merge a tag into itself")
+			}
+			items, err := store.TaggedItems(source.UUID)
+			if err != nil {
+				return formatDBError(err)
+			}
+			tags, err := store.Tags()
+			if err != nil {
+				return formatDBError(err)
+			}
+
+			opts := things.MergeTagsOptions{
+				Source:      source.Title,
+				Destination: dest.Title,
+				Items:       make([]things.TaggedItem, 0, len(items)),
+			}
+			for _, item := range items {
+				kind := things.TaggedToDo
+				switch item.Type {
+				case "project":
+					kind = things.TaggedProject
+				case "area":
+					kind = things.TaggedArea
+				}
+				opts.Items = append(opts.Items, things.TaggedItem{Kind: kind, ID: item.UUID, Tags: item.Tags})
+			}
+			// A DST nested under SRC moves up to SRC's parent rather than
+			// under itself, together with the child of SRC it sits under.
+			byID := make(map[string]db.Tag, len(tags))
+			for _, tag := range tags {
+				byID[tag.UUID] = tag
+			}
+			for id, seen := dest.UUID, map[string]bool{}; id != "" && !seen[id]; id = byID[id].ParentID {
+				seen[id] = true
+				if byID[id].ParentID == source.UUID {
+					opts.DestinationIsChild = true
+					if id != dest.UUID {
+						opts.DestinationAncestor = byID[id].Title
+					}
+					break
+				}
+			}
+			for _, tag := range tags {
+				switch {
+				case tag.UUID == source.ParentID:
+					opts.SourceParent = tag.Title
+				case tag.ParentID == source.UUID:
+					opts.Children = append(opts.Children, tag.Title)
+				}
+			}
+
+			if app.DryRun {
+				if err := previewTagMerge(app.Out, opts, items); err != nil {
+					return err
+				}
+			}
+			if err := confirmDelete(app, "tag", source.Title, confirm); err != nil {
+				return err
+			}
+
+			script, err := things.BuildMergeTagsScript(opts)
+			if err != nil {
+				return err
+			}
+			return runScript(app, script)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
+	flags.StringVar(&confirm, "confirm", "", "Confirm deleting SRC by typing its title")
+
+	return cmd
+}
+
+func previewTagMerge(out io.Writer, opts things.MergeTagsOptions, items []db.TaggedItem) error {
+	source, dest := opts.Source, opts.Destination
+	fmt.Fprintf(out, "Would retag %d item(s) from %q to %q and delete %q.\n", len(items), source, dest, source)
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	for _, item := range items {
+		fmt.Fprintf(w, "  %s\t%s\t%s\n", item.Type, item.UUID, item.Title)
+	}
+	if opts.DestinationIsChild {
+		parent := "the top level"
+		if opts.SourceParent != "" {
+			parent = opts.SourceParent
+		}
+		fmt.Fprintf(w, "  tag\t%s\tmoved under %s\n", dest, parent)
+		if opts.DestinationAncestor != "" {
+			fmt.Fprintf(w, "  tag\t%s\tmoved under %s\n", opts.DestinationAncestor, parent)
+		}
+	}
+	for _, child := range opts.Children {
+		if child == dest || child == opts.DestinationAncestor {
+			continue
+		}
+		fmt.Fprintf(w, "  tag\t%s\tmoved under %s\n", child, dest)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	return nil
+}
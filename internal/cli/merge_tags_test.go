@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func writeMergeTagsDB(t *testing.T) string {
+	t.Helper()
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+	for _, stmt := range []string{
+		`INSERT INTO TMTag (uuid, title) VALUES ('TAG2', 'Priority');`,
+		`INSERT INTO TMTag (uuid, title, parent) VALUES ('TAG3', 'very urgent', 'TAG1');`,
+		`INSERT INTO TMTaskTag (tasks, tags) VALUES ('P1', 'TAG1');`,
+	} {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	return dbPath
+}
+
+func TestMergeTagsDryRunPreviews(t *testing.T) {
+	dbPath := writeMergeTagsDB(t)
+	runner := &recordScriptRunner{}
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"--dry-run", "merge-tags", "--db", dbPath, "urgent", "Priority"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if runner.script != "" {
+		t.Fatalf("expected no script execution in dry-run")
+	}
+	text := out.String()
+	for _, want := range []string{
+		"Would retag 2 item(s)",
+		"Task One",
+		"Project One",
+		"set tag names of to do id \"T1\" to \"Priority\"",
+		"set tag names of project id \"P1\" to \"Priority\"",
+		"set parent tag of tag \"very urgent\" to tag \"Priority\"",
+		"delete tag \"urgent\"",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output:\n%s", want, text)
+		}
+	}
+}
+
+func TestMergeTagsRequiresConfirm(t *testing.T) {
+	dbPath := writeMergeTagsDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"merge-tags", "--db", dbPath, "urgent", "Priority"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--confirm=urgent") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+
+	root = NewRoot(app)
+	root.SetArgs([]string{"merge-tags", "--db", dbPath, "--confirm", "urgent", "urgent", "Priority"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if script := requireScript(t, runner); !strings.Contains(script, "delete tag \"urgent\"") {
+		t.Fatalf("expected delete in script, got %q", script)
+	}
+}
+
+func TestMergeTagsIntoChildMovesItUp(t *testing.T) {
+	dbPath := writeMergeTagsDB(t)
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}, Scripter: &recordScriptRunner{}}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"--dry-run", "merge-tags", "--db", dbPath, "urgent", "very urgent"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	text := out.String()
+	if !strings.Contains(text, "very urgent  moved under the top level") || !strings.Contains(text, "set parent tag of tag \"very urgent\" to missing value") {
+		t.Fatalf("expected DST moved to the top level:\n%s", text)
+	}
+	if strings.Contains(text, "set parent tag of tag \"very urgent\" to tag") {
+		t.Fatalf("DST must not be reparented under itself:\n%s", text)
+	}
+}
+
+func TestMergeTagsIntoGrandchildAvoidsCycle(t *testing.T) {
+	dbPath := writeMergeTagsDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	// urgent > very urgent > critical
+	if _, err := conn.Exec(`INSERT INTO TMTag (uuid, title, parent) VALUES ('TAG4', 'critical', 'TAG3');`); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	conn.Close()
+
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}, Scripter: &recordScriptRunner{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"--dry-run", "merge-tags", "--db", dbPath, "urgent", "critical"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"set parent tag of tag \"critical\" to missing value\n  set parent tag of tag \"very urgent\" to missing value",
+		"critical     moved under the top level",
+		"very urgent  moved under the top level",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "set parent tag of tag \"very urgent\" to tag \"critical\"") {
+		t.Fatalf("the tag DST came through must not move under DST:\n%s", text)
+	}
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewMoveCommand builds the move subcommand.
+func NewMoveCommand(app *App) *cobra.Command {
+	var dbPath string
+	var id string
+	var authToken string
+	var toProject string
+	var toHeading string
+	var yes bool
+	opts := TaskQueryOptions{
+		Status:     "incomplete",
+		Limit:      200,
+		ExactMatch: true,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "move [OPTIONS...]",
+		Short: "Move todos to a project or heading",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changedStatus := cmd.Flags().Changed("status")
+			opts.HasURLSet = cmd.Flags().Changed("has-url")
+			hasSelector := hasExplicitSelector(map[string]bool{"status": changedStatus}, opts)
+			if strings.TrimSpace(id) != "" && hasSelector {
+				return fmt.Errorf("Error: use either --id or query filters")
+			}
+			if strings.TrimSpace(id) == "" && !hasSelector {
+				return fmt.Errorf("Error: Must specify --id=ID or query filters")
+			}
+			if strings.TrimSpace(toProject) == "" && strings.TrimSpace(toHeading) == "" {
+				return fmt.Errorf("Error: Must specify --to-project or --to-heading")
+			}
+
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			var tasks []db.Task
+			if strings.TrimSpace(id) != "" {
+				task, err := store.TaskByID(id)
+				if err != nil {
+					return formatDBError(err)
+				}
+				tasks = []db.Task{*task}
+			} else {
+				tasks, err = fetchTasks(store, store.Tasks, opts, false, []int{db.TaskTypeTodo})
+				if err != nil {
+					return formatDBError(err)
+				}
+			}
+			if len(tasks) == 0 {
+				return fmt.Errorf("Error: no tasks matched")
+			}
+			if len(tasks) > 1 && !yes && !app.DryRun {
+				return fmt.Errorf("Error: %d tasks matched (rerun with --yes to apply)", len(tasks))
+			}
+
+			projectID := ""
+			if strings.TrimSpace(toProject) != "" {
+				projectID, err = store.ResolveProjectID(toProject)
+				projectID, err = resolvePicked(app.In, app.Err, projectID, err)
+				if err != nil {
+					return fmt.Errorf("Error: %s", err)
+				}
+			}
+
+			token, err := resolveAuthToken(app, authToken)
+			if err != nil {
+				return err
+			}
+
+			entry := ActionEntry{
+				Type:  ActionUpdate,
+				Items: make([]ActionItem, 0, len(tasks)),
+			}
+			urls := make([]string, 0, len(tasks))
+			headingIDs := map[string]string{}
+			for _, task := range tasks {
+				update := things.UpdateOptions{AuthToken: token, ID: task.UUID, ListID: projectID}
+				if strings.TrimSpace(toHeading) != "" {
+					// Todos under a heading have no project of their own;
+					// fall back to the heading's project.
+					target := projectID
+					if target == "" {
+						target = task.ProjectID
+					}
+					if target == "" && task.HeadingID != "" {
+						target, err = store.HeadingProjectID(task.HeadingID)
+						if err != nil {
+							return formatDBError(err)
+						}
+					}
+					headingID, ok := headingIDs[target]
+					if !ok {
+						headingID, err = store.ResolveHeadingID(target, toHeading)
+						if err != nil {
+							return fmt.Errorf("Error: %s (id %s)", err, task.UUID)
+						}
+						headingIDs[target] = headingID
+					}
+					update.HeadingID = headingID
+				}
+				url, err := things.BuildUpdateURL(update, "")
+				if err != nil {
+					return err
+				}
+				urls = append(urls, url)
+				expected := update
+				expected.Heading = strings.TrimSpace(toHeading)
+				entry.Items = append(entry.Items, updateActionItem(task, expected, ""))
+				entry.Redo = append(entry.Redo, urlStep(url))
+			}
+			logAction(app, entry)
+
+			return openURLs(app, urls)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
+	flags.StringVar(&id, "id", "", "ID of the todo to move")
+	flags.StringVar(&authToken, "auth-token", "", "Things URL scheme authorization token")
+	flags.StringVar(&toProject, "to-project", "", "Destination project (title or ID)")
+	flags.StringVar(&toHeading, "to-heading", "", "Destination heading (title within the project, or ID)")
+	flags.BoolVar(&yes, "yes", false, "Confirm moving multiple todos")
+	addTaskQueryFlags(cmd, &opts, true, true)
+
+	return cmd
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMoveToHeadingResolvesWithinProject(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	launcher := &recordLauncher{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"move", "--db", dbPath, "--auth-token", "tok", "--id", "T1", "--to-heading", "heading"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	url := requireOpenURL(t, launcher)
+	if !strings.Contains(url, "heading-id=H1") || !strings.Contains(url, "id=T1") {
+		t.Fatalf("unexpected url %q", url)
+	}
+	entries, _ := readActions()
+	if len(entries) != 1 || entries[0].Type != ActionUpdate || entries[0].Items[0].HeadingTitle != "Heading" {
+		t.Fatalf("expected move to be logged, got %+v", entries)
+	}
+}
+
+func TestMoveToProjectAndHeading(t *testing.T) {
+	dbPath := writeTestDB(t)
+	launcher := &recordLauncher{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"move", "--db", dbPath, "--auth-token", "tok", "--id", "INBOX1", "--to-project", "Project One", "--to-heading", "Heading"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("move failed: %v", err)
+	}
+	url := requireOpenURL(t, launcher)
+	if !strings.Contains(url, "list-id=P1") || !strings.Contains(url, "heading-id=H1") {
+		t.Fatalf("unexpected url %q", url)
+	}
+}
+
+func TestMoveUnknownHeadingFails(t *testing.T) {
+	dbPath := writeTestDB(t)
+	launcher := &recordLauncher{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"move", "--db", dbPath, "--auth-token", "tok", "--id", "T1", "--to-heading", "Nope"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "heading not found") {
+		t.Fatalf("expected heading not found, got %v", err)
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no URL to be opened")
+	}
+}
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// Outline formats render tasks as nested text for pasting into notes:
+// GitHub-flavored Markdown checklists, Org-mode headlines, or TaskPaper.
+const (
+	formatMarkdown  = "markdown"
+	formatOrg       = "org"
+	formatTaskPaper = "taskpaper"
+)
+
+func isOutlineFormat(format string) bool {
+	switch format {
+	case formatMarkdown, formatOrg, formatTaskPaper:
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveOutlineFormat validates --format for commands that print tables,
+// JSON, or outlines (projects, areas, all).
+func resolveOutlineFormat(format string, asJSON bool) (string, error) {
+	format = strings.TrimSpace(strings.ToLower(format))
+	if format == "md" {
+		format = formatMarkdown
+	}
+	switch {
+	case format == "":
+		if asJSON {
+			return "json", nil
+		}
+		return "table", nil
+	case asJSON && format != "json":
+		return "", fmt.Errorf("Error: --json cannot be used with --format %s", format)
+	case format == "table" || format == "json" || isOutlineFormat(format):
+		return format, nil
+	}
+	return "", fmt.Errorf("Error: invalid format %q", format)
+}
+
+// outlineNode is a group (area, project, heading, or list section) when
+// Task is nil, and a todo otherwise.
+type outlineNode struct {
+	Title    string
+	Task     *db.Task
+	Children []outlineNode
+}
+
+// taskOutline groups a flat task list by project, then heading, keeping
+// each group where its first task appeared. Things leaves project unset on
+// todos under a heading, so their project comes from the heading. Tasks
+// outside projects and headings stay at the top level.
+func taskOutline(tasks []db.Task) []outlineNode {
+	nodes := []outlineNode{}
+	groups := map[string]int{}
+	headings := map[string]int{}
+	for i := range tasks {
+		task := &tasks[i]
+		leaf := outlineNode{Title: task.Title, Task: task}
+		projectID, projectTitle := task.ProjectID, task.ProjectTitle
+		if projectID == "" {
+			projectID, projectTitle = task.HeadingProjectID, task.HeadingProjectTitle
+		}
+
+		key, title := "project\x00"+projectID, projectTitle
+		if projectID == "" {
+			if task.HeadingID == "" {
+				nodes = append(nodes, leaf)
+				continue
+			}
+			key, title = "heading\x00"+task.HeadingID, task.HeadingTitle
+		}
+		index, ok := groups[key]
+		if !ok {
+			index = len(nodes)
+			groups[key] = index
+			nodes = append(nodes, outlineNode{Title: title})
+		}
+		group := &nodes[index]
+		if projectID == "" || task.HeadingID == "" {
+			group.Children = append(group.Children, leaf)
+			continue
+		}
+
+		heading, ok := headings[task.HeadingID]
+		if !ok {
+			heading = len(group.Children)
+			headings[task.HeadingID] = heading
+			group.Children = append(group.Children, outlineNode{Title: task.HeadingTitle})
+		}
+		group.Children[heading].Children = append(group.Children[heading].Children, leaf)
+	}
+	return nodes
+}
+
+// treeOutline converts the --recursive area/project tree.
+func treeOutline(items []db.TreeItem) []outlineNode {
+	nodes := make([]outlineNode, 0, len(items))
+	for _, item := range items {
+		if item.Type != "to-do" {
+			nodes = append(nodes, outlineNode{Title: item.Title, Children: treeOutline(item.Items)})
+			continue
+		}
+		task := &db.Task{
+			UUID:      item.UUID,
+			Title:     item.Title,
+			StartDate: item.StartDate,
+			Deadline:  item.Deadline,
+			StopDate:  item.StopDate,
+			Tags:      item.Tags,
+			Checklist: item.Checklist,
+		}
+		if item.Status != nil {
+			task.Status = *item.Status
+		}
+		nodes = append(nodes, outlineNode{Title: item.Title, Task: task})
+	}
+	return nodes
+}
+
+// projectTreeItems and areaTreeItems let plain project and area lists
+// print as outlines of bare groups.
+func projectTreeItems(projects []db.Project) []db.TreeItem {
+	items := make([]db.TreeItem, len(projects))
+	for i, project := range projects {
+		items[i] = db.TreeItem{UUID: project.UUID, Type: "project", Title: project.Title}
+	}
+	return items
+}
+
+func areaTreeItems(areas []db.Area) []db.TreeItem {
+	items := make([]db.TreeItem, len(areas))
+	for i, area := range areas {
+		items[i] = db.TreeItem{UUID: area.UUID, Type: "area", Title: area.Title}
+	}
+	return items
+}
+
+func writeOutline(out io.Writer, nodes []outlineNode, format string) error {
+	w := &outlineWriter{out: out, format: format}
+	w.write(nodes, 0)
+	return w.err
+}
+
+type outlineWriter struct {
+	out     io.Writer
+	format  string
+	started bool
+	err     error
+}
+
+func (w *outlineWriter) line(text string) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintln(w.out, text)
+	w.started = true
+}
+
+// write prints a group's todos before its subgroups, so in Markdown a todo
+// never lands under a sibling heading.
+func (w *outlineWriter) write(nodes []outlineNode, depth int) {
+	for _, node := range nodes {
+		if node.Task != nil {
+			w.task(*node.Task, depth)
+		}
+	}
+	for _, node := range nodes {
+		if node.Task == nil {
+			w.group(node, depth)
+		}
+	}
+}
+
+func (w *outlineWriter) group(node outlineNode, depth int) {
+	switch w.format {
+	case formatMarkdown:
+		if w.started {
+			w.line("")
+		}
+		w.line(strings.Repeat("#", min(depth+2, 6)) + " " + node.Title)
+		if hasOutlineTasks(node.Children) {
+			w.line("")
+		}
+	case formatOrg:
+		w.line(strings.Repeat("*", depth+1) + " " + node.Title)
+	case formatTaskPaper:
+		w.line(strings.Repeat("\t", depth) + node.Title + ":")
+	}
+	w.write(node.Children, depth+1)
+}
+
+func hasOutlineTasks(nodes []outlineNode) bool {
+	for _, node := range nodes {
+		if node.Task != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *outlineWriter) task(task db.Task, depth int) {
+	switch w.format {
+	case formatMarkdown:
+		w.markdownTask(task)
+	case formatOrg:
+		w.orgTask(task, depth)
+	case formatTaskPaper:
+		w.taskPaperTask(task, depth)
+	}
+}
+
+// markdownTask writes a GFM task list item; canceled todos are checked and
+// struck through. Todos are not indented under headings, which Markdown
+// expresses with the heading level instead.
+func (w *outlineWriter) markdownTask(task db.Task) {
+	box := "[ ]"
+	title := task.Title
+	switch task.Status {
+	case db.StatusCompleted:
+		box = "[x]"
+	case db.StatusCanceled:
+		box = "[x]"
+		title = "~~" + title + "~~"
+	}
+	details := []string{}
+	if task.StartDate != "" {
+		details = append(details, "start "+task.StartDate)
+	}
+	if task.Deadline != "" {
+		details = append(details, "due "+task.Deadline)
+	}
+	line := "- " + box + " " + title
+	if len(details) > 0 {
+		line += " (" + strings.Join(details, ", ") + ")"
+	}
+	for _, tag := range task.Tags {
+		line += " #" + outlineTag(tag, "-")
+	}
+	w.line(line)
+	for _, item := range task.Checklist {
+		itemBox := "[ ]"
+		if item.Status != db.StatusIncomplete {
+			itemBox = "[x]"
+		}
+		w.line("  - " + itemBox + " " + item.Title)
+	}
+}
+
+// orgTask writes a TODO or DONE headline with its tags, then a planning
+// line with CLOSED, SCHEDULED (start date), and DEADLINE stamps.
+func (w *outlineWriter) orgTask(task db.Task, depth int) {
+	keyword := "TODO"
+	if task.Status != db.StatusIncomplete {
+		keyword = "DONE"
+	}
+	line := strings.Repeat("*", depth+1) + " " + keyword + " " + task.Title
+	if len(task.Tags) > 0 {
+		tags := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			tags[i] = outlineTag(tag, "_")
+		}
+		line += " :" + strings.Join(tags, ":") + ":"
+	}
+	w.line(line)
+
+	indent := strings.Repeat(" ", depth+2)
+	planning := []string{}
+	if task.Status != db.StatusIncomplete && task.StopDate != "" {
+		planning = append(planning, "CLOSED: "+orgTimestamp(task.StopDate, "[", "]"))
+	}
+	if task.StartDate != "" {
+		planning = append(planning, "SCHEDULED: "+orgTimestamp(task.StartDate, "<", ">"))
+	}
+	if task.Deadline != "" {
+		planning = append(planning, "DEADLINE: "+orgTimestamp(task.Deadline, "<", ">"))
+	}
+	if len(planning) > 0 {
+		w.line(indent + strings.Join(planning, " "))
+	}
+	for _, item := range task.Checklist {
+		box := "[ ]"
+		if item.Status != db.StatusIncomplete {
+			box = "[X]"
+		}
+		w.line(indent + "- " + box + " " + item.Title)
+	}
+}
+
+// orgTimestamp formats a YYYY-MM-DD date (with an optional HH:MM:SS time)
+// as an Org timestamp such as <2026-01-05 Mon> or [2026-01-05 Mon 09:30].
+func orgTimestamp(value string, openMark string, closeMark string) string {
+	if parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local); err == nil {
+		return openMark + parsed.Format("2006-01-02 Mon 15:04") + closeMark
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return openMark + parsed.Format("2006-01-02 Mon") + closeMark
+	}
+	return openMark + value + closeMark
+}
+
+// taskPaperTask writes a "- " task line with @tags, @start and @due dates,
+// and @done or @cancelled for closed todos.
+func (w *outlineWriter) taskPaperTask(task db.Task, depth int) {
+	indent := strings.Repeat("\t", depth)
+	line := indent + "- " + task.Title
+	for _, tag := range task.Tags {
+		line += " @" + outlineTag(tag, "_")
+	}
+	if task.StartDate != "" {
+		line += " @start(" + task.StartDate + ")"
+	}
+	if task.Deadline != "" {
+		line += " @due(" + task.Deadline + ")"
+	}
+	stopped := task.StopDate
+	if len(stopped) > len("2006-01-02") {
+		stopped = stopped[:len("2006-01-02")]
+	}
+	switch task.Status {
+	case db.StatusCompleted:
+		line += taskPaperTag("done", stopped)
+	case db.StatusCanceled:
+		line += taskPaperTag("cancelled", stopped)
+	}
+	w.line(line)
+	for _, item := range task.Checklist {
+		itemLine := indent + "\t- " + item.Title
+		if item.Status != db.StatusIncomplete {
+			itemLine += " @done"
+		}
+		w.line(itemLine)
+	}
+}
+
+func taskPaperTag(name string, value string) string {
+	if value == "" {
+		return " @" + name
+	}
+	return " @" + name + "(" + value + ")"
+}
+
+// outlineTag makes a tag title usable as a single-word tag, replacing
+// anything other than letters, digits, "-", and "_" with sep.
+func outlineTag(tag string, sep string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(tag) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || (r == '-' && sep == "-") {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(sep)
+		}
+	}
+	return b.String()
+}
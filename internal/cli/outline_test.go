@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+func TestTasksOutlineFormats(t *testing.T) {
+	dbPath := writeTestDB(t)
+	useTempActionLog(t)
+
+	output, err := runWithArgs(t, "tasks", "--db", dbPath, "--project", "P1", "--recursive", "--format", "markdown")
+	if err != nil {
+		t.Fatalf("markdown: %v", err)
+	}
+	// HT1 sits under the heading with no project of its own, as in Things.
+	want := "## Project One\n\n### Heading\n\n- [ ] Task One #urgent\n  - [ ] Check Item\n- [ ] Heading Task\n"
+	if output != want {
+		t.Fatalf("unexpected markdown:\n%s", output)
+	}
+
+	output, err = runWithArgs(t, "tasks", "--db", dbPath, "--project", "P1", "--format", "org")
+	if err != nil {
+		t.Fatalf("org: %v", err)
+	}
+	if !strings.Contains(output, "* Project One\n** Heading\n*** TODO Task One :urgent:\n*** TODO Heading Task\n") {
+		t.Fatalf("unexpected org:\n%s", output)
+	}
+
+	output, err = runWithArgs(t, "tasks", "--db", dbPath, "--project", "P1", "--format", "taskpaper")
+	if err != nil {
+		t.Fatalf("taskpaper: %v", err)
+	}
+	if !strings.Contains(output, "Project One:\n\tHeading:\n\t\t- Task One @urgent") {
+		t.Fatalf("unexpected taskpaper:\n%s", output)
+	}
+}
+
+func TestTreeOutlineFormat(t *testing.T) {
+	dbPath := writeTestDB(t)
+	useTempActionLog(t)
+
+	output, err := runWithArgs(t, "projects", "--db", dbPath, "--recursive", "--format", "org")
+	if err != nil {
+		t.Fatalf("projects: %v", err)
+	}
+	if !strings.Contains(output, "* Project One") || !strings.Contains(output, "TODO Task One :urgent:") {
+		t.Fatalf("unexpected org tree:\n%s", output)
+	}
+
+	if _, err := runWithArgs(t, "areas", "--db", dbPath, "--json", "--format", "org"); err == nil {
+		t.Fatalf("expected --json with --format org to fail")
+	}
+}
+
+func TestOrgTimestamp(t *testing.T) {
+	if got := orgTimestamp("2026-01-05", "<", ">"); got != "<2026-01-05 Mon>" {
+		t.Fatalf("unexpected date stamp: %q", got)
+	}
+	if got := orgTimestamp("2026-01-05 09:30:00", "[", "]"); got != "[2026-01-05 Mon 09:30]" {
+		t.Fatalf("unexpected time stamp: %q", got)
+	}
+}
+
+func TestTaskOutlineKeysHeadingsByID(t *testing.T) {
+	tasks := []db.Task{
+		{Title: "Paint", HeadingID: "H1", HeadingTitle: "Later", HeadingProjectID: "P1", HeadingProjectTitle: "Kitchen"},
+		{Title: "Order tiles", HeadingID: "H2", HeadingTitle: "Later", HeadingProjectID: "P2", HeadingProjectTitle: "Bathroom"},
+		{Title: "Measure", ProjectID: "P1", ProjectTitle: "Kitchen"},
+	}
+	nodes := taskOutline(tasks)
+	if len(nodes) != 2 || nodes[0].Title != "Kitchen" || nodes[1].Title != "Bathroom" {
+		t.Fatalf("expected one group per project, got %#v", nodes)
+	}
+	kitchen := nodes[0].Children
+	if len(kitchen) != 2 || kitchen[0].Title != "Later" || len(kitchen[0].Children) != 1 || kitchen[1].Title != "Measure" {
+		t.Fatalf("unexpected kitchen group: %#v", kitchen)
+	}
+	if bathroom := nodes[1].Children; len(bathroom) != 1 || bathroom[0].Children[0].Title != "Order tiles" {
+		t.Fatalf("same-named headings of different projects must not merge: %#v", bathroom)
+	}
+}
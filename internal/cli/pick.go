@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// resolvePicked passes a resolver's result through, except that an
+// ambiguous title becomes a prompt when in is a terminal. Scripts keep
+// getting the *db.AmbiguousError, which lists the candidates with UUIDs.
+func resolvePicked(in io.Reader, errOut io.Writer, id string, err error) (string, error) {
+	var ambiguous *db.AmbiguousError
+	if err == nil || !errors.As(err, &ambiguous) || !isInputTTY(in) {
+		return id, err
+	}
+	return pickCandidate(in, errOut, ambiguous)
+}
+
+func pickCandidate(in io.Reader, errOut io.Writer, ambiguous *db.AmbiguousError) (string, error) {
+	fmt.Fprintf(errOut, "%q matches several %s:\n", ambiguous.Input, ambiguous.Kind)
+	for i, candidate := range ambiguous.Candidates {
+		fmt.Fprintf(errOut, "  %d) %s  %s\n", i+1, candidate.Label(), candidate.UUID)
+	}
+	fmt.Fprintf(errOut, "Choose 1-%d: ", len(ambiguous.Candidates))
+	line, err := readLine(in)
+	if err != nil {
+		return "", err
+	}
+	choice, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil || choice < 1 || choice > len(ambiguous.Candidates) {
+		return "", fmt.Errorf("no match chosen for %q", ambiguous.Input)
+	}
+	return ambiguous.Candidates[choice-1].UUID, nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+func TestPickCandidate(t *testing.T) {
+	ambiguous := &db.AmbiguousError{
+		Kind:  "projects",
+		Input: "work",
+		Candidates: []db.Candidate{
+			{UUID: "P1", Title: "Work", Context: "Office"},
+			{UUID: "P2", Title: "Homework"},
+		},
+	}
+
+	errOut := &bytes.Buffer{}
+	id, err := pickCandidate(strings.NewReader("2\n"), errOut, ambiguous)
+	if err != nil || id != "P2" {
+		t.Fatalf("unexpected pick: %q, %v", id, err)
+	}
+	if !strings.Contains(errOut.String(), "1) Work (Office)  P1") || !strings.Contains(errOut.String(), "Choose 1-2: ") {
+		t.Fatalf("unexpected prompt: %q", errOut.String())
+	}
+	if _, err := pickCandidate(strings.NewReader("3\n"), &bytes.Buffer{}, ambiguous); err == nil {
+		t.Fatalf("expected an out-of-range choice to fail")
+	}
+
+	// Without a terminal the ambiguity error is returned unchanged.
+	if _, err := resolvePicked(strings.NewReader("1\n"), &bytes.Buffer{}, "", ambiguous); !errors.Is(err, ambiguous) {
+		t.Fatalf("expected the ambiguity error, got %v", err)
+	}
+}
+
+func TestFuzzyTitleResolution(t *testing.T) {
+	dbPath := writeTestDB(t)
+	useTempActionLog(t)
+
+	output, err := runWithArgs(t, "tasks", "--db", dbPath, "--project", "proj", "--select", "title", "--no-header")
+	if err != nil {
+		t.Fatalf("tasks --project: %v", err)
+	}
+	if output != "Task One\nHeading Task\n" {
+		t.Fatalf("unexpected tasks output: %q", output)
+	}
+
+	output, err = runWithArgs(t, "show", "--db", dbPath, "--json", "projct one")
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	if !strings.Contains(output, `"uuid":"P1"`) {
+		t.Fatalf("unexpected show output: %q", output)
+	}
+
+	// Writes resolve titles exactly, so a typo fails instead of moving todos
+	// into a near match.
+	_, err = runWithArgs(t, "--dry-run", "move", "--db", dbPath, "--auth-token", "tok", "--id", "T1", "--to-project", "Projct One")
+	if err == nil || !strings.Contains(err.Error(), "project not found: Projct One") {
+		t.Fatalf("expected move to reject a fuzzy project title, got %v", err)
+	}
+
+	_, err = runWithArgs(t, "show", "--db", dbPath, "tsk")
+	if err == nil || !strings.Contains(err.Error(), `"tsk" matches`) || !strings.Contains(err.Error(), "Inbox Task [to-do]  INBOX1") {
+		t.Fatalf("expected an ambiguity error listing candidates, got %v", err)
+	}
+}
@@ -0,0 +1,303 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+)
+
+const updatePlanVersion = 1
+
+// UpdatePlan records a bulk update so it can be reviewed and applied later.
+type UpdatePlan struct {
+	Version int                  `json:"version"`
+	Created string               `json:"created"`
+	Input   string               `json:"input,omitempty"`
+	Options things.UpdateOptions `json:"options"`
+	// AllowNonToday records --allow-non-today for the evening guard.
+	AllowNonToday bool       `json:"allow_non_today,omitempty"`
+	Tasks         []PlanTask `json:"tasks"`
+}
+
+// PlanTask pairs a task snapshot with its intended state after the update.
+type PlanTask struct {
+	Before db.Task   `json:"before"`
+	After  PlanState `json:"after"`
+}
+
+// PlanState is the subset of task fields an update can change.
+type PlanState struct {
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Notes     string   `json:"notes,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	When      string   `json:"when,omitempty"`
+	Deadline  string   `json:"deadline,omitempty"`
+	List      string   `json:"list,omitempty"`
+	Heading   string   `json:"heading,omitempty"`
+	Checklist []string `json:"checklist,omitempty"`
+}
+
+func buildUpdatePlan(store *db.Store, tasks []db.Task, opts things.UpdateOptions, rawInput string) (UpdatePlan, error) {
+	opts.AuthToken = ""
+	opts.ID = ""
+	plan := UpdatePlan{
+		Version: updatePlanVersion,
+		Created: time.Now().Format(time.RFC3339),
+		Input:   rawInput,
+		Options: opts,
+		Tasks:   make([]PlanTask, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		if task.Checklist == nil {
+			checklist, err := store.ChecklistItems(task.UUID)
+			if err != nil {
+				return UpdatePlan{}, err
+			}
+			task.Checklist = checklist
+		}
+		plan.Tasks = append(plan.Tasks, PlanTask{
+			Before: task,
+			After:  applyUpdateOptions(planStateFromTask(task), opts, rawInput),
+		})
+	}
+	return plan, nil
+}
+
+func planStateFromTask(task db.Task) PlanState {
+	state := PlanState{
+		Title:    task.Title,
+		Status:   db.StatusLabel(task.Status),
+		Notes:    task.Notes,
+		Tags:     task.Tags,
+		When:     task.StartDate,
+		Deadline: task.Deadline,
+		Heading:  task.HeadingTitle,
+	}
+	if state.When == "" {
+		state.When = strings.ToLower(task.Start)
+	}
+	if task.ProjectTitle != "" {
+		state.List = task.ProjectTitle
+	} else {
+		state.List = task.AreaTitle
+	}
+	for _, item := range task.Checklist {
+		state.Checklist = append(state.Checklist, item.Title)
+	}
+	return state
+}
+
+// applyUpdateOptions predicts the state of a task after a Things update URL
+// built from opts and rawInput is opened.
+func applyUpdateOptions(state PlanState, opts things.UpdateOptions, rawInput string) PlanState {
+	notes := opts.Notes
+	if rawInput != "" {
+		if things.HasMultipleLines(rawInput) {
+			state.Title = things.FindTitle(rawInput)
+			notes = things.FindNotes(rawInput)
+		} else {
+			state.Title = rawInput
+		}
+	}
+	if notes != "" {
+		state.Notes = notes
+	}
+	if opts.PrependNotes != "" {
+		state.Notes = joinNotes(opts.PrependNotes, state.Notes)
+	}
+	if opts.AppendNotes != "" {
+		state.Notes = joinNotes(state.Notes, opts.AppendNotes)
+	}
+
+	if opts.Tags != "" {
+		state.Tags = splitTagList(opts.Tags)
+	}
+	if opts.AddTags != "" {
+		tags := append([]string{}, state.Tags...)
+		for _, tag := range splitTagList(opts.AddTags) {
+			if !containsFold(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+		state.Tags = tags
+	}
+
+	if opts.When != "" {
+		state.When = opts.When
+	} else if opts.Later {
+		state.When = "evening"
+	}
+	if opts.Deadline != "" {
+		state.Deadline = opts.Deadline
+	}
+
+	if opts.Canceled {
+		state.Status = db.StatusLabel(db.StatusCanceled)
+	} else if opts.Completed {
+		state.Status = db.StatusLabel(db.StatusCompleted)
+	}
+
+	if opts.ListID != "" {
+		state.List = opts.ListID
+	} else if opts.List != "" {
+		state.List = opts.List
+	}
+	if opts.Heading != "" {
+		state.Heading = opts.Heading
+	}
+
+	if len(opts.ChecklistItems) > 0 {
+		state.Checklist = append([]string{}, opts.ChecklistItems...)
+	}
+	if len(opts.PrependChecklistItems) > 0 {
+		state.Checklist = append(append([]string{}, opts.PrependChecklistItems...), state.Checklist...)
+	}
+	if len(opts.AppendChecklistItems) > 0 {
+		state.Checklist = append(append([]string{}, state.Checklist...), opts.AppendChecklistItems...)
+	}
+	return state
+}
+
+func joinNotes(first, second string) string {
+	if first == "" {
+		return second
+	}
+	if second == "" {
+		return first
+	}
+	return first + "\n" + second
+}
+
+func splitTagList(value string) []string {
+	tags := []string{}
+	for _, tag := range strings.Split(value, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func containsFold(items []string, value string) bool {
+	for _, item := range items {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanFieldChange is a single field-level difference in a plan.
+type PlanFieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+func diffPlanStates(before, after PlanState) []PlanFieldChange {
+	changes := []PlanFieldChange{}
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, PlanFieldChange{Field: field, Before: from, After: to})
+		}
+	}
+	add("title", before.Title, after.Title)
+	add("status", before.Status, after.Status)
+	add("notes", before.Notes, after.Notes)
+	add("tags", strings.Join(before.Tags, ", "), strings.Join(after.Tags, ", "))
+	add("when", before.When, after.When)
+	add("deadline", before.Deadline, after.Deadline)
+	add("list", before.List, after.List)
+	add("heading", before.Heading, after.Heading)
+	add("checklist", strings.Join(before.Checklist, ", "), strings.Join(after.Checklist, ", "))
+	return changes
+}
+
+func printPlanDiff(out io.Writer, plan UpdatePlan) {
+	fmt.Fprintf(out, "Plan: %d tasks\n", len(plan.Tasks))
+	for _, task := range plan.Tasks {
+		fmt.Fprintf(out, "\n%s  %s\n", task.Before.UUID, task.Before.Title)
+		changes := diffPlanStates(planStateFromTask(task.Before), task.After)
+		if len(changes) == 0 {
+			fmt.Fprintln(out, "  (no field changes)")
+			continue
+		}
+		for _, change := range changes {
+			fmt.Fprintf(out, "  %s:\n", change.Field)
+			fmt.Fprintf(out, "    - %s\n", formatPlanValue(change.Before))
+			fmt.Fprintf(out, "    + %s\n", formatPlanValue(change.After))
+		}
+	}
+}
+
+func formatPlanValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	if strings.ContainsAny(value, "\n\t") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+func writeUpdatePlan(out io.Writer, path string, plan UpdatePlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		_, err = out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("Error: write plan %s: %v", path, err)
+	}
+	return nil
+}
+
+func readUpdatePlan(path string) (UpdatePlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return UpdatePlan{}, fmt.Errorf("Error: read plan %s: %v", path, err)
+	}
+	var plan UpdatePlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return UpdatePlan{}, fmt.Errorf("Error: invalid plan %s: %v", path, err)
+	}
+	if plan.Version != updatePlanVersion {
+		return UpdatePlan{}, fmt.Errorf("Error: unsupported plan version %d", plan.Version)
+	}
+	if len(plan.Tasks) == 0 {
+		return UpdatePlan{}, fmt.Errorf("Error: plan has no tasks")
+	}
+	return plan, nil
+}
+
+// checkPlanFresh verifies that no planned task was modified after the plan was
+// created, by comparing modification timestamps with the database.
+func checkPlanFresh(store *db.Store, plan UpdatePlan) error {
+	stale := []string{}
+	for _, task := range plan.Tasks {
+		current, err := store.TaskByID(task.Before.UUID)
+		if err != nil {
+			stale = append(stale, task.Before.UUID+" (missing)")
+			continue
+		}
+		if current.Modified != task.Before.Modified {
+			stale = append(stale, task.Before.UUID)
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("Error: %d tasks changed since the plan was created: %s (re-run update --plan)", len(stale), strings.Join(stale, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+)
+
+func TestApplyUpdateOptionsPredictsState(t *testing.T) {
+	before := PlanState{
+		Title:  "Task",
+		Status: "incomplete",
+		Notes:  "old",
+		Tags:   []string{"home"},
+		When:   "anytime",
+	}
+	after := applyUpdateOptions(before, things.UpdateOptions{
+		AppendNotes: "more",
+		AddTags:     "errand, Home",
+		When:        "2026-03-01",
+		Completed:   true,
+	}, "")
+
+	changes := diffPlanStates(before, after)
+	got := map[string]PlanFieldChange{}
+	for _, change := range changes {
+		got[change.Field] = change
+	}
+	if len(changes) != 4 {
+		t.Fatalf("expected 4 changes, got %+v", changes)
+	}
+	if got["notes"].After != "old\nmore" {
+		t.Fatalf("unexpected notes: %+v", got["notes"])
+	}
+	if got["tags"].After != "home, errand" {
+		t.Fatalf("unexpected tags: %+v", got["tags"])
+	}
+	if got["when"].Before != "anytime" || got["when"].After != "2026-03-01" {
+		t.Fatalf("unexpected when: %+v", got["when"])
+	}
+	if got["status"].After != "completed" {
+		t.Fatalf("unexpected status: %+v", got["status"])
+	}
+}
+
+func TestUpdatePlanAndApply(t *testing.T) {
+	t.Setenv("THINGS_AUTH_TOKEN", "token")
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("HOME", config)
+	dbPath := writeTestDB(t)
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}, Launcher: &recordLauncher{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"update", "--db", dbPath, "--id", "T1", "--deadline", "2026-05-01", "--add-tags", "errand", "--plan", planPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if _, err := os.Stat(planPath); err != nil {
+		t.Fatalf("expected plan file: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"T1  Task One", "deadline:", "+ 2026-05-01", "- urgent", "+ urgent, errand"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in diff, got:\n%s", want, text)
+		}
+	}
+
+	launcher := &recordLauncher{}
+	app = &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+	root = NewRoot(app)
+	root.SetArgs([]string{"apply", "--db", dbPath, planPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	url := requireOpenURL(t, launcher)
+	if !strings.Contains(url, "id=T1") || !strings.Contains(url, "deadline=2026-05-01") || !strings.Contains(url, "add-tags=errand") {
+		t.Fatalf("unexpected url %q", url)
+	}
+	entries, err := readActions()
+	if err != nil || len(entries) != 1 || entries[0].Type != ActionUpdate || entries[0].Items[0].Expected == nil {
+		t.Fatalf("expected action log entry, got %+v (%v)", entries, err)
+	}
+}
+
+func TestApplyRejectsStalePlan(t *testing.T) {
+	t.Setenv("THINGS_AUTH_TOKEN", "token")
+	dbPath := writeTestDB(t)
+	store, _, err := db.OpenDefault(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	task, err := store.TaskByID("T1")
+	store.Close()
+	if err != nil {
+		t.Fatalf("task: %v", err)
+	}
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	plan := UpdatePlan{
+		Version: updatePlanVersion,
+		Options: things.UpdateOptions{When: "today"},
+		Tasks:   []PlanTask{{Before: *task}},
+	}
+	if err := writeUpdatePlan(&bytes.Buffer{}, planPath, plan); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`UPDATE TMTask SET userModificationDate = 1767225600 WHERE uuid = 'T1'`); err != nil {
+		t.Fatalf("touch task: %v", err)
+	}
+	conn.Close()
+
+	launcher := &recordLauncher{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+	root := NewRoot(app)
+	root.SetArgs([]string{"apply", "--db", dbPath, planPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	err = root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "changed since the plan was created") {
+		t.Fatalf("expected stale plan error, got %v", err)
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no open invocation")
+	}
+}
+
+func TestApplyRunsWhenChecks(t *testing.T) {
+	t.Setenv("THINGS_AUTH_TOKEN", "token")
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	store, _, err := db.OpenDefault(dbPath)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	task, err := store.TaskByID("UP1")
+	store.Close()
+	if err != nil {
+		t.Fatalf("task: %v", err)
+	}
+
+	apply := func(plan UpdatePlan) (*recordLauncher, error) {
+		planPath := filepath.Join(t.TempDir(), "plan.json")
+		if err := writeUpdatePlan(&bytes.Buffer{}, planPath, plan); err != nil {
+			t.Fatalf("write plan: %v", err)
+		}
+		launcher := &recordLauncher{}
+		app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+		root := NewRoot(app)
+		root.SetArgs([]string{"apply", "--db", dbPath, planPath})
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		return launcher, root.Execute()
+	}
+	plan := UpdatePlan{
+		Version: updatePlanVersion,
+		Options: things.UpdateOptions{When: "whenever"},
+		Tasks:   []PlanTask{{Before: *task}},
+	}
+
+	if _, err := apply(plan); err == nil || !strings.Contains(err.Error(), "invalid --when") {
+		t.Fatalf("expected invalid --when error, got %v", err)
+	}
+	plan.Options.When = "evening"
+	launcher, err := apply(plan)
+	if err == nil || !strings.Contains(err.Error(), "refusing to move task UP1 to This Evening") {
+		t.Fatalf("expected evening guard error, got %v", err)
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no open invocation")
+	}
+	plan.AllowNonToday = true
+	if launcher, err = apply(plan); err != nil {
+		t.Fatalf("apply with --allow-non-today plan failed: %v", err)
+	}
+	if url := requireOpenURL(t, launcher); !strings.Contains(url, "when=evening") {
+		t.Fatalf("unexpected url %q", url)
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// taskListAnnotation marks commands whose --format, --select, --sort, and
+// --limit take task-list values, so profile-wide defaults apply to them.
+const taskListAnnotation = "things3-cli/task-list"
+
+// applyProfile loads the selected profile into app and fills in defaults for
+// flags the user did not set on cmd.
+func applyProfile(app *App, cmd *cobra.Command) error {
+	profile, name, err := loadProfile(app.ProfileName)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+	app.Profile = profile
+	app.ProfileName = name
+	if profile == nil {
+		return nil
+	}
+
+	_, taskList := cmd.Annotations[taskListAnnotation]
+	defaults := profile.DefaultsFor(commandKey(cmd), taskList)
+	values := []flagDefault{
+		{"db", profile.DB, []string{"database"}},
+		{"format", defaults.Format, []string{"json", "template", "template-file"}},
+		{"select", strings.Join(defaults.Select, ","), nil},
+		{"sort", defaults.Sort, nil},
+	}
+	if defaults.Limit != nil {
+		values = append(values, flagDefault{"limit", strconv.Itoa(*defaults.Limit), nil})
+	}
+	for _, entry := range values {
+		if err := setFlagDefault(cmd, entry.flag, entry.value, entry.conflicts...); err != nil {
+			return fmt.Errorf("Error: profile %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+type flagDefault struct {
+	flag      string
+	value     string
+	conflicts []string
+}
+
+// loadProfile reads the config file and returns the profile named by name,
+// THINGS_PROFILE, or default_profile, in that order.
+func loadProfile(name string) (*config.Profile, string, error) {
+	if name == "" {
+		name = strings.TrimSpace(os.Getenv("THINGS_PROFILE"))
+	}
+	path, err := config.Path()
+	if err != nil {
+		if name == "" {
+			return nil, "", nil
+		}
+		return nil, "", err
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg.Profile(name)
+}
+
+// setFlagDefault sets flag to value unless the user set it or one of its
+// conflicting flags. Setting through Value leaves Changed false, so commands
+// still treat the value as a default.
+func setFlagDefault(cmd *cobra.Command, name string, value string, conflicts ...string) error {
+	if value == "" {
+		return nil
+	}
+	flag := cmd.Flags().Lookup(name)
+	if flag == nil || flag.Changed {
+		return nil
+	}
+	for _, conflict := range conflicts {
+		if cmd.Flags().Changed(conflict) {
+			return nil
+		}
+	}
+	if err := flag.Value.Set(value); err != nil {
+		return fmt.Errorf("invalid %s %q: %v", name, value, err)
+	}
+	return nil
+}
+
+// commandKey names cmd the way [profiles.NAME.commands] keys do, e.g.
+// "today" or "template export".
+func commandKey(cmd *cobra.Command) string {
+	path := cmd.CommandPath()
+	if root := cmd.Root(); root != nil {
+		path = strings.TrimPrefix(path, root.Name())
+	}
+	return strings.TrimSpace(path)
+}
+
+// ExpandAliases expands the command word when it names an alias of the
+// active profile. It runs before flag parsing, so it finds --profile and the
+// command word (the first argument after leading global flags) itself.
+func ExpandAliases(args []string) ([]string, error) {
+	name := ""
+	commandIndex := len(args)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if value, ok := strings.CutPrefix(arg, "--profile="); ok {
+			name = value
+			continue
+		}
+		if arg == "--profile" {
+			if i+1 < len(args) {
+				name = args[i+1]
+			}
+			i++
+			continue
+		}
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			commandIndex = i
+			break
+		}
+	}
+	profile, _, err := loadProfile(name)
+	if err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	if profile == nil || commandIndex >= len(args) {
+		return args, nil
+	}
+	rest, err := profile.ExpandAlias(args[commandIndex:])
+	if err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	expanded := make([]string, 0, commandIndex+len(rest))
+	expanded = append(expanded, args[:commandIndex]...)
+	return append(expanded, rest...), nil
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeProfileConfig(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	t.Setenv("THINGS_CONFIG", path)
+	t.Setenv("THINGS_PROFILE", "")
+}
+
+func runWithArgs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}, Launcher: &recordLauncher{}}
+	expanded, err := ExpandAliases(args)
+	if err != nil {
+		return "", err
+	}
+	root := NewRoot(app)
+	root.SetArgs(expanded)
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	err = root.Execute()
+	return out.String(), err
+}
+
+func TestProfileSuppliesDatabaseAndOutputDefaults(t *testing.T) {
+	dbPath := writeTestDB(t)
+	writeProfileConfig(t, `
+[profiles.work]
+db = "`+dbPath+`"
+format = "json"
+select = ["title"]
+`)
+
+	out, err := runWithArgs(t, "--profile", "work", "tasks")
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatalf("expected JSON from profile format, got %q (%v)", out, err)
+	}
+	if len(rows) == 0 || !reflect.DeepEqual(keys(rows[0]), []string{"title"}) {
+		t.Fatalf("expected only selected fields, got %v", rows)
+	}
+
+	out, err = runWithArgs(t, "--profile=work", "tasks", "--format", "csv")
+	if err != nil {
+		t.Fatalf("tasks csv: %v", err)
+	}
+	if !strings.HasPrefix(out, "TITLE\nTask One\n") {
+		t.Fatalf("expected explicit --format to win, got %q", out)
+	}
+
+	if _, err := runWithArgs(t, "tasks", "--profile", "missing"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+}
+
+func TestProfileAliasesAndToken(t *testing.T) {
+	dbPath := writeTestDB(t)
+	t.Setenv("THINGS_AUTH_TOKEN", "")
+	t.Setenv("WORK_TOKEN", "work-secret")
+	writeProfileConfig(t, `
+default_profile = "work"
+
+[profiles.work]
+db = "`+dbPath+`"
+auth_token_env = "WORK_TOKEN"
+
+[profiles.work.aliases]
+mine = "tasks --select title --format csv"
+`)
+
+	out, err := runWithArgs(t, "mine", "--no-header")
+	if err != nil {
+		t.Fatalf("alias: %v", err)
+	}
+	if !strings.HasPrefix(out, "Task One\n") || strings.Contains(out, "TITLE") {
+		t.Fatalf("unexpected alias output %q", out)
+	}
+
+	out, err = runWithArgs(t, "auth")
+	if err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+	if !strings.Contains(out, "set (profile work, WORK_TOKEN)") {
+		t.Fatalf("expected profile token source, got %q", out)
+	}
+}
+
+func keys(row map[string]any) []string {
+	out := make([]string, 0, len(row))
+	for key := range row {
+		out = append(out, key)
+	}
+	return out
+}
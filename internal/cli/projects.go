@@ -15,6 +15,7 @@ func NewProjectsCommand(app *App) *cobra.Command {
 	var includeTrashed bool
 	var all bool
 	var asJSON bool
+	var format string
 	var noHeader bool
 	var recursive bool
 	var onlyProjects bool
@@ -32,6 +33,10 @@ func NewProjectsCommand(app *App) *cobra.Command {
 			if onlyProjects && !recursive {
 				recursive = true
 			}
+			format, err = resolveOutlineFormat(format, asJSON)
+			if err != nil {
+				return err
+			}
 
 			statusFilter, err := db.ParseStatus(status)
 			if err != nil {
@@ -44,7 +49,8 @@ func NewProjectsCommand(app *App) *cobra.Command {
 
 			areaID := ""
 			if area != "" {
-				areaID, err = store.ResolveAreaID(area)
+				areaID, err = store.MatchAreaID(area)
+				areaID, err = resolvePicked(app.In, app.Err, areaID, err)
 				if err != nil {
 					return fmt.Errorf("Error: %s", err)
 				}
@@ -61,7 +67,7 @@ func NewProjectsCommand(app *App) *cobra.Command {
 				if err != nil {
 					return formatDBError(err)
 				}
-				return printTree(app.Out, items, asJSON)
+				return printTree(app.Out, items, format)
 			}
 
 			projects, err := store.Projects(db.ProjectFilter{
@@ -72,7 +78,10 @@ func NewProjectsCommand(app *App) *cobra.Command {
 			if err != nil {
 				return formatDBError(err)
 			}
-			return printProjects(app.Out, projects, asJSON, noHeader)
+			if isOutlineFormat(format) {
+				return printTree(app.Out, projectTreeItems(projects), format)
+			}
+			return printProjects(app.Out, projects, format == "json", noHeader)
 		},
 	}
 
@@ -84,6 +93,7 @@ func NewProjectsCommand(app *App) *cobra.Command {
 	cmd.Flags().BoolVar(&includeTrashed, "include-trashed", false, "Include trashed projects")
 	cmd.Flags().BoolVar(&all, "all", false, "Include completed, canceled, and trashed projects")
 	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+	cmd.Flags().StringVar(&format, "format", "", "Output format: table, json, markdown, org, taskpaper")
 	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Suppress header row")
 	cmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Include nested headings/todos")
 	cmd.Flags().BoolVarP(&onlyProjects, "only-projects", "e", false, "Only include projects")
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// NewQueryCommand builds the query command group for saved queries.
+func NewQueryCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query <save|list|run|delete>",
+		Short: "Save and run named queries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printHelp(app.Out, formatHelpText(queryHelp, isTTY(app.Out)))
+			return ErrHelpPrinted
+		},
+	}
+	cmd.AddCommand(newQuerySaveCommand(app))
+	cmd.AddCommand(newQueryListCommand(app))
+	cmd.AddCommand(newQueryRunCommand(app))
+	cmd.AddCommand(newQueryDeleteCommand(app))
+	return cmd
+}
+
+func newQuerySaveCommand(app *App) *cobra.Command {
+	var saved SavedQuery
+
+	cmd := &cobra.Command{
+		Use:   "save NAME --query=QUERY",
+		Short: "Save a named query",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := validateSavedQueryName(args[0])
+			if err != nil {
+				return err
+			}
+			saved.Name = name
+			saved.Query = strings.TrimSpace(saved.Query)
+			if saved.Query == "" {
+				return fmt.Errorf("Error: --query is required")
+			}
+			if _, _, err := parseSortSpec(saved.Sort); err != nil {
+				return err
+			}
+			if _, err := parseTaskSelect(saved.Select); err != nil {
+				return err
+			}
+
+			queries, err := readSavedQueries()
+			if err != nil {
+				return err
+			}
+			_, existed := queries[name]
+			queries[name] = saved
+			// Parse against the updated set so references and cycles through
+			// this query are checked before anything is written.
+			if _, err := parseRichQueryWith("@"+name, savedQueryResolver(queries), nil, nil); err != nil {
+				return err
+			}
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would save query @%s: %s\n", name, saved.Query)
+				return nil
+			}
+			if err := writeSavedQueries(queries); err != nil {
+				return err
+			}
+			if existed {
+				fmt.Fprintf(app.Out, "Updated query @%s\n", name)
+			} else {
+				fmt.Fprintf(app.Out, "Saved query @%s\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&saved.Query, "query", "", "Rich query to save (may reference other saved queries as @name)")
+	cmd.Flags().StringVar(&saved.Sort, "sort", "", "Default sort for query run (e.g. -deadline,title)")
+	cmd.Flags().StringVar(&saved.Select, "select", "", "Default fields for query run (comma-separated)")
+	return cmd
+}
+
+func newQueryListCommand(app *App) *cobra.Command {
+	var asJSON bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved queries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queries, err := readSavedQueries()
+			if err != nil {
+				return err
+			}
+			list := sortedSavedQueries(queries)
+			if asJSON {
+				enc := json.NewEncoder(app.Out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(list)
+			}
+			if len(list) == 0 {
+				fmt.Fprintln(app.Err, "No saved queries")
+				return nil
+			}
+			w := tabwriter.NewWriter(app.Out, 0, 2, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tQUERY\tSORT\tSELECT")
+			for _, query := range list {
+				fmt.Fprintf(w, "@%s\t%s\t%s\t%s\n", query.Name, query.Query, query.Sort, query.Select)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
+	return cmd
+}
+
+func newQueryRunCommand(app *App) *cobra.Command {
+	var dbPath string
+	opts := TaskQueryOptions{
+		Status: "incomplete",
+		Limit:  200,
+	}
+	var format string
+	var selectRaw string
+	var asJSON bool
+	var noHeader bool
+	var templateFlags taskTemplateFlags
+
+	cmd := &cobra.Command{
+		Use:   "run NAME [OPTIONS...]",
+		Short: "List todos matching a saved query",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := validateSavedQueryName(args[0])
+			if err != nil {
+				return err
+			}
+			queries, err := readSavedQueries()
+			if err != nil {
+				return err
+			}
+			saved, ok := queries[name]
+			if !ok {
+				return fmt.Errorf("Error: unknown saved query @%s (see things query list)", name)
+			}
+
+			// Flags given on the command line narrow or override the saved
+			// query; its sort and fields fill in the ones left unset.
+			if extra := strings.TrimSpace(opts.Query); extra != "" {
+				opts.Query = fmt.Sprintf("@%s AND (%s)", name, extra)
+			} else {
+				opts.Query = "@" + name
+			}
+			if !cmd.Flags().Changed("sort") && saved.Sort != "" {
+				opts.Sort = saved.Sort
+			}
+			if !cmd.Flags().Changed("select") && saved.Select != "" {
+				selectRaw = saved.Select
+			}
+
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			opts.HasURLSet = cmd.Flags().Changed("has-url")
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
+			if err != nil {
+				return err
+			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
+			tasks, err := fetchTasks(store, store.Tasks, opts, false, []int{db.TaskTypeTodo})
+			if err != nil {
+				return formatDBError(err)
+			}
+			return printTasks(app.Out, tasks, outputOpts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	addTaskQueryFlags(cmd, &opts, true, true)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
+	return cmd
+}
+
+func newQueryDeleteCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete NAME",
+		Short: "Delete a saved query",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := validateSavedQueryName(args[0])
+			if err != nil {
+				return err
+			}
+			queries, err := readSavedQueries()
+			if err != nil {
+				return err
+			}
+			if _, ok := queries[name]; !ok {
+				return fmt.Errorf("Error: unknown saved query @%s", name)
+			}
+			for _, other := range sortedSavedQueries(queries) {
+				if other.Name != name && referencesSavedQuery(other.Query, name) {
+					return fmt.Errorf("Error: @%s is used by @%s", name, other.Name)
+				}
+			}
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would delete query @%s\n", name)
+				return nil
+			}
+			delete(queries, name)
+			if err := writeSavedQueries(queries); err != nil {
+				return err
+			}
+			fmt.Fprintf(app.Out, "Deleted query @%s\n", name)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// referencesSavedQuery reports whether query refers to @name directly.
+func referencesSavedQuery(query string, name string) bool {
+	tokens, err := newQueryLexer(query).tokens()
+	if err != nil {
+		return false
+	}
+	for _, tok := range tokens {
+		if tok.typ == tokenIdent && tok.value == "@"+name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQuerySaveListRunDelete(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+
+	if _, err := runWithArgs(t, "query", "save", "urgent", "--query", "tag:urgent"); err != nil {
+		t.Fatalf("save urgent: %v", err)
+	}
+	out, err := runWithArgs(t, "query", "save", "@mine", "--query", "@urgent AND title:task", "--select", "title,tags")
+	if err != nil {
+		t.Fatalf("save mine: %v", err)
+	}
+	if out != "Saved query @mine\n" {
+		t.Fatalf("unexpected save output %q", out)
+	}
+	if _, err := runWithArgs(t, "query", "save", "urgent", "--query", "@mine"); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Fatalf("expected cycle to be rejected, got %v", err)
+	}
+
+	out, err = runWithArgs(t, "query", "list", "--json")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var saved []SavedQuery
+	if err := json.Unmarshal([]byte(out), &saved); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(saved) != 2 || saved[0].Name != "mine" || saved[1].Query != "tag:urgent" {
+		t.Fatalf("unexpected saved queries: %+v", saved)
+	}
+
+	out, err = runWithArgs(t, "query", "run", "mine", "--db", dbPath)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "TITLE") || !strings.Contains(lines[0], "TAGS") || !strings.Contains(lines[1], "Task One") {
+		t.Fatalf("unexpected run output %q", out)
+	}
+
+	out, err = runWithArgs(t, "tasks", "--db", dbPath, "--query", "NOT @urgent", "--select", "title", "--no-header")
+	if err != nil {
+		t.Fatalf("tasks with reference: %v", err)
+	}
+	if out == "" || strings.Contains(out, "Task One") {
+		t.Fatalf("expected @urgent to be excluded, got %q", out)
+	}
+
+	if _, err := runWithArgs(t, "query", "delete", "urgent"); err == nil || !strings.Contains(err.Error(), "used by @mine") {
+		t.Fatalf("expected referenced query to be kept, got %v", err)
+	}
+	if _, err := runWithArgs(t, "query", "delete", "mine"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := runWithArgs(t, "query", "run", "mine", "--db", dbPath); err == nil || !strings.Contains(err.Error(), "unknown saved query @mine") {
+		t.Fatalf("expected unknown query error, got %v", err)
+	}
+	// Without a saved query of that name, @mine is searched for literally.
+	if out, err := runWithArgs(t, "tasks", "--db", dbPath, "--query", "@mine", "--select", "title", "--no-header"); err != nil || out != "" {
+		t.Fatalf("expected a literal match with no results, got %q, %v", out, err)
+	}
+}
+
+func TestSavedQueriesFollowThingsConfig(t *testing.T) {
+	useTempActionLog(t)
+	dir := filepath.Join(t.TempDir(), "custom")
+	t.Setenv("THINGS_CONFIG", filepath.Join(dir, "config.toml"))
+
+	if _, err := runWithArgs(t, "query", "save", "mine", "--query", "tag:urgent"); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "queries.json")); err != nil {
+		t.Fatalf("expected queries.json next to THINGS_CONFIG: %v", err)
+	}
+}
+
+func TestTasksQueryTypedPredicates(t *testing.T) {
+	dbPath := writeTestDB(t)
+
+	out, err := runWithArgs(t, "tasks", "--db", dbPath, "--query", "deadline<=+1d OR start:someday", "--select", "title", "--no-header")
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	if out != "Upcoming Task\nSomeday Task\nDeadline Task\n" {
+		t.Fatalf("unexpected date/start matches %q", out)
+	}
+
+	out, err = runWithArgs(t, "tasks", "--db", dbPath, "--query", "checklist:incomplete", "--format", "json")
+	if err != nil {
+		t.Fatalf("tasks checklist: %v", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal([]byte(out), &rows); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["uuid"] != "T1" || rows[0]["checklist"] != nil {
+		t.Fatalf("expected T1 without checklist items, got %v", rows)
+	}
+
+	out, err = runWithArgs(t, "tasks", "--db", dbPath, "--status", "any", "--query", "status:completed AND stopped>=today", "--select", "title", "--no-header")
+	if err != nil {
+		t.Fatalf("tasks status: %v", err)
+	}
+	if out != "Completed Task\n" {
+		t.Fatalf("unexpected status matches %q", out)
+	}
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NewRedoCommand builds the redo subcommand.
+func NewRedoCommand(app *App) *cobra.Command {
+	var dbPath string
+	var id string
+	var authToken string
+	var yes bool
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "redo [OPTIONS...]",
+		Short: "Redo an undone action",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			entries, err := readActions()
+			if err != nil {
+				return fmt.Errorf("Error: %s", err)
+			}
+			idx, err := findAction(entries, id, true)
+			if err != nil {
+				return fmt.Errorf("Error: %s", err)
+			}
+			entry := entries[idx]
+			if !entry.Undone {
+				return fmt.Errorf("Error: action %s has not been undone", entry.ID)
+			}
+			if len(entry.Redo) == 0 {
+				return fmt.Errorf("Error: action %s cannot be redone (logged before redo support)", entry.ID)
+			}
+
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would redo %s %s for %d items\n", entry.Type, entry.ID, len(entry.Items))
+				return previewActionItems(app.Out, entry.Items)
+			}
+
+			if len(entry.Items) > 1 && !yes {
+				return fmt.Errorf("Error: %d tasks matched (rerun with --yes to apply)", len(entry.Items))
+			}
+
+			store, err := openActionStore(dbPath, entry.Type == ActionRepeat)
+			if err != nil {
+				if entry.Type == ActionRepeat {
+					return formatDBError(err)
+				}
+				fmt.Fprintf(app.Err, "Warning: could not verify current state: %v\n", err)
+			}
+			if store != nil {
+				defer closeWriteStore(app, store, &err)
+				if err := checkActionDrift(app, store, entry, false, force); err != nil {
+					return err
+				}
+			}
+
+			// Redoing add-area creates a new area; log its ID for the next undo.
+			var areasBefore []map[string]bool
+			if entry.Type == ActionAddArea && store != nil {
+				for _, item := range entry.Items {
+					before, err := areaIDsTitled(store, item.Title)
+					if err != nil {
+						return formatDBError(err)
+					}
+					areasBefore = append(areasBefore, before)
+				}
+			}
+
+			token := ""
+			for _, step := range entry.Redo {
+				switch {
+				case step.URL != "":
+					url := step.URL
+					if strings.Contains(url, "auth-token=***") {
+						if token == "" {
+							token, err = resolveAuthToken(app, authToken)
+							if err != nil {
+								return err
+							}
+						}
+						url = strings.Replace(url, "auth-token=***", "auth-token="+token, 1)
+					}
+					if err := openURL(app, url); err != nil {
+						return err
+					}
+				case step.Script != "":
+					if err := runScript(app, step.Script); err != nil {
+						return err
+					}
+				case step.RepeatID != "":
+					if step.RepeatClear || step.Repeat == nil {
+						err = store.ClearRepeatRule(step.RepeatID)
+					} else {
+						err = store.ApplyRepeatRule(step.RepeatID, *step.Repeat)
+					}
+					if err != nil {
+						return formatDBError(err)
+					}
+				}
+			}
+
+			if areasBefore != nil {
+				items := append([]ActionItem(nil), entry.Items...)
+				for i := range items {
+					items[i].UUID = findCreatedArea(store, items[i].Title, areasBefore[i])
+				}
+				if err := setActionItems(entry.ID, items); err != nil {
+					fmt.Fprintf(app.Err, "Warning: failed to update action log: %v\n", err)
+				}
+			}
+			if err := setActionUndone(entry.ID, false); err != nil {
+				fmt.Fprintf(app.Err, "Warning: failed to update action log: %v\n", err)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
+	flags.StringVar(&id, "id", "", "ID of the logged action to redo (see things history)")
+	flags.StringVar(&authToken, "auth-token", "", "Things URL scheme authorization token")
+	flags.BoolVar(&yes, "yes", false, "Confirm redo for multiple tasks")
+	flags.BoolVar(&force, "force", false, "Redo even if items changed since they were undone")
+
+	return cmd
+}
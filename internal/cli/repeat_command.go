@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/repeat"
+	"github.com/spf13/cobra"
+)
+
+// NewRepeatCommand builds the repeat subcommand.
+func NewRepeatCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "repeat <pause|resume|skip>",
+		Short: "Pause, resume, or skip repeating templates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printHelp(app.Out, formatHelpText(repeatHelp, isTTY(app.Out)))
+			return ErrHelpPrinted
+		},
+	}
+	cmd.AddCommand(newRepeatChangeCommand(app, "pause", "Stop a repeating template from creating new instances", pauseRepeat))
+	cmd.AddCommand(newRepeatChangeCommand(app, "resume", "Let a paused repeating template create instances again", resumeRepeat))
+	cmd.AddCommand(newRepeatChangeCommand(app, "skip", "Move the next instance of a repeating template forward one interval", skipRepeat))
+	return cmd
+}
+
+// repeatChange edits a snapshot of a template's repeat fields in place.
+type repeatChange func(update *db.RepeatUpdate) error
+
+func newRepeatChangeCommand(app *App, use string, short string, change repeatChange) *cobra.Command {
+	var dbPath string
+	var id string
+
+	cmd := &cobra.Command{
+		Use:   use + " --id=ID",
+		Short: short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if strings.TrimSpace(id) == "" {
+				return fmt.Errorf("Error: Must specify --id=ID")
+			}
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would %s repeating template for %s\n", use, id)
+				return nil
+			}
+
+			store, _, err := db.OpenDefaultWritable(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer closeWriteStore(app, store, &err)
+
+			target, err := store.RepeatTargetByID(id)
+			if err != nil {
+				return formatDBError(err)
+			}
+			targetID, usedTemplate, err := resolveRepeatTarget(store, id, target.Type)
+			if err != nil {
+				return formatDBError(err)
+			}
+			if usedTemplate {
+				fmt.Fprintf(app.Err, "Note: resolved repeating template %s\n", targetID)
+			}
+			before, err := store.RepeatRuleByID(targetID)
+			if err != nil {
+				return formatDBError(err)
+			}
+			if before == nil {
+				return fmt.Errorf("Error: item does not repeat")
+			}
+
+			after := *before
+			if err := change(&after); err != nil {
+				return err
+			}
+			if err := store.ApplyRepeatRule(targetID, after); err != nil {
+				return formatDBError(err)
+			}
+
+			targetTitle := targetID
+			if task, err := store.TaskByID(targetID); err == nil {
+				targetTitle = task.Title
+			}
+			logAction(app, ActionEntry{
+				Type:  ActionRepeat,
+				Items: []ActionItem{{UUID: targetID, Title: targetTitle, Repeat: before}},
+				Redo:  []ActionStep{{RepeatID: targetID, Repeat: &after}},
+			})
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().StringVar(&id, "id", "", "ID of the repeating template or one of its instances")
+
+	return cmd
+}
+
+func pauseRepeat(update *db.RepeatUpdate) error {
+	if update.InstanceCreationPaused != 0 {
+		return fmt.Errorf("Error: repeating template is already paused")
+	}
+	update.InstanceCreationPaused = 1
+	return nil
+}
+
+func resumeRepeat(update *db.RepeatUpdate) error {
+	if update.InstanceCreationPaused == 0 {
+		return fmt.Errorf("Error: repeating template is not paused")
+	}
+	update.InstanceCreationPaused = 0
+	return nil
+}
+
+func skipRepeat(update *db.RepeatUpdate) error {
+	if update.NextInstanceStartDate == nil {
+		return fmt.Errorf("Error: repeating template has no scheduled next instance to skip")
+	}
+	spec, err := repeat.DecodeRule(update.RecurrenceRule)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+	next, err := repeat.SkipNext(spec, *update.NextInstanceStartDate)
+	if err != nil {
+		return fmt.Errorf("Error: %v", err)
+	}
+	update.NextInstanceStartDate = &next
+	update.Deadline = repeat.TemplateDeadline(spec)
+	update.SetDeadline = true
+	return nil
+}
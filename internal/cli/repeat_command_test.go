@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/repeat"
+)
+
+func TestRepeatPauseResumeSkipWithUndo(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+	addRepeatColumns(t, conn)
+
+	update, err := repeat.BuildUpdate(repeat.Spec{
+		Mode:   repeat.ModeSchedule,
+		Unit:   repeat.UnitWeek,
+		Every:  1,
+		Anchor: time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local),
+	})
+	if err != nil {
+		t.Fatalf("build rule: %v", err)
+	}
+	if _, err := conn.Exec(
+		`UPDATE TMTask SET rt1_recurrenceRule = ?, rt1_instanceCreationPaused = 0, rt1_nextInstanceStartDate = ? WHERE uuid = 'T1'`,
+		update.RecurrenceRule, *update.NextInstanceStartDate,
+	); err != nil {
+		t.Fatalf("set rule: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO TMTask (uuid, type, status, trashed, title, rt1_repeatingTemplate) VALUES ('T1-COPY', 0, 0, 0, 'Task One', 'T1')`); err != nil {
+		t.Fatalf("insert instance: %v", err)
+	}
+
+	run := func(args ...string) error {
+		t.Helper()
+		app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		return root.Execute()
+	}
+	state := func() (int, int) {
+		t.Helper()
+		var paused, next int
+		if err := conn.QueryRow(`SELECT rt1_instanceCreationPaused, rt1_nextInstanceStartDate FROM TMTask WHERE uuid = 'T1'`).Scan(&paused, &next); err != nil {
+			t.Fatalf("select state: %v", err)
+		}
+		return paused, next
+	}
+	dateValue := func(day int) int {
+		return 2026<<16 | 1<<12 | day<<7
+	}
+
+	if err := run("repeat", "pause", "--db", dbPath, "--id", "T1-COPY"); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if paused, _ := state(); paused != 1 {
+		t.Fatalf("expected template paused")
+	}
+	if err := run("repeat", "pause", "--db", dbPath, "--id", "T1"); err == nil || !strings.Contains(err.Error(), "already paused") {
+		t.Fatalf("expected already paused error, got %v", err)
+	}
+	if err := run("repeat", "resume", "--db", dbPath, "--id", "T1"); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+	if paused, _ := state(); paused != 0 {
+		t.Fatalf("expected template resumed")
+	}
+
+	if _, next := state(); next != dateValue(12) {
+		t.Fatalf("unexpected initial next instance %d", next)
+	}
+	if err := run("repeat", "skip", "--db", dbPath, "--id", "T1"); err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	if _, next := state(); next != dateValue(19) {
+		t.Fatalf("expected next instance moved a week, got %d", next)
+	}
+
+	if err := run("undo", "--db", dbPath); err != nil {
+		t.Fatalf("undo: %v", err)
+	}
+	if _, next := state(); next != dateValue(12) {
+		t.Fatalf("expected undo to restore next instance, got %d", next)
+	}
+}
+
+func TestRepeatRequiresRepeatingItem(t *testing.T) {
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	addRepeatColumns(t, conn)
+	conn.Close()
+
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"repeat", "skip", "--db", dbPath, "--id", "T1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "does not repeat") {
+		t.Fatalf("expected non-repeating error, got %v", err)
+	}
+}
+
+func TestRepeatSkipResetsDeadlineAndUndoChecksDates(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+	addRepeatColumns(t, conn)
+
+	offset := 2
+	spec := repeat.Spec{
+		Mode:           repeat.ModeSchedule,
+		Unit:           repeat.UnitWeek,
+		Every:          1,
+		Anchor:         time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local),
+		DeadlineOffset: &offset,
+	}
+	update, err := repeat.BuildUpdate(spec)
+	if err != nil {
+		t.Fatalf("build rule: %v", err)
+	}
+	stale := 2026<<16 | 1<<12 | 14<<7
+	if _, err := conn.Exec(
+		`UPDATE TMTask SET rt1_recurrenceRule = ?, rt1_instanceCreationPaused = 0, rt1_nextInstanceStartDate = ?, deadline = ? WHERE uuid = 'T1'`,
+		update.RecurrenceRule, *update.NextInstanceStartDate, stale,
+	); err != nil {
+		t.Fatalf("set rule: %v", err)
+	}
+
+	run := func(args ...string) error {
+		t.Helper()
+		app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		return root.Execute()
+	}
+
+	if err := run("repeat", "skip", "--db", dbPath, "--id", "T1"); err != nil {
+		t.Fatalf("skip: %v", err)
+	}
+	var deadline int
+	if err := conn.QueryRow(`SELECT deadline FROM TMTask WHERE uuid = 'T1'`).Scan(&deadline); err != nil {
+		t.Fatalf("select deadline: %v", err)
+	}
+	if want := *repeat.TemplateDeadline(spec); deadline != want {
+		t.Fatalf("expected template deadline %d, got %d", want, deadline)
+	}
+
+	// Things moving the next instance on after the skip must block the undo.
+	if _, err := conn.Exec(`UPDATE TMTask SET rt1_nextInstanceStartDate = ? WHERE uuid = 'T1'`, 2026<<16|1<<12|26<<7); err != nil {
+		t.Fatalf("move next instance: %v", err)
+	}
+	if err := run("undo", "--db", dbPath); err == nil || !strings.Contains(err.Error(), "repeat rule changed") {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+	if err := run("undo", "--db", dbPath, "--force"); err != nil {
+		t.Fatalf("forced undo: %v", err)
+	}
+	if err := conn.QueryRow(`SELECT deadline FROM TMTask WHERE uuid = 'T1'`).Scan(&deadline); err != nil {
+		t.Fatalf("select deadline: %v", err)
+	}
+	if deadline != stale {
+		t.Fatalf("expected undo to restore the deadline, got %d", deadline)
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/repeat"
+	"github.com/spf13/cobra"
+)
+
+func parseRepeatArgs(t *testing.T, args ...string) (RepeatSpec, error) {
+	t.Helper()
+	var opts RepeatOptions
+	cmd := &cobra.Command{Use: "test"}
+	addRepeatFlags(cmd, &opts, true)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return parseRepeatSpec(cmd, opts)
+}
+
+func TestParseRepeatSpecRepeatOn(t *testing.T) {
+	spec, err := parseRepeatArgs(t, "--repeat=week", "--repeat-on=mon,fri")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := []repeat.Offset{{Weekday: time.Monday}, {Weekday: time.Friday}}
+	if len(spec.Spec.On) != 2 || spec.Spec.On[0] != want[0] || spec.Spec.On[1] != want[1] {
+		t.Fatalf("unexpected offsets: %+v", spec.Spec.On)
+	}
+
+	spec, err = parseRepeatArgs(t, "--repeat=month", "--repeat-on=last-day")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(spec.Spec.On) != 1 || !spec.Spec.On[0].LastDay {
+		t.Fatalf("unexpected offsets: %+v", spec.Spec.On)
+	}
+
+	if _, err := parseRepeatArgs(t, "--repeat=day", "--repeat-on=mon"); err == nil || !strings.Contains(err.Error(), "repeat-on") {
+		t.Fatalf("expected repeat-on error, got %v", err)
+	}
+	if _, err := parseRepeatArgs(t, "--repeat-on=mon"); err == nil || !strings.Contains(err.Error(), "--repeat is required") {
+		t.Fatalf("expected --repeat required error, got %v", err)
+	}
+	if _, err := parseRepeatArgs(t, "--repeat-clear", "--repeat-on=mon"); err == nil {
+		t.Fatalf("expected --repeat-clear conflict")
+	}
+}
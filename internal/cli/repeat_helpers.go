@@ -76,13 +76,31 @@ func resolveRepeatTarget(store *db.Store, id string, expectedType int) (string,
 	return resolvedID, usedTemplate, nil
 }
 
-func applyRepeatSpec(store *db.Store, id string, spec RepeatSpec) error {
+// applyRepeatSpec writes spec to the item and returns the applied update, or
+// nil when the rule was cleared.
+func applyRepeatSpec(store *db.Store, id string, spec RepeatSpec) (*db.RepeatUpdate, error) {
 	if spec.Clear {
-		return store.ClearRepeatRule(id)
+		return nil, store.ClearRepeatRule(id)
 	}
 	update, err := repeat.BuildUpdate(spec.Spec)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return store.ApplyRepeatRule(id, update)
+	if err := store.ApplyRepeatRule(id, update); err != nil {
+		return nil, err
+	}
+	return &update, nil
+}
+
+// checkProjectTemplate verifies that a project can become a repeating
+// template: Things does not allow repeating todos inside it.
+func checkProjectTemplate(store *db.Store, projectID string) error {
+	repeating, err := store.ProjectRepeatingTodos(projectID)
+	if err != nil {
+		return formatDBError(err)
+	}
+	if len(repeating) > 0 {
+		return fmt.Errorf("Error: project contains repeating todos (%s); clear their repeat rules first", strings.Join(repeating, ", "))
+	}
+	return nil
 }
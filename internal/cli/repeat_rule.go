@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/repeat"
+)
+
+// annotateRepeatRules decodes each task's recurrence rule, filling in
+// RepeatRule with a human-readable summary when showRule is set and
+// Occurrences with the next n projected dates when n > 0. Rules that cannot
+// be decoded are reported as "unrecognized" rather than failing the listing.
+func annotateRepeatRules(store *db.Store, tasks []db.Task, showRule bool, n int) error {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.UUID)
+	}
+	rules, err := store.RecurrenceRules(ids)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range tasks {
+		data, ok := rules[tasks[i].UUID]
+		if !ok {
+			continue
+		}
+		spec, err := repeat.DecodeRule(data)
+		if err != nil {
+			if showRule {
+				tasks[i].RepeatRule = "unrecognized"
+			}
+			continue
+		}
+		if showRule {
+			tasks[i].RepeatRule = spec.Describe()
+		}
+		if n > 0 {
+			occurrences, err := repeat.Occurrences(spec, now, n)
+			if err != nil {
+				continue
+			}
+			tasks[i].Occurrences = formatOccurrences(occurrences)
+		}
+	}
+	return nil
+}
+
+// itemOccurrences projects the next n dates of a repeating item. Instances
+// resolve to their template.
+func itemOccurrences(store *db.Store, id string, n int) ([]db.Occurrence, error) {
+	target, err := store.RepeatTargetByID(id)
+	if err != nil {
+		return nil, formatDBError(err)
+	}
+	if target.RepeatingTemplateID != "" {
+		id = target.RepeatingTemplateID
+	}
+	rules, err := store.RecurrenceRules([]string{id})
+	if err != nil {
+		return nil, formatDBError(err)
+	}
+	data, ok := rules[id]
+	if !ok {
+		return nil, fmt.Errorf("Error: item does not repeat")
+	}
+	spec, err := repeat.DecodeRule(data)
+	if err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	occurrences, err := repeat.Occurrences(spec, time.Now(), n)
+	if err != nil {
+		return nil, fmt.Errorf("Error: %v", err)
+	}
+	return formatOccurrences(occurrences), nil
+}
+
+func formatOccurrences(occurrences []repeat.Occurrence) []db.Occurrence {
+	formatted := make([]db.Occurrence, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		entry := db.Occurrence{Start: occurrence.Start.Format("2006-01-02")}
+		if occurrence.Deadline != nil {
+			entry.Deadline = occurrence.Deadline.Format("2006-01-02")
+		}
+		formatted = append(formatted, entry)
+	}
+	return formatted
+}
+
+func occurrencesString(occurrences []db.Occurrence) string {
+	parts := make([]string, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		if occurrence.Deadline != "" {
+			parts = append(parts, occurrence.Start+" (due "+occurrence.Deadline+")")
+		} else {
+			parts = append(parts, occurrence.Start)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// withTaskField adds field to table/csv output and to an explicit --select
+// list. Unselected JSON output already carries every field.
+func withTaskField(opts TaskOutputOptions, field string) TaskOutputOptions {
+	fields := opts.Select
+	if len(fields) == 0 {
+		if opts.Format == "json" || opts.Format == "jsonl" {
+			return opts
+		}
+		fields = defaultTaskTableFields
+	}
+	for _, existing := range fields {
+		if existing == field {
+			return opts
+		}
+	}
+	selected := make([]string, 0, len(fields)+1)
+	selected = append(selected, fields...)
+	opts.Select = append(selected, field)
+	return opts
+}
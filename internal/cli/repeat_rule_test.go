@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/repeat"
+)
+
+func TestRepeatingShowRule(t *testing.T) {
+	dbPath := writeTestDB(t)
+	end := time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local)
+	update, err := repeat.BuildUpdate(repeat.Spec{
+		Mode:    repeat.ModeAfterCompletion,
+		Unit:    repeat.UnitWeek,
+		Every:   2,
+		Anchor:  time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local),
+		EndDate: &end,
+	})
+	if err != nil {
+		t.Fatalf("build rule: %v", err)
+	}
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`UPDATE TMTask SET rt1_recurrenceRule = ? WHERE uuid = 'T1'`, update.RecurrenceRule); err != nil {
+		t.Fatalf("set rule: %v", err)
+	}
+	conn.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out.String()
+	}
+
+	want := "every 2 weeks on Monday, after completion, until 2027-01-01"
+	out := run("repeating", "--db", dbPath, "--show-rule")
+	if !strings.Contains(out, "RULE") || !strings.Contains(out, want) {
+		t.Fatalf("expected rule column, got %q", out)
+	}
+
+	out = run("repeating", "--db", dbPath, "--show-rule", "--json")
+	var tasks []map[string]any
+	if err := json.Unmarshal([]byte(out), &tasks); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0]["repeat_rule"] != want {
+		t.Fatalf("unexpected json: %s", out)
+	}
+
+	out = run("repeating", "--db", dbPath)
+	if strings.Contains(out, "RULE") {
+		t.Fatalf("expected no rule column without --show-rule, got %q", out)
+	}
+}
+
+func TestRepeatingNextAndShowOccurrences(t *testing.T) {
+	dbPath := writeTestDB(t)
+	today := time.Now()
+	until := today.AddDate(0, 0, 8)
+	offset := 2
+	update, err := repeat.BuildUpdate(repeat.Spec{
+		Mode:           repeat.ModeSchedule,
+		Unit:           repeat.UnitWeek,
+		Every:          1,
+		Anchor:         today,
+		EndDate:        &until,
+		DeadlineOffset: &offset,
+	})
+	if err != nil {
+		t.Fatalf("build rule: %v", err)
+	}
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	addRepeatColumns(t, conn)
+	if _, err := conn.Exec(`UPDATE TMTask SET rt1_recurrenceRule = ? WHERE uuid = 'T1'`, update.RecurrenceRule); err != nil {
+		t.Fatalf("set rule: %v", err)
+	}
+	conn.Close()
+
+	run := func(args ...string) string {
+		t.Helper()
+		out := &bytes.Buffer{}
+		app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out.String()
+	}
+
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format("2006-01-02") }
+	want := day(-2) + " (due " + day(0) + "), " + day(5) + " (due " + day(7) + ")"
+	out := run("repeating", "--db", dbPath, "--next", "5")
+	if !strings.Contains(out, "NEXT") || !strings.Contains(out, want) {
+		t.Fatalf("expected next column %q, got %q", want, out)
+	}
+
+	out = run("show", "--db", dbPath, "--id", "T1", "--occurrences", "5", "--json")
+	var item struct {
+		Occurrences []struct {
+			Start    string `json:"start"`
+			Deadline string `json:"deadline"`
+		} `json:"occurrences"`
+	}
+	if err := json.Unmarshal([]byte(out), &item); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if len(item.Occurrences) != 2 || item.Occurrences[1].Deadline != day(7) {
+		t.Fatalf("unexpected occurrences: %s", out)
+	}
+
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"show", "--db", dbPath, "--id", "P1", "--occurrences", "3"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "does not repeat") {
+		t.Fatalf("expected non-repeating error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewRestoreCommand builds the restore subcommand.
+func NewRestoreCommand(app *App) *cobra.Command {
+	var dbPath string
+	var id string
+	var yes bool
+	opts := TaskQueryOptions{
+		Status:     "any",
+		Limit:      200,
+		ExactMatch: true,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "restore [OPTIONS...]",
+		Short: "Restore trashed todos in place",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changedStatus := cmd.Flags().Changed("status")
+			opts.HasURLSet = cmd.Flags().Changed("has-url")
+			hasSelector := hasExplicitSelector(map[string]bool{"status": changedStatus}, opts)
+			if strings.TrimSpace(id) != "" && hasSelector {
+				return fmt.Errorf("Error: use either --id or query filters")
+			}
+			if strings.TrimSpace(id) == "" && !hasSelector {
+				return fmt.Errorf("Error: Must specify --id=ID or query filters")
+			}
+
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			var tasks []db.Task
+			if strings.TrimSpace(id) != "" {
+				task, err := store.TaskByID(id)
+				if err != nil {
+					return formatDBError(err)
+				}
+				if !task.Trashed {
+					return fmt.Errorf("Error: %s is not in Trash", id)
+				}
+				tasks = []db.Task{*task}
+			} else {
+				tasks, err = fetchTasks(store, store.TrashTasks, opts, false, []int{db.TaskTypeTodo})
+				if err != nil {
+					return formatDBError(err)
+				}
+			}
+			if len(tasks) == 0 {
+				return fmt.Errorf("Error: no tasks matched")
+			}
+			if app.DryRun {
+				return previewTasks(app.Out, tasks)
+			}
+			if len(tasks) > 1 && !yes {
+				return fmt.Errorf("Error: %d tasks matched (rerun with --yes to apply)", len(tasks))
+			}
+
+			targets := make([]things.RestoreTarget, 0, len(tasks))
+			entry := ActionEntry{
+				Type:  ActionRestore,
+				Items: make([]ActionItem, 0, len(tasks)),
+			}
+			for _, task := range tasks {
+				item := taskToActionItem(task)
+				targets = append(targets, restoreTarget(item))
+				entry.Items = append(entry.Items, item)
+			}
+			script, err := things.BuildRestoreScript(targets)
+			if err != nil {
+				return err
+			}
+			entry.Redo = []ActionStep{{Script: script}}
+
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+			warnUnrestoredHeadings(app, entry.Items)
+			logAction(app, entry)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().StringVar(&id, "id", "", "ID of the trashed todo to restore")
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm bulk restore")
+	addTaskQueryFlags(cmd, &opts, true, true)
+
+	return cmd
+}
+
+// restoreTarget maps a logged snapshot back to the list, project or area, and
+// start date the todo had before it was trashed.
+func restoreTarget(item ActionItem) things.RestoreTarget {
+	return things.RestoreTarget{
+		ID:        item.UUID,
+		List:      item.Start,
+		ProjectID: item.ProjectID,
+		AreaID:    item.AreaID,
+		StartDate: item.StartDate,
+	}
+}
+
+// warnUnrestoredHeadings reports todos that were under a heading: the restore
+// script can only return them to the heading's project.
+func warnUnrestoredHeadings(app *App, items []ActionItem) {
+	for _, item := range items {
+		if item.HeadingTitle != "" {
+			fmt.Fprintf(app.Err, "Warning: %q was not moved back under heading %q.\n", item.Title, item.HeadingTitle)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestRestoreByIDRunsRestoreScript(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"restore", "--db", dbPath, "--id", "TRASH1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `to do id "TRASH1"`) || !strings.Contains(script, `move restoredToDo to list "Anytime"`) {
+		t.Fatalf("unexpected restore script %q", script)
+	}
+	entries, _ := readActions()
+	if len(entries) != 1 || entries[0].Type != ActionRestore {
+		t.Fatalf("expected restore to be logged, got %+v", entries)
+	}
+}
+
+func TestRestoreRejectsUntrashedTask(t *testing.T) {
+	dbPath := writeTestDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"restore", "--db", dbPath, "--id", "T1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "not in Trash") {
+		t.Fatalf("expected not-in-trash error, got %v", err)
+	}
+	if runner.script != "" {
+		t.Fatalf("expected no script")
+	}
+}
+
+func TestUndoTrashRestoresOriginals(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	if err := appendAction(ActionEntry{
+		Type:  ActionTrash,
+		Items: []ActionItem{{UUID: "TRASH1", Title: "Trashed Task", Start: "Someday", ProjectID: "P1"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `to do id "TRASH1"`) || !strings.Contains(script, `project id "P1"`) {
+		t.Fatalf("expected in-place restore, got %q", script)
+	}
+}
+
+func TestRestoreHeadingTodoReportsHeading(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`UPDATE TMTask SET project = NULL, heading = 'H1' WHERE uuid = 'TRASH1'`); err != nil {
+		t.Fatalf("move under heading: %v", err)
+	}
+	conn.Close()
+
+	runner := &recordScriptRunner{}
+	errOut := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: errOut, Scripter: runner}
+	root := NewRoot(app)
+	root.SetArgs([]string{"restore", "--db", dbPath, "--id", "TRASH1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if script := requireScript(t, runner); !strings.Contains(script, `set project of restoredToDo to project id "P1"`) {
+		t.Fatalf("expected the heading's project, got %q", script)
+	}
+	if !strings.Contains(errOut.String(), `not moved back under heading "Heading"`) {
+		t.Fatalf("expected heading warning, got %q", errOut.String())
+	}
+}
@@ -20,7 +20,7 @@ func NewRoot(app *App) *cobra.Command {
 				printVersion(app.Out)
 				return ErrVersionPrinted
 			}
-			return nil
+			return applyProfile(app, cmd)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			printHelp(app.Out, formatHelpText(rootHelp, isTTY(app.Out)))
@@ -28,10 +28,14 @@ func NewRoot(app *App) *cobra.Command {
 		},
 	}
 
+	// Subcommands that prompt (such as the candidate picker) read app.In.
+	cmd.SetIn(app.In)
+
 	cmd.PersistentFlags().BoolVar(&app.Debug, "debug", false, "Enable debug mode")
 	cmd.PersistentFlags().BoolVar(&app.Foreground, "foreground", false, "Open Things in the foreground")
 	cmd.PersistentFlags().BoolVar(&app.DryRun, "dry-run", false, "Print the Things URL without opening it")
 	cmd.PersistentFlags().BoolVarP(&versionFlag, "version", "V", false, "Print version information")
+	cmd.PersistentFlags().StringVar(&app.ProfileName, "profile", "", "Config profile to use (overrides THINGS_PROFILE)")
 
 	cmd.AddCommand(NewAddCommand(app))
 	cmd.AddCommand(NewAddAreaCommand(app))
@@ -56,6 +60,7 @@ func NewRoot(app *App) *cobra.Command {
 	cmd.AddCommand(NewTagsCommand(app))
 	cmd.AddCommand(NewTasksCommand(app))
 	cmd.AddCommand(NewAuthCommand(app))
+	cmd.AddCommand(NewDoctorCommand(app))
 	cmd.AddCommand(NewUpdateCommand(app))
 	cmd.AddCommand(NewUpdateAreaCommand(app))
 	cmd.AddCommand(NewDeleteAreaCommand(app))
@@ -64,6 +69,24 @@ func NewRoot(app *App) *cobra.Command {
 	cmd.AddCommand(NewUndoCommand(app))
 	cmd.AddCommand(NewShowCommand(app))
 	cmd.AddCommand(NewSearchCommand(app))
+	cmd.AddCommand(NewImportJSONCommand(app))
+	cmd.AddCommand(NewTemplateCommand(app))
+	cmd.AddCommand(NewApplyCommand(app))
+	cmd.AddCommand(NewRedoCommand(app))
+	cmd.AddCommand(NewHistoryCommand(app))
+	cmd.AddCommand(NewRestoreCommand(app))
+	cmd.AddCommand(NewCompleteCommand(app))
+	cmd.AddCommand(NewCancelCommand(app))
+	cmd.AddCommand(NewReopenCommand(app))
+	cmd.AddCommand(NewAddTagCommand(app))
+	cmd.AddCommand(NewUpdateTagCommand(app))
+	cmd.AddCommand(NewDeleteTagCommand(app))
+	cmd.AddCommand(NewMergeTagsCommand(app))
+	cmd.AddCommand(NewHeadingsCommand(app))
+	cmd.AddCommand(NewMoveCommand(app))
+	cmd.AddCommand(NewRepeatCommand(app))
+	cmd.AddCommand(NewDBCommand(app))
+	cmd.AddCommand(NewQueryCommand(app))
 
 	cmd.SetHelpCommand(&cobra.Command{
 		Use:   "help [command]",
@@ -120,6 +143,8 @@ func NewRoot(app *App) *cobra.Command {
 				printHelp(app.Out, formatHelpText(tasksHelp, isTTY(app.Out)))
 			case "auth":
 				printHelp(app.Out, formatHelpText(authHelp, isTTY(app.Out)))
+			case "doctor":
+				printHelp(app.Out, formatHelpText(doctorHelp, isTTY(app.Out)))
 			case "show":
 				printHelp(app.Out, formatHelpText(showHelp, isTTY(app.Out)))
 			case "search":
@@ -138,6 +163,42 @@ func NewRoot(app *App) *cobra.Command {
 				printHelp(app.Out, formatHelpText(updateProjectHelp, isTTY(app.Out)))
 			case "delete-project":
 				printHelp(app.Out, formatHelpText(deleteProjectHelp, isTTY(app.Out)))
+			case "import-json":
+				printHelp(app.Out, formatHelpText(importJSONHelp, isTTY(app.Out)))
+			case "template":
+				printHelp(app.Out, formatHelpText(templateHelp, isTTY(app.Out)))
+			case "apply":
+				printHelp(app.Out, formatHelpText(applyHelp, isTTY(app.Out)))
+			case "redo":
+				printHelp(app.Out, formatHelpText(redoHelp, isTTY(app.Out)))
+			case "history":
+				printHelp(app.Out, formatHelpText(historyHelp, isTTY(app.Out)))
+			case "restore":
+				printHelp(app.Out, formatHelpText(restoreHelp, isTTY(app.Out)))
+			case "complete":
+				printHelp(app.Out, formatHelpText(completeHelp, isTTY(app.Out)))
+			case "cancel":
+				printHelp(app.Out, formatHelpText(cancelHelp, isTTY(app.Out)))
+			case "reopen":
+				printHelp(app.Out, formatHelpText(reopenHelp, isTTY(app.Out)))
+			case "add-tag":
+				printHelp(app.Out, formatHelpText(addTagHelp, isTTY(app.Out)))
+			case "update-tag":
+				printHelp(app.Out, formatHelpText(updateTagHelp, isTTY(app.Out)))
+			case "delete-tag":
+				printHelp(app.Out, formatHelpText(deleteTagHelp, isTTY(app.Out)))
+			case "merge-tags":
+				printHelp(app.Out, formatHelpText(mergeTagsHelp, isTTY(app.Out)))
+			case "headings":
+				printHelp(app.Out, formatHelpText(headingsHelp, isTTY(app.Out)))
+			case "move":
+				printHelp(app.Out, formatHelpText(moveHelp, isTTY(app.Out)))
+			case "repeat":
+				printHelp(app.Out, formatHelpText(repeatHelp, isTTY(app.Out)))
+			case "db":
+				printHelp(app.Out, formatHelpText(dbHelp, isTTY(app.Out)))
+			case "query":
+				printHelp(app.Out, formatHelpText(queryHelp, isTTY(app.Out)))
 			case "help":
 				printHelp(app.Out, formatHelpText(rootHelp, isTTY(app.Out)))
 			default:
@@ -149,6 +210,9 @@ func NewRoot(app *App) *cobra.Command {
 
 	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
 		name := cmd.Name()
+		if parent := cmd.Parent(); parent != nil && parent.Parent() != nil {
+			name = parent.Name() + " " + name
+		}
 		switch name {
 		case "things":
 			printHelp(app.Out, formatHelpText(rootHelp, isTTY(app.Out)))
@@ -200,6 +264,8 @@ func NewRoot(app *App) *cobra.Command {
 			printHelp(app.Out, formatHelpText(tasksHelp, isTTY(app.Out)))
 		case "auth":
 			printHelp(app.Out, formatHelpText(authHelp, isTTY(app.Out)))
+		case "doctor":
+			printHelp(app.Out, formatHelpText(doctorHelp, isTTY(app.Out)))
 		case "show":
 			printHelp(app.Out, formatHelpText(showHelp, isTTY(app.Out)))
 		case "search":
@@ -218,6 +284,42 @@ func NewRoot(app *App) *cobra.Command {
 			printHelp(app.Out, formatHelpText(updateProjectHelp, isTTY(app.Out)))
 		case "delete-project":
 			printHelp(app.Out, formatHelpText(deleteProjectHelp, isTTY(app.Out)))
+		case "import-json":
+			printHelp(app.Out, formatHelpText(importJSONHelp, isTTY(app.Out)))
+		case "template", "template apply", "template export":
+			printHelp(app.Out, formatHelpText(templateHelp, isTTY(app.Out)))
+		case "apply":
+			printHelp(app.Out, formatHelpText(applyHelp, isTTY(app.Out)))
+		case "redo":
+			printHelp(app.Out, formatHelpText(redoHelp, isTTY(app.Out)))
+		case "history":
+			printHelp(app.Out, formatHelpText(historyHelp, isTTY(app.Out)))
+		case "restore":
+			printHelp(app.Out, formatHelpText(restoreHelp, isTTY(app.Out)))
+		case "complete":
+			printHelp(app.Out, formatHelpText(completeHelp, isTTY(app.Out)))
+		case "cancel":
+			printHelp(app.Out, formatHelpText(cancelHelp, isTTY(app.Out)))
+		case "reopen":
+			printHelp(app.Out, formatHelpText(reopenHelp, isTTY(app.Out)))
+		case "add-tag":
+			printHelp(app.Out, formatHelpText(addTagHelp, isTTY(app.Out)))
+		case "update-tag":
+			printHelp(app.Out, formatHelpText(updateTagHelp, isTTY(app.Out)))
+		case "delete-tag":
+			printHelp(app.Out, formatHelpText(deleteTagHelp, isTTY(app.Out)))
+		case "merge-tags":
+			printHelp(app.Out, formatHelpText(mergeTagsHelp, isTTY(app.Out)))
+		case "headings":
+			printHelp(app.Out, formatHelpText(headingsHelp, isTTY(app.Out)))
+		case "move":
+			printHelp(app.Out, formatHelpText(moveHelp, isTTY(app.Out)))
+		case "repeat", "repeat pause", "repeat resume", "repeat skip":
+			printHelp(app.Out, formatHelpText(repeatHelp, isTTY(app.Out)))
+		case "db", "db backup", "db backups", "db restore-backup":
+			printHelp(app.Out, formatHelpText(dbHelp, isTTY(app.Out)))
+		case "query", "query save", "query list", "query run", "query delete":
+			printHelp(app.Out, formatHelpText(queryHelp, isTTY(app.Out)))
 		default:
 			printHelp(app.Out, formatHelpText(rootHelp, isTTY(app.Out)))
 		}
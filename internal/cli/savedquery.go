@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SavedQuery is a named rich query with optional default sort and fields.
+type SavedQuery struct {
+	Name   string `json:"name"`
+	Query  string `json:"query"`
+	Sort   string `json:"sort,omitempty"`
+	Select string `json:"select,omitempty"`
+}
+
+var savedQueryNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+func savedQueriesPath() (string, error) {
+	return configFilePath("queries.json")
+}
+
+// readSavedQueries returns saved queries keyed by name. A missing file means
+// no saved queries.
+func readSavedQueries() (map[string]SavedQuery, error) {
+	path, err := savedQueriesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]SavedQuery{}, nil
+		}
+		return nil, err
+	}
+	var list []SavedQuery
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("Error: invalid saved queries file %s: %v", path, err)
+	}
+	queries := make(map[string]SavedQuery, len(list))
+	for _, query := range list {
+		queries[query.Name] = query
+	}
+	return queries, nil
+}
+
+func writeSavedQueries(queries map[string]SavedQuery) error {
+	path, err := savedQueriesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sortedSavedQueries(queries), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+func sortedSavedQueries(queries map[string]SavedQuery) []SavedQuery {
+	list := make([]SavedQuery, 0, len(queries))
+	for _, query := range queries {
+		list = append(list, query)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func validateSavedQueryName(name string) (string, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "@")
+	if !savedQueryNamePattern.MatchString(name) {
+		return "", fmt.Errorf("Error: invalid query name %q (use letters, digits, - and _)", name)
+	}
+	return name, nil
+}
+
+// savedQueryResolver looks up @name references in queries.
+func savedQueryResolver(queries map[string]SavedQuery) queryResolver {
+	return func(name string) (string, bool, error) {
+		query, ok := queries[name]
+		return query.Query, ok, nil
+	}
+}
+
+// defaultQueryResolver reads the saved queries file the first time a query
+// references one, so queries without @name never touch it.
+func defaultQueryResolver() queryResolver {
+	var resolve queryResolver
+	return func(name string) (string, bool, error) {
+		if resolve == nil {
+			queries, err := readSavedQueries()
+			if err != nil {
+				return "", false, err
+			}
+			resolve = savedQueryResolver(queries)
+		}
+		return resolve(name)
+	}
+}
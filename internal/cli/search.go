@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
@@ -19,6 +21,9 @@ func NewSearchCommand(app *App) *cobra.Command {
 	var selectRaw string
 	var asJSON bool
 	var noHeader bool
+	var templateFlags taskTemplateFlags
+	var ranked bool
+	var reindex bool
 
 	cmd := &cobra.Command{
 		Use:   "search [--] [-|QUERY]",
@@ -32,10 +37,18 @@ func NewSearchCommand(app *App) *cobra.Command {
 			if query == "" && strings.TrimSpace(opts.Query) == "" {
 				return fmt.Errorf("Error: query required")
 			}
-			if query != "" && strings.TrimSpace(opts.Query) != "" {
+			if reindex && !ranked {
+				return fmt.Errorf("Error: --reindex requires --ranked")
+			}
+			if ranked && query == "" {
+				return fmt.Errorf("Error: --ranked requires a QUERY argument")
+			}
+			// With --ranked, QUERY goes to the full-text index and --query
+			// narrows its hits like any other filter.
+			if !ranked && query != "" && strings.TrimSpace(opts.Query) != "" {
 				return fmt.Errorf("Error: use either QUERY argument or --query")
 			}
-			if query != "" {
+			if query != "" && !ranked {
 				opts.Search = query
 			}
 
@@ -46,10 +59,24 @@ func NewSearchCommand(app *App) *cobra.Command {
 			defer store.Close()
 
 			opts.HasURLSet = cmd.Flags().Changed("has-url")
-			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader)
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
 			if err != nil {
 				return err
 			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
+			if ranked {
+				if outputOpts.Select == nil && (outputOpts.Format == "table" || outputOpts.Format == "csv") {
+					outputOpts.Select = defaultRankedSearchFields
+				}
+				highlight := outputOpts.Format == "table" && isTTY(app.Out)
+				tasks, err := rankedSearch(app, store, query, opts, reindex, highlight)
+				if err != nil {
+					return formatDBError(err)
+				}
+				return printTasks(app.Out, tasks, outputOpts)
+			}
 			tasks, err := fetchTasks(store, store.Tasks, opts, false, []int{db.TaskTypeTodo})
 			if err != nil {
 				return formatDBError(err)
@@ -60,8 +87,84 @@ func NewSearchCommand(app *App) *cobra.Command {
 
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().BoolVar(&ranked, "ranked", false, "Rank matches with the full-text index and show snippets")
+	cmd.Flags().BoolVar(&reindex, "reindex", false, "Rebuild the full-text index before a ranked search")
 	addTaskQueryFlags(cmd, &opts, false, true)
-	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
 
 	return cmd
 }
+
+var defaultRankedSearchFields = []string{"uuid", "title", "project", "snippet"}
+
+// rankedSearch refreshes the full-text index, then lists the todos it
+// matched through the usual filters, best match first unless --sort is
+// given. Matched terms are bold when highlight is set and bracketed
+// otherwise.
+func rankedSearch(app *App, store *db.Store, text string, opts TaskQueryOptions, reindex bool, highlight bool) ([]db.Task, error) {
+	path, err := db.SearchIndexPath()
+	if err != nil {
+		return nil, err
+	}
+	index, err := db.OpenSearchIndex(path)
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+	stats, err := store.RefreshSearchIndex(index, reindex)
+	if err != nil {
+		return nil, err
+	}
+	if reindex {
+		fmt.Fprintf(app.Err, "Indexed %d todos in %s\n", stats.Total, index.Path())
+	}
+
+	openMark, closeMark := "[", "]"
+	if highlight {
+		openMark, closeMark = "\x1b[1m", "\x1b[0m"
+	}
+	hits, err := index.Search(text, openMark, closeMark)
+	if err != nil {
+		return nil, err
+	}
+	rank := make(map[string]int, len(hits))
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		rank[hit.UUID] = i
+		ids[i] = hit.UUID
+	}
+	idList, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	runner := func(filter db.TaskFilter) ([]db.Task, error) {
+		const inHits = "t.uuid IN (SELECT value FROM json_each(?))"
+		if filter.Where == "" {
+			filter.Where = inHits
+		} else {
+			filter.Where = "(" + filter.Where + ") AND " + inHits
+		}
+		filter.WhereArgs = append(append([]any{}, filter.WhereArgs...), string(idList))
+		return store.Tasks(filter)
+	}
+
+	// Offset and limit apply to the ranked list, so fetch every match first.
+	all := opts
+	all.Limit = 0
+	all.Offset = 0
+	tasks, err := fetchTasks(store, runner, all, false, []int{db.TaskTypeTodo})
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		hit := hits[rank[tasks[i].UUID]]
+		tasks[i].Score = hit.Score
+		tasks[i].Snippet = hit.Snippet
+	}
+	if strings.TrimSpace(opts.Sort) == "" {
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return rank[tasks[i].UUID] < rank[tasks[j].UUID]
+		})
+	}
+	return applyOffsetLimit(tasks, opts.Limit, opts.Offset), nil
+}
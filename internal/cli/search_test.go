@@ -2,8 +2,12 @@ package cli
 
 import (
 	"bytes"
+	"encoding/json"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
 )
 
 func TestSearchCommandEmpty(t *testing.T) {
@@ -46,3 +50,56 @@ func TestSearchCommandWithQuery(t *testing.T) {
 		t.Fatalf("unexpected output: %q", output)
 	}
 }
+
+func TestSearchCommandRanked(t *testing.T) {
+	dbPath := writeTestDB(t)
+	t.Setenv("THINGS_SEARCH_INDEX", filepath.Join(t.TempDir(), "search.sqlite"))
+
+	run := func(args ...string) (string, error) {
+		t.Helper()
+		app := &App{
+			In:  strings.NewReader(""),
+			Out: &bytes.Buffer{},
+			Err: &bytes.Buffer{},
+		}
+		root := NewRoot(app)
+		root.SetArgs(append([]string{"search", "--db", dbPath}, args...))
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		err := root.Execute()
+		return app.Out.(*bytes.Buffer).String(), err
+	}
+
+	// Checklist items are only searchable through the index.
+	output, err := run("--ranked", "--no-header", "--select", "title,snippet", "check")
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if !strings.Contains(output, "Task One") || !strings.Contains(output, "[Check] Item") {
+		t.Fatalf("unexpected ranked output: %q", output)
+	}
+
+	output, err = run("--ranked", "--json", "note")
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	var tasks []db.Task
+	if err := json.Unmarshal([]byte(output), &tasks); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].UUID != "T1" || tasks[0].Score <= 0 || tasks[0].Snippet != "Some [notes]" {
+		t.Fatalf("unexpected ranked json: %#v", tasks)
+	}
+
+	output, err = run("--ranked", "--query", "title:nothing", "check")
+	if err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	if strings.Contains(output, "Task One") {
+		t.Fatalf("expected --query to narrow ranked hits, got %q", output)
+	}
+
+	if _, err := run("--reindex", "check"); err == nil || !strings.Contains(err.Error(), "--reindex requires --ranked") {
+		t.Fatalf("expected --reindex error, got %v", err)
+	}
+}
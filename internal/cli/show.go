@@ -16,6 +16,7 @@ func NewShowCommand(app *App) *cobra.Command {
 	var id string
 	var asJSON bool
 	var noHeader bool
+	var occurrences int
 
 	cmd := &cobra.Command{
 		Use:   "show [OPTIONS...] [--] [-|QUERY]",
@@ -34,6 +35,10 @@ func NewShowCommand(app *App) *cobra.Command {
 				return fmt.Errorf("Must specify --id=ID or query")
 			}
 
+			if occurrences < 0 {
+				return fmt.Errorf("Error: --occurrences must be >= 0")
+			}
+
 			store, _, err := db.OpenDefault(dbPath)
 			if err != nil {
 				return formatDBError(err)
@@ -48,20 +53,25 @@ func NewShowCommand(app *App) *cobra.Command {
 					}
 					return formatDBError(err)
 				}
-				return printItem(app.Out, item, asJSON, noHeader)
+				return showItem(app, store, item, occurrences, asJSON, noHeader)
 			}
 
-			items, err := store.ItemsByTitle(query)
+			item, err := store.ResolveItem(query)
+			var ambiguous *db.AmbiguousError
+			if errors.As(err, &ambiguous) {
+				var picked string
+				if picked, err = resolvePicked(app.In, app.Err, "", err); err != nil {
+					return fmt.Errorf("Error: %s", err)
+				}
+				item, err = store.ItemByID(picked)
+			}
 			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("Error: item not found")
+				}
 				return formatDBError(err)
 			}
-			if len(items) == 0 {
-				return fmt.Errorf("Error: item not found")
-			}
-			if len(items) > 1 {
-				return fmt.Errorf("Error: found %d items with that title; use --id for an exact match", len(items))
-			}
-			return printItem(app.Out, &items[0], asJSON, noHeader)
+			return showItem(app, store, item, occurrences, asJSON, noHeader)
 		},
 	}
 
@@ -71,6 +81,18 @@ func NewShowCommand(app *App) *cobra.Command {
 	flags.StringVar(&id, "id", "", "ID of area/project/tag/todo")
 	flags.BoolVarP(&asJSON, "json", "j", false, "Output JSON")
 	flags.BoolVar(&noHeader, "no-header", false, "Suppress header row")
+	flags.IntVar(&occurrences, "occurrences", 0, "Show the next N occurrences of a repeating todo or project")
 
 	return cmd
 }
+
+func showItem(app *App, store *db.Store, item *db.Item, occurrences int, asJSON bool, noHeader bool) error {
+	if occurrences > 0 {
+		projected, err := itemOccurrences(store, item.UUID, occurrences)
+		if err != nil {
+			return err
+		}
+		item.Occurrences = projected
+	}
+	return printItem(app.Out, item, asJSON, noHeader)
+}
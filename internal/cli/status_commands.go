@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewCompleteCommand builds the complete subcommand.
+func NewCompleteCommand(app *App) *cobra.Command {
+	return newStatusCommand(app, "complete", "Mark todos completed", db.StatusCompleted, "incomplete")
+}
+
+// NewCancelCommand builds the cancel subcommand.
+func NewCancelCommand(app *App) *cobra.Command {
+	return newStatusCommand(app, "cancel", "Mark todos canceled", db.StatusCanceled, "incomplete")
+}
+
+// NewReopenCommand builds the reopen subcommand.
+func NewReopenCommand(app *App) *cobra.Command {
+	return newStatusCommand(app, "reopen", "Reopen completed or canceled todos", db.StatusIncomplete, "any")
+}
+
+func newStatusCommand(app *App, use string, short string, status int, defaultStatus string) *cobra.Command {
+	var dbPath string
+	var id string
+	var yes bool
+	opts := TaskQueryOptions{
+		Status:     defaultStatus,
+		Limit:      200,
+		ExactMatch: true,
+	}
+
+	cmd := &cobra.Command{
+		Use:   use + " [OPTIONS...]",
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			changedStatus := cmd.Flags().Changed("status")
+			opts.HasURLSet = cmd.Flags().Changed("has-url")
+			hasSelector := hasExplicitSelector(map[string]bool{"status": changedStatus}, opts)
+			if strings.TrimSpace(id) != "" && hasSelector {
+				return fmt.Errorf("Error: use either --id or query filters")
+			}
+			if strings.TrimSpace(id) == "" && !hasSelector {
+				return fmt.Errorf("Error: Must specify --id=ID or query filters")
+			}
+
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			var tasks []db.Task
+			if strings.TrimSpace(id) != "" {
+				task, err := store.TaskByID(id)
+				if err != nil {
+					return formatDBError(err)
+				}
+				if task.Status == status {
+					return fmt.Errorf("Error: %s is already %s", id, db.StatusLabel(status))
+				}
+				tasks = []db.Task{*task}
+			} else {
+				matched, err := fetchTasks(store, store.Tasks, opts, false, []int{db.TaskTypeTodo})
+				if err != nil {
+					return formatDBError(err)
+				}
+				for _, task := range matched {
+					if task.Status != status {
+						tasks = append(tasks, task)
+					}
+				}
+			}
+			if len(tasks) == 0 {
+				return fmt.Errorf("Error: no tasks matched")
+			}
+			if app.DryRun {
+				return previewTasks(app.Out, tasks)
+			}
+			if len(tasks) > 1 && !yes {
+				return fmt.Errorf("Error: %d tasks matched (rerun with --yes to apply)", len(tasks))
+			}
+
+			targets := make([]things.StatusTarget, 0, len(tasks))
+			entry := ActionEntry{
+				Type:  ActionStatus,
+				Items: make([]ActionItem, 0, len(tasks)),
+			}
+			for _, task := range tasks {
+				targets = append(targets, things.StatusTarget{ID: task.UUID, Status: scriptStatus(status)})
+				item := taskToActionItem(task)
+				expected := planStateFromTask(task)
+				expected.Status = db.StatusLabel(status)
+				item.Expected = &expected
+				entry.Items = append(entry.Items, item)
+			}
+			script, err := things.BuildStatusScript(targets)
+			if err != nil {
+				return err
+			}
+			entry.Redo = []ActionStep{{Script: script}}
+
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+			logAction(app, entry)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
+	cmd.Flags().StringVar(&id, "id", "", "ID of the todo to "+use)
+	cmd.Flags().BoolVar(&yes, "yes", false, "Confirm changing multiple todos")
+	addTaskQueryFlags(cmd, &opts, true, true)
+
+	return cmd
+}
+
+// scriptStatus maps a database status to the AppleScript status keyword.
+func scriptStatus(status int) string {
+	switch status {
+	case db.StatusCompleted:
+		return things.StatusCompleted
+	case db.StatusCanceled:
+		return things.StatusCanceled
+	default:
+		return things.StatusOpen
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCompleteByIDRunsStatusScript(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"complete", "--db", dbPath, "--id", "T1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `set status of to do id "T1" to completed`) {
+		t.Fatalf("unexpected script %q", script)
+	}
+	entries, _ := readActions()
+	if len(entries) != 1 || entries[0].Type != ActionStatus {
+		t.Fatalf("expected status action to be logged, got %+v", entries)
+	}
+	if entries[0].Items[0].Expected == nil || entries[0].Items[0].Expected.Status != "completed" {
+		t.Fatalf("expected completed state, got %+v", entries[0].Items[0].Expected)
+	}
+}
+
+func TestReopenBulkRequiresYesAndSkipsOpenTasks(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"reopen", "--db", dbPath, "--search", "Task"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected --yes error, got %v", err)
+	}
+
+	root = NewRoot(app)
+	root.SetArgs([]string{"reopen", "--db", dbPath, "--search", "Task", "--yes"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `to do id "COMP1" to open`) || !strings.Contains(script, `to do id "CANC1" to open`) {
+		t.Fatalf("expected completed and canceled todos reopened, got %q", script)
+	}
+	if strings.Contains(script, `"T1"`) {
+		t.Fatalf("expected open todos to be skipped, got %q", script)
+	}
+}
+
+func TestCancelRejectsAlreadyCanceled(t *testing.T) {
+	dbPath := writeTestDB(t)
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"cancel", "--db", dbPath, "--id", "CANC1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "already canceled") {
+		t.Fatalf("expected already canceled error, got %v", err)
+	}
+}
+
+func TestUndoCompleteReopens(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	if err := appendAction(ActionEntry{
+		Type:  ActionStatus,
+		Items: []ActionItem{{UUID: "COMP1", Title: "Completed Task", Status: 0}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `set status of to do id "COMP1" to open`) {
+		t.Fatalf("expected reopen script, got %q", script)
+	}
+}
@@ -10,6 +10,7 @@ func NewTagsCommand(app *App) *cobra.Command {
 	var dbPath string
 	var asJSON bool
 	var noHeader bool
+	var tree bool
 
 	cmd := &cobra.Command{
 		Use:   "tags",
@@ -25,6 +26,9 @@ func NewTagsCommand(app *App) *cobra.Command {
 			if err != nil {
 				return formatDBError(err)
 			}
+			if tree {
+				return printTagTree(app.Out, tags, asJSON, noHeader)
+			}
 			return printTags(app.Out, tags, asJSON, noHeader)
 		},
 	}
@@ -33,6 +37,7 @@ func NewTagsCommand(app *App) *cobra.Command {
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	cmd.Flags().BoolVarP(&asJSON, "json", "j", false, "Output JSON")
 	cmd.Flags().BoolVar(&noHeader, "no-header", false, "Suppress header row")
+	cmd.Flags().BoolVar(&tree, "tree", false, "Show the tag hierarchy with usage counts")
 
 	return cmd
 }
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// TagNode is a tag with its children. Total counts uses of the tag and all
+// of its descendants.
+type TagNode struct {
+	db.Tag
+	Total    int        `json:"total"`
+	Children []*TagNode `json:"children,omitempty"`
+}
+
+// buildTagTree arranges tags by ParentID, keeping their input order. Tags
+// whose parent is missing are treated as roots.
+func buildTagTree(tags []db.Tag) []*TagNode {
+	nodes := make(map[string]*TagNode, len(tags))
+	for _, tag := range tags {
+		nodes[tag.UUID] = &TagNode{Tag: tag}
+	}
+	roots := []*TagNode{}
+	for _, tag := range tags {
+		node := nodes[tag.UUID]
+		parent, ok := nodes[tag.ParentID]
+		if tag.ParentID == "" || !ok || parent == node {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	for _, root := range roots {
+		sumTagUsage(root, map[*TagNode]bool{})
+	}
+	return roots
+}
+
+func sumTagUsage(node *TagNode, seen map[*TagNode]bool) int {
+	if seen[node] {
+		return 0
+	}
+	seen[node] = true
+	node.Total = node.Usage
+	for _, child := range node.Children {
+		node.Total += sumTagUsage(child, seen)
+	}
+	return node.Total
+}
+
+func printTagTree(out io.Writer, tags []db.Tag, asJSON bool, noHeader bool) error {
+	roots := buildTagTree(tags)
+	if asJSON {
+		enc := json.NewEncoder(out)
+		return enc.Encode(roots)
+	}
+	w := tabwriter.NewWriter(out, 0, 2, 2, ' ', 0)
+	if !noHeader {
+		fmt.Fprintln(w, "TITLE\tUSAGE\tTOTAL\tSHORTCUT\tUUID")
+	}
+	var walk func(nodes []*TagNode, depth int)
+	walk = func(nodes []*TagNode, depth int) {
+		for _, node := range nodes {
+			fmt.Fprintf(w, "%s%s\t%d\t%d\t%s\t%s\n", strings.Repeat("  ", depth), node.Title, node.Usage, node.Total, node.Shortcut, node.UUID)
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(roots, 0)
+	return w.Flush()
+}
+
+// tagAncestors maps each lowercased tag title to the titles of its parent,
+// grandparent, and so on.
+func tagAncestors(tags []db.Tag) map[string][]string {
+	byID := make(map[string]db.Tag, len(tags))
+	for _, tag := range tags {
+		byID[tag.UUID] = tag
+	}
+	ancestors := make(map[string][]string, len(tags))
+	for _, tag := range tags {
+		seen := map[string]bool{tag.UUID: true}
+		parentID := tag.ParentID
+		for parentID != "" && !seen[parentID] {
+			parent, ok := byID[parentID]
+			if !ok {
+				break
+			}
+			seen[parentID] = true
+			ancestors[strings.ToLower(tag.Title)] = append(ancestors[strings.ToLower(tag.Title)], parent.Title)
+			parentID = parent.ParentID
+		}
+	}
+	return ancestors
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func writeTagTreeDB(t *testing.T) string {
+	t.Helper()
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+	for _, stmt := range []string{
+		`INSERT INTO TMTag (uuid, title) VALUES ('ROOT', 'work');`,
+		`INSERT INTO TMTag (uuid, title, parent) VALUES ('MID', 'clients', 'ROOT');`,
+		`UPDATE TMTag SET parent = 'MID' WHERE uuid = 'TAG1';`,
+	} {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	return dbPath
+}
+
+func TestTagsTreeOutput(t *testing.T) {
+	dbPath := writeTagTreeDB(t)
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"tags", "--db", dbPath, "--tree", "--no-header"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("tags failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[0], "work ") || !strings.HasPrefix(lines[1], "  clients ") || !strings.HasPrefix(lines[2], "    urgent ") {
+		t.Fatalf("unexpected tree:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[0]); fields[1] != "0" || fields[2] != "1" {
+		t.Fatalf("expected usage 0 and total 1 for root, got %q", lines[0])
+	}
+}
+
+func TestTasksTagRecursiveFilter(t *testing.T) {
+	dbPath := writeTagTreeDB(t)
+	for _, args := range [][]string{
+		{"tasks", "--db", dbPath, "--tag", "work", "--tag-recursive", "--no-header"},
+		{"tasks", "--db", dbPath, "--query", "tag:work", "--tag-recursive", "--no-header"},
+	} {
+		out := &bytes.Buffer{}
+		app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("tasks failed: %v", err)
+		}
+		if !strings.Contains(out.String(), "Task One") {
+			t.Fatalf("expected descendant-tagged task for %v, got %q", args, out.String())
+		}
+	}
+
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"tasks", "--db", dbPath, "--query", "tag:work", "--no-header"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("tasks failed: %v", err)
+	}
+	if strings.Contains(out.String(), "Task One") {
+		t.Fatalf("expected no match without --tag-recursive, got %q", out.String())
+	}
+}
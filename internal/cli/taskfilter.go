@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -15,6 +16,7 @@ type TaskQueryOptions struct {
 	Project          string
 	Area             string
 	Tag              string
+	TagRecursive     bool
 	Search           string
 	Query            string
 	Limit            int
@@ -31,6 +33,16 @@ type TaskQueryOptions struct {
 	HasURL           bool
 	HasURLSet        bool
 	Sort             string
+	Explain          bool
+	// ExactMatch resolves --project, --area, and --tag by exact title or ID
+	// only; commands that write set it so a typo cannot select other items.
+	ExactMatch bool
+
+	explainOut func() io.Writer
+	// pickIn and pickErr, when set, let an ambiguous --project, --area, or
+	// --tag title prompt for a choice.
+	pickIn  func() io.Reader
+	pickErr func() io.Writer
 }
 
 type TaskSortField struct {
@@ -38,6 +50,35 @@ type TaskSortField struct {
 	Desc  bool
 }
 
+// resolvePicked settles an ambiguous filter title; see resolvePicked.
+func (opts TaskQueryOptions) resolvePicked(id string, err error) (string, error) {
+	if opts.pickIn == nil || opts.pickErr == nil {
+		return id, err
+	}
+	return resolvePicked(opts.pickIn(), opts.pickErr(), id, err)
+}
+
+func (opts TaskQueryOptions) resolveProjectID(store *db.Store, input string) (string, error) {
+	if opts.ExactMatch {
+		return store.ResolveProjectID(input)
+	}
+	return store.MatchProjectID(input)
+}
+
+func (opts TaskQueryOptions) resolveAreaID(store *db.Store, input string) (string, error) {
+	if opts.ExactMatch {
+		return store.ResolveAreaID(input)
+	}
+	return store.MatchAreaID(input)
+}
+
+func (opts TaskQueryOptions) resolveTagID(store *db.Store, input string) (string, error) {
+	if opts.ExactMatch {
+		return store.ResolveTagID(input)
+	}
+	return store.MatchTagID(input)
+}
+
 func buildTaskFilter(store *db.Store, opts TaskQueryOptions) (db.TaskFilter, []TaskSortField, error) {
 	statusFilter, err := db.ParseStatus(opts.Status)
 	if err != nil {
@@ -51,7 +92,7 @@ func buildTaskFilter(store *db.Store, opts TaskQueryOptions) (db.TaskFilter, []T
 
 	projectID := ""
 	if opts.Project != "" {
-		projectID, err = store.ResolveProjectID(opts.Project)
+		projectID, err = opts.resolvePicked(opts.resolveProjectID(store, opts.Project))
 		if err != nil {
 			return db.TaskFilter{}, nil, fmt.Errorf("Error: %s", err)
 		}
@@ -59,7 +100,7 @@ func buildTaskFilter(store *db.Store, opts TaskQueryOptions) (db.TaskFilter, []T
 
 	areaID := ""
 	if opts.Area != "" {
-		areaID, err = store.ResolveAreaID(opts.Area)
+		areaID, err = opts.resolvePicked(opts.resolveAreaID(store, opts.Area))
 		if err != nil {
 			return db.TaskFilter{}, nil, fmt.Errorf("Error: %s", err)
 		}
@@ -67,7 +108,7 @@ func buildTaskFilter(store *db.Store, opts TaskQueryOptions) (db.TaskFilter, []T
 
 	tagID := ""
 	if opts.Tag != "" {
-		tagID, err = store.ResolveTagID(opts.Tag)
+		tagID, err = opts.resolvePicked(opts.resolveTagID(store, opts.Tag))
 		if err != nil {
 			return db.TaskFilter{}, nil, fmt.Errorf("Error: %s", err)
 		}
@@ -85,6 +126,7 @@ func buildTaskFilter(store *db.Store, opts TaskQueryOptions) (db.TaskFilter, []T
 		ProjectID:             projectID,
 		AreaID:                areaID,
 		TagID:                 tagID,
+		TagRecursive:          opts.TagRecursive,
 		Search:                opts.Search,
 		Limit:                 opts.Limit,
 		Offset:                opts.Offset,
@@ -12,6 +12,7 @@ func addTaskQueryFlags(cmd *cobra.Command, opts *TaskQueryOptions, includeSearch
 	flags.StringVarP(&opts.Tag, "filter-tag", "t", "", "Filter by tag title or ID")
 	flags.StringVar(&opts.Tag, "filtertag", "", "Alias for --filter-tag")
 	flags.StringVar(&opts.Tag, "tag", "", "Alias for --filter-tag")
+	flags.BoolVar(&opts.TagRecursive, "tag-recursive", false, "Tag filters (and tag: queries) also match descendant tags")
 	if includeSearch {
 		flags.StringVar(&opts.Search, "search", "", "Search title or notes (case-insensitive substring)")
 	}
@@ -22,7 +23,7 @@ func addTaskQueryFlags(cmd *cobra.Command, opts *TaskQueryOptions, includeSearch
 	flags.IntVar(&opts.Offset, "offset", 0, "Offset results for pagination")
 	flags.BoolVar(&opts.IncludeTrashed, "include-trashed", false, "Include trashed tasks")
 	flags.BoolVar(&opts.All, "all", false, "Include completed, canceled, and trashed tasks")
-	flags.BoolVarP(&opts.IncludeChecklist, "recursive", "r", false, "Include checklist items in JSON output")
+	flags.BoolVarP(&opts.IncludeChecklist, "recursive", "r", false, "Include checklist items in JSON, outline, and template output")
 	flags.StringVar(&opts.CreatedBefore, "created-before", "", "Filter tasks created before (YYYY-MM-DD or RFC3339)")
 	flags.StringVar(&opts.CreatedAfter, "created-after", "", "Filter tasks created after (YYYY-MM-DD or RFC3339)")
 	flags.StringVar(&opts.ModifiedBefore, "modified-before", "", "Filter tasks modified before (YYYY-MM-DD or RFC3339)")
@@ -31,12 +32,22 @@ func addTaskQueryFlags(cmd *cobra.Command, opts *TaskQueryOptions, includeSearch
 	flags.StringVar(&opts.StartBefore, "start-before", "", "Filter tasks starting before (YYYY-MM-DD)")
 	flags.BoolVar(&opts.HasURL, "has-url", false, "Filter tasks with URLs in notes")
 	flags.StringVar(&opts.Sort, "sort", "", "Sort by fields (e.g. created,-deadline,title)")
+	flags.BoolVar(&opts.Explain, "explain", false, "Print the SQL the query compiles to instead of running it")
+	opts.explainOut = cmd.OutOrStdout
+	opts.pickIn = cmd.InOrStdin
+	opts.pickErr = cmd.ErrOrStderr
 }
 
-func addTaskOutputFlags(cmd *cobra.Command, format *string, selectRaw *string, asJSON *bool, noHeader *bool) {
+func addTaskOutputFlags(cmd *cobra.Command, format *string, selectRaw *string, asJSON *bool, noHeader *bool, templateFlags *taskTemplateFlags) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[taskListAnnotation] = "true"
 	flags := cmd.Flags()
-	flags.StringVar(format, "format", "", "Output format: table, json, jsonl, csv")
+	flags.StringVar(format, "format", "", "Output format: table, json, jsonl, csv, markdown, org, taskpaper")
 	flags.StringVar(selectRaw, "select", "", "Select fields (comma-separated)")
 	flags.BoolVarP(asJSON, "json", "j", false, "Output JSON")
 	flags.BoolVar(noHeader, "no-header", false, "Suppress header row")
+	flags.StringVar(&templateFlags.Text, "template", "", "Render each task with a Go text/template (e.g. '{{.Title}} {{.Deadline | default \"none\"}}')")
+	flags.StringVar(&templateFlags.File, "template-file", "", "Read the --template text from a file")
 }
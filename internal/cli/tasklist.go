@@ -17,6 +17,7 @@ func newTaskListCommand(app *App, use string, short string, defaultStatus string
 	var selectRaw string
 	var asJSON bool
 	var noHeader bool
+	var templateFlags taskTemplateFlags
 
 	cmd := &cobra.Command{
 		Use:   use,
@@ -29,10 +30,13 @@ func newTaskListCommand(app *App, use string, short string, defaultStatus string
 			defer store.Close()
 
 			opts.HasURLSet = cmd.Flags().Changed("has-url")
-			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader)
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
 			if err != nil {
 				return err
 			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
 			tasks, err := fetchTasks(store, func(filter db.TaskFilter) ([]db.Task, error) {
 				return runner(store, filter)
 			}, opts, false, []int{db.TaskTypeTodo})
@@ -46,7 +50,7 @@ func newTaskListCommand(app *App, use string, short string, defaultStatus string
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	addTaskQueryFlags(cmd, &opts, true, true)
-	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
 
 	return cmd
 }
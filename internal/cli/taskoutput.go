@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"text/template"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
 )
@@ -17,10 +18,31 @@ type TaskOutputOptions struct {
 	Format   string
 	Select   []string
 	NoHeader bool
+	// Template is set for --template and --template-file output.
+	Template *template.Template
 }
 
-func resolveTaskOutputOptions(format string, asJSON bool, selectRaw string, noHeader bool) (TaskOutputOptions, error) {
+// needsChecklist reports whether the output reads checklist items, which are
+// otherwise only loaded with --recursive.
+func (o TaskOutputOptions) needsChecklist() bool {
+	return o.Template != nil && templateUsesField(o.Template, "Checklist")
+}
+
+func resolveTaskOutputOptions(format string, asJSON bool, selectRaw string, noHeader bool, templateFlags taskTemplateFlags) (TaskOutputOptions, error) {
+	tmpl, err := parseTaskTemplate(templateFlags)
+	if err != nil {
+		return TaskOutputOptions{}, err
+	}
+	if tmpl != nil {
+		if asJSON || strings.TrimSpace(format) != "" {
+			return TaskOutputOptions{}, fmt.Errorf("Error: --template cannot be used with --format or --json")
+		}
+		return TaskOutputOptions{Format: formatTemplate, NoHeader: noHeader, Template: tmpl}, nil
+	}
 	format = strings.TrimSpace(strings.ToLower(format))
+	if format == "md" {
+		format = formatMarkdown
+	}
 	if format == "" {
 		if asJSON {
 			format = "json"
@@ -31,7 +53,7 @@ func resolveTaskOutputOptions(format string, asJSON bool, selectRaw string, noHe
 		return TaskOutputOptions{}, fmt.Errorf("Error: --json cannot be used with --format %s", format)
 	}
 	switch format {
-	case "table", "json", "jsonl", "csv":
+	case "table", "json", "jsonl", "csv", formatMarkdown, formatOrg, formatTaskPaper:
 	default:
 		return TaskOutputOptions{}, fmt.Errorf("Error: invalid format %q", format)
 	}
@@ -94,6 +116,8 @@ var taskFieldAliases = map[string]string{
 	"today-index":   "today_index",
 	"today_index":   "today_index",
 	"repeat":        "repeating",
+	"rule":          "repeat_rule",
+	"next":          "occurrences",
 }
 
 var taskFieldHeaders = map[string]string{
@@ -112,6 +136,8 @@ var taskFieldHeaders = map[string]string{
 	"start":        "START",
 	"start_date":   "START_DATE",
 	"repeating":    "REPEATING",
+	"repeat_rule":  "RULE",
+	"occurrences":  "NEXT",
 	"deadline":     "DEADLINE",
 	"stop_date":    "STOP_DATE",
 	"created":      "CREATED",
@@ -120,6 +146,8 @@ var taskFieldHeaders = map[string]string{
 	"today_index":  "TODAY_INDEX",
 	"tags":         "TAGS",
 	"type":         "TYPE",
+	"score":        "SCORE",
+	"snippet":      "SNIPPET",
 }
 
 var defaultTaskTableFields = []string{
@@ -164,6 +192,10 @@ func taskFieldValue(task db.Task, field string) any {
 		return task.StartDate
 	case "repeating":
 		return task.Repeating
+	case "repeat_rule":
+		return task.RepeatRule
+	case "occurrences":
+		return task.Occurrences
 	case "deadline":
 		return task.Deadline
 	case "stop_date":
@@ -183,6 +215,10 @@ func taskFieldValue(task db.Task, field string) any {
 		return task.Tags
 	case "type":
 		return task.Type
+	case "score":
+		return task.Score
+	case "snippet":
+		return task.Snippet
 	default:
 		return ""
 	}
@@ -208,6 +244,13 @@ func taskFieldString(task db.Task, field string) string {
 		return strconv.Itoa(*task.TodayIndex)
 	case "tags":
 		return strings.Join(task.Tags, ",")
+	case "occurrences":
+		return occurrencesString(task.Occurrences)
+	case "score":
+		if task.Score == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(task.Score, 'f', 2, 64)
 	default:
 		value := taskFieldValue(task, field)
 		switch v := value.(type) {
@@ -229,6 +272,8 @@ func taskFieldString(task db.Task, field string) string {
 
 func writeTasks(out io.Writer, tasks []db.Task, opts TaskOutputOptions) error {
 	switch opts.Format {
+	case formatTemplate:
+		return writeTemplate(out, tasks, opts.Template)
 	case "json":
 		enc := json.NewEncoder(out)
 		if len(opts.Select) == 0 {
@@ -295,6 +340,8 @@ func writeTasks(out io.Writer, tasks []db.Task, opts TaskOutputOptions) error {
 			fields = defaultTaskTableFields
 		}
 		return writeTaskTable(out, tasks, fields, opts.NoHeader)
+	case formatMarkdown, formatOrg, formatTaskPaper:
+		return writeOutline(out, taskOutline(tasks), opts.Format)
 	default:
 		return fmt.Errorf("Error: invalid format %q", opts.Format)
 	}
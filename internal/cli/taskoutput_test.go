@@ -9,7 +9,7 @@ import (
 )
 
 func TestResolveTaskOutputOptionsDefaults(t *testing.T) {
-	opts, err := resolveTaskOutputOptions("", false, "", false)
+	opts, err := resolveTaskOutputOptions("", false, "", false, taskTemplateFlags{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -17,7 +17,7 @@ func TestResolveTaskOutputOptionsDefaults(t *testing.T) {
 		t.Fatalf("expected table format, got %q", opts.Format)
 	}
 
-	opts, err = resolveTaskOutputOptions("", true, "", false)
+	opts, err = resolveTaskOutputOptions("", true, "", false, taskTemplateFlags{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -27,10 +27,10 @@ func TestResolveTaskOutputOptionsDefaults(t *testing.T) {
 }
 
 func TestResolveTaskOutputOptionsInvalidFormat(t *testing.T) {
-	if _, err := resolveTaskOutputOptions("nope", false, "", false); err == nil {
+	if _, err := resolveTaskOutputOptions("nope", false, "", false, taskTemplateFlags{}); err == nil {
 		t.Fatalf("expected error for invalid format")
 	}
-	if _, err := resolveTaskOutputOptions("csv", true, "", false); err == nil {
+	if _, err := resolveTaskOutputOptions("csv", true, "", false, taskTemplateFlags{}); err == nil {
 		t.Fatalf("expected error for json + non-json format")
 	}
 }
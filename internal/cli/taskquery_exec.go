@@ -1,6 +1,18 @@
 package cli
 
-import "github.com/ossianhempel/things3-cli/internal/db"
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// ErrExplainPrinted is returned after --explain printed the query plan in
+// place of running the command.
+var ErrExplainPrinted = errors.New("explain printed")
 
 func fetchTasks(store *db.Store, runner func(db.TaskFilter) ([]db.Task, error), opts TaskQueryOptions, forcePost bool, types []int) ([]db.Task, error) {
 	filter, sortSpec, err := buildTaskFilter(store, opts)
@@ -11,24 +23,73 @@ func fetchTasks(store *db.Store, runner func(db.TaskFilter) ([]db.Task, error),
 		filter.Types = types
 	}
 
-	queryExpr, err := parseRichQuery(opts.Query)
+	var warn io.Writer
+	if opts.pickErr != nil {
+		warn = opts.pickErr()
+	}
+	queryExpr, err := parseRichQuery(opts.Query, warn)
 	if err != nil {
 		return nil, err
 	}
+	compiled := sqlQuery{Exact: true}
+	if queryExpr != nil {
+		schema, err := store.Schema()
+		if err != nil {
+			return nil, err
+		}
+		compiled = compileTaskQuery(queryExpr, schema, opts.TagRecursive)
+		filter.Where = compiled.Where
+		filter.WhereArgs = compiled.Args
+	}
 
-	postProcess := forcePost || queryExpr != nil
+	// Predicates SQL cannot express are matched in Go, so sorting and limits
+	// wait until after filtering; otherwise they stay in the SQL.
+	goFilter := queryExpr != nil && !compiled.Exact
+	postProcess := forcePost || goFilter
 	if postProcess {
 		filter.Limit = 0
 		filter.Offset = 0
 	}
+	// checklist: predicates matched in Go need the items; drop them again
+	// afterwards unless the caller asked for them.
+	loadChecklist := goFilter && !filter.IncludeChecklist && queryUsesChecklist(queryExpr)
+	if loadChecklist {
+		filter.IncludeChecklist = true
+	}
+
+	if opts.Explain {
+		store.ExplainTaskQueries()
+		if _, err := runner(filter); err != nil {
+			return nil, err
+		}
+		out := io.Writer(os.Stdout)
+		if opts.explainOut != nil {
+			out = opts.explainOut()
+		}
+		writeQueryExplanation(out, store.ExplainedTaskQueries(), queryExpr != nil, compiled, postProcess)
+		return nil, ErrExplainPrinted
+	}
 
 	tasks, err := runner(filter)
 	if err != nil {
 		return nil, err
 	}
 
-	if queryExpr != nil {
-		tasks = filterTasksByQuery(tasks, queryExpr)
+	if goFilter {
+		var ancestors map[string][]string
+		if opts.TagRecursive {
+			tags, err := store.Tags()
+			if err != nil {
+				return nil, err
+			}
+			ancestors = tagAncestors(tags)
+		}
+		tasks = filterTasksByQuery(tasks, queryExpr, ancestors)
+	}
+	if loadChecklist {
+		for i := range tasks {
+			tasks[i].Checklist = nil
+		}
 	}
 
 	if postProcess && len(sortSpec) > 0 {
@@ -42,6 +103,40 @@ func fetchTasks(store *db.Store, runner func(db.TaskFilter) ([]db.Task, error),
 	return tasks, nil
 }
 
+// writeQueryExplanation prints the SQL a listing would run and which steps
+// happen in Go.
+func writeQueryExplanation(out io.Writer, queries []db.TaskQuery, hasQuery bool, compiled sqlQuery, postProcess bool) {
+	for i, query := range queries {
+		if len(queries) > 1 {
+			fmt.Fprintf(out, "-- statement %d of %d\n", i+1, len(queries))
+		}
+		fmt.Fprintln(out, query.SQL)
+		args := make([]string, 0, len(query.Args))
+		for _, arg := range query.Args {
+			if text, ok := arg.(string); ok {
+				args = append(args, fmt.Sprintf("%q", text))
+			} else {
+				args = append(args, fmt.Sprint(arg))
+			}
+		}
+		fmt.Fprintf(out, "-- args: [%s]\n", strings.Join(args, ", "))
+	}
+	switch {
+	case !hasQuery:
+	case compiled.Exact:
+		fmt.Fprintln(out, "-- query: compiled to SQL")
+	case compiled.Where != "":
+		fmt.Fprintln(out, "-- query: partly compiled to SQL; the full query is rechecked in Go")
+	default:
+		fmt.Fprintln(out, "-- query: matched in Go")
+	}
+	if postProcess {
+		fmt.Fprintln(out, "-- sort, offset, and limit: applied in Go")
+	} else {
+		fmt.Fprintln(out, "-- sort, offset, and limit: applied in SQL")
+	}
+}
+
 func applyOffsetLimit(tasks []db.Task, limit int, offset int) []db.Task {
 	if offset < 0 {
 		offset = 0
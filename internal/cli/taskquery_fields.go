@@ -0,0 +1,286 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// Comparison operators in rich queries. ":" keeps its substring meaning for
+// text fields and means equality for typed fields.
+const (
+	queryOpContains = ":"
+	queryOpEqual    = "="
+	queryOpNotEqual = "!="
+	queryOpLess     = "<"
+	queryOpLessEq   = "<="
+	queryOpGreater  = ">"
+	queryOpGreatEq  = ">="
+)
+
+func isOrderingOp(op string) bool {
+	switch op {
+	case queryOpLess, queryOpLessEq, queryOpGreater, queryOpGreatEq:
+		return true
+	default:
+		return false
+	}
+}
+
+// queryDateFields maps date field names (and aliases) to the task value they
+// compare.
+var queryDateFields = map[string]string{
+	"deadline":   "deadline",
+	"due":        "deadline",
+	"start":      "start_date",
+	"start_date": "start_date",
+	"created":    "created",
+	"modified":   "modified",
+	"stop_date":  "stop_date",
+	"stopped":    "stop_date",
+}
+
+func taskDateValue(task db.Task, field string) string {
+	switch field {
+	case "deadline":
+		return task.Deadline
+	case "start_date":
+		return task.StartDate
+	case "created":
+		return task.Created
+	case "modified":
+		return task.Modified
+	case "stop_date":
+		return task.StopDate
+	default:
+		return ""
+	}
+}
+
+// queryDatePredicate compares a task date with a date literal. Value is
+// either YYYY-MM-DD, compared by day, or "YYYY-MM-DD HH:MM:SS". Presence,
+// when set, is "none" or "any" and tests whether the date is set at all.
+type queryDatePredicate struct {
+	Field    string
+	Op       string
+	Value    string
+	Presence string
+}
+
+func (q queryDatePredicate) Match(task db.Task) bool {
+	value := taskDateValue(task, q.Field)
+	switch q.Presence {
+	case "none":
+		return value == ""
+	case "any":
+		return value != ""
+	}
+	if value == "" {
+		return false
+	}
+	cmp := compareDateText(value, q.Value)
+	switch q.Op {
+	case queryOpLess:
+		return cmp < 0
+	case queryOpLessEq:
+		return cmp <= 0
+	case queryOpGreater:
+		return cmp > 0
+	case queryOpGreatEq:
+		return cmp >= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// compareDateText compares a task date with a literal at the literal's
+// precision: a date-only literal ignores the time of day.
+func compareDateText(value string, literal string) int {
+	if len(literal) == len("2006-01-02") {
+		if len(value) > len(literal) {
+			value = value[:len(literal)]
+		}
+	} else if len(value) == len("2006-01-02") {
+		value += " 00:00:00"
+	}
+	return strings.Compare(value, literal)
+}
+
+type queryStatusPredicate struct {
+	Status int
+}
+
+func (q queryStatusPredicate) Match(task db.Task) bool {
+	return task.Status == q.Status
+}
+
+// queryStartPredicate matches the list a todo starts in (Inbox, Anytime, or
+// Someday).
+type queryStartPredicate struct {
+	Start string
+}
+
+func (q queryStartPredicate) Match(task db.Task) bool {
+	return strings.EqualFold(task.Start, q.Start)
+}
+
+// queryChecklistPredicate matches on checklist state: "none" (no items),
+// "any" (has items), "incomplete" (an item is open), or "complete" (has
+// items, all closed).
+type queryChecklistPredicate struct {
+	State string
+}
+
+func (q queryChecklistPredicate) Match(task db.Task) bool {
+	open := 0
+	for _, item := range task.Checklist {
+		if item.Status == db.StatusIncomplete {
+			open++
+		}
+	}
+	switch q.State {
+	case "none":
+		return len(task.Checklist) == 0
+	case "any":
+		return len(task.Checklist) > 0
+	case "incomplete":
+		return open > 0
+	default:
+		return len(task.Checklist) > 0 && open == 0
+	}
+}
+
+// buildFieldPredicate builds the expression for field op value. "!=" is
+// built as NOT of the equality so tasks missing the field match it.
+func buildFieldPredicate(field string, op string, valueToken token, now time.Time) (queryExpr, error) {
+	if op == queryOpNotEqual {
+		inner, err := buildFieldPredicate(field, queryOpEqual, valueToken, now)
+		if err != nil {
+			return nil, err
+		}
+		return queryNot{Inner: inner}, nil
+	}
+
+	name := strings.ToLower(field)
+	value := strings.ToLower(strings.TrimSpace(valueToken.value))
+	typed := name == "status" || name == "checklist" || queryDateFields[name] != ""
+	if typed && valueToken.typ == tokenRegex {
+		return nil, fmt.Errorf("Error: %s does not accept a regex", field)
+	}
+
+	switch {
+	case name == "status":
+		if isOrderingOp(op) {
+			return nil, fmt.Errorf("Error: %s does not support %s", field, op)
+		}
+		status, err := db.ParseStatus(value)
+		if err != nil || status == nil {
+			return nil, fmt.Errorf("Error: invalid status %q (use incomplete, completed, or canceled)", valueToken.value)
+		}
+		return queryStatusPredicate{Status: *status}, nil
+	case name == "checklist":
+		if isOrderingOp(op) {
+			return nil, fmt.Errorf("Error: %s does not support %s", field, op)
+		}
+		switch value {
+		case "none", "any", "incomplete", "complete":
+			return queryChecklistPredicate{State: value}, nil
+		case "completed":
+			return queryChecklistPredicate{State: "complete"}, nil
+		case "true":
+			return queryChecklistPredicate{State: "any"}, nil
+		case "false":
+			return queryChecklistPredicate{State: "none"}, nil
+		}
+		return nil, fmt.Errorf("Error: invalid checklist state %q (use none, any, incomplete, or complete)", valueToken.value)
+	case name == "start" && !isOrderingOp(op) && (value == "inbox" || value == "anytime" || value == "someday"):
+		return queryStartPredicate{Start: value}, nil
+	case queryDateFields[name] != "":
+		dateField := queryDateFields[name]
+		if !isOrderingOp(op) && (value == "none" || value == "any") {
+			return queryDatePredicate{Field: dateField, Presence: value}, nil
+		}
+		literal, err := parseQueryDate(value, now)
+		if err != nil {
+			return nil, fmt.Errorf("Error: invalid date %q for %s (use YYYY-MM-DD, today, yesterday, tomorrow, +3d, or -2w)", valueToken.value, field)
+		}
+		return queryDatePredicate{Field: dateField, Op: op, Value: literal}, nil
+	}
+
+	if isOrderingOp(op) {
+		return nil, fmt.Errorf("Error: %s does not support %s", field, op)
+	}
+	m, err := buildMatcher(valueToken)
+	if err != nil {
+		return nil, err
+	}
+	m.Exact = op == queryOpEqual
+	return queryPredicate{Field: field, Matcher: m}, nil
+}
+
+var queryDateOffsetPattern = regexp.MustCompile(`^([+-])(\d+)([dwmy])$`)
+
+// parseQueryDate resolves a date literal relative to now: today, yesterday,
+// tomorrow, an offset such as +3d, -2w, +1m, or -1y, YYYY-MM-DD, or a local
+// "YYYY-MM-DD HH:MM[:SS]" timestamp.
+func parseQueryDate(input string, now time.Time) (string, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch input {
+	case "today":
+		return today.Format("2006-01-02"), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1).Format("2006-01-02"), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	}
+	if match := queryDateOffsetPattern.FindStringSubmatch(input); match != nil {
+		amount, err := strconv.Atoi(match[2])
+		if err != nil {
+			return "", err
+		}
+		if match[1] == "-" {
+			amount = -amount
+		}
+		switch match[3] {
+		case "d":
+			today = today.AddDate(0, 0, amount)
+		case "w":
+			today = today.AddDate(0, 0, 7*amount)
+		case "m":
+			today = today.AddDate(0, amount, 0)
+		case "y":
+			today = today.AddDate(amount, 0, 0)
+		}
+		return today.Format("2006-01-02"), nil
+	}
+	if parsed, err := time.ParseInLocation("2006-01-02", input, now.Location()); err == nil {
+		return parsed.Format("2006-01-02"), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if parsed, err := time.ParseInLocation(layout, input, now.Location()); err == nil {
+			return parsed.Format("2006-01-02 15:04:05"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q", input)
+}
+
+// queryUsesChecklist reports whether expr has a checklist predicate, which
+// needs checklist items loaded with the tasks.
+func queryUsesChecklist(expr queryExpr) bool {
+	switch q := expr.(type) {
+	case queryAnd:
+		return queryUsesChecklist(q.Left) || queryUsesChecklist(q.Right)
+	case queryOr:
+		return queryUsesChecklist(q.Left) || queryUsesChecklist(q.Right)
+	case queryNot:
+		return queryUsesChecklist(q.Inner)
+	case queryChecklistPredicate:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,9 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
 )
@@ -89,15 +91,21 @@ func (q queryPredicate) Match(task db.Task) bool {
 	}
 }
 
+// matcher tests text against a regex or a lowercase value, as a substring
+// unless Exact is set.
 type matcher struct {
 	Regex *regexp.Regexp
 	Value string
+	Exact bool
 }
 
 func (m matcher) Match(input string) bool {
 	if m.Regex != nil {
 		return m.Regex.MatchString(input)
 	}
+	if m.Exact {
+		return strings.ToLower(input) == m.Value
+	}
 	return strings.Contains(strings.ToLower(input), m.Value)
 }
 
@@ -135,7 +143,20 @@ func notesHasURL(notes string) bool {
 	return strings.Contains(notes, "http://") || strings.Contains(notes, "https://")
 }
 
-func parseRichQuery(input string) (queryExpr, error) {
+// queryResolver returns the query text saved under name, and false when no
+// query has that name.
+type queryResolver func(name string) (string, bool, error)
+
+// parseRichQuery parses a top-level --query, warning on warn about @name
+// words that match no saved query.
+func parseRichQuery(input string, warn io.Writer) (queryExpr, error) {
+	return parseRichQueryWith(input, defaultQueryResolver(), nil, warn)
+}
+
+// parseRichQueryWith parses input, expanding @name references through
+// resolve. expanding holds the saved queries currently being expanded and is
+// used to reject reference cycles.
+func parseRichQueryWith(input string, resolve queryResolver, expanding []string, warn io.Writer) (queryExpr, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return nil, nil
@@ -145,7 +166,7 @@ func parseRichQuery(input string) (queryExpr, error) {
 	if err != nil {
 		return nil, err
 	}
-	parser := queryParser{tokens: tokens}
+	parser := queryParser{tokens: tokens, resolve: resolve, expanding: expanding, warn: warn, now: time.Now()}
 	expr, err := parser.parseExpression()
 	if err != nil {
 		return nil, err
@@ -156,19 +177,33 @@ func parseRichQuery(input string) (queryExpr, error) {
 	return expr, nil
 }
 
-func filterTasksByQuery(tasks []db.Task, expr queryExpr) []db.Task {
+// filterTasksByQuery keeps tasks matching expr. When ancestors is set, a task
+// also matches tag predicates for every ancestor of its tags.
+func filterTasksByQuery(tasks []db.Task, expr queryExpr, ancestors map[string][]string) []db.Task {
 	if expr == nil {
 		return tasks
 	}
 	filtered := make([]db.Task, 0, len(tasks))
 	for _, task := range tasks {
-		if expr.Match(task) {
+		candidate := task
+		if ancestors != nil {
+			candidate.Tags = expandTagAncestors(task.Tags, ancestors)
+		}
+		if expr.Match(candidate) {
 			filtered = append(filtered, task)
 		}
 	}
 	return filtered
 }
 
+func expandTagAncestors(tags []string, ancestors map[string][]string) []string {
+	expanded := append([]string{}, tags...)
+	for _, tag := range tags {
+		expanded = append(expanded, ancestors[strings.ToLower(tag)]...)
+	}
+	return expanded
+}
+
 type tokenType int
 
 const (
@@ -182,6 +217,7 @@ const (
 	tokenLParen
 	tokenRParen
 	tokenColon
+	tokenCompare
 )
 
 type token struct {
@@ -231,8 +267,25 @@ func (l *queryLexer) nextToken() (token, error) {
 		l.pos++
 		return token{typ: tokenColon, value: ":"}, nil
 	case '!':
+		if l.peekNext() == '=' {
+			l.pos += 2
+			return token{typ: tokenCompare, value: "!="}, nil
+		}
 		l.pos++
 		return token{typ: tokenNot, value: "!"}, nil
+	case '<', '>':
+		l.pos++
+		if l.pos < len(l.input) && l.input[l.pos] == '=' {
+			l.pos++
+			return token{typ: tokenCompare, value: string(ch) + "="}, nil
+		}
+		return token{typ: tokenCompare, value: string(ch)}, nil
+	case '=':
+		l.pos++
+		if l.pos < len(l.input) && l.input[l.pos] == '=' {
+			l.pos++
+		}
+		return token{typ: tokenCompare, value: "="}, nil
 	case '"', '\'':
 		return l.scanQuoted(ch)
 	case '/':
@@ -252,7 +305,7 @@ func (l *queryLexer) nextToken() (token, error) {
 	start := l.pos
 	for l.pos < len(l.input) {
 		ch = l.input[l.pos]
-		if isDelimiter(ch) {
+		if isDelimiter(ch) || (ch == '!' && l.peekNext() == '=') {
 			break
 		}
 		l.pos++
@@ -355,7 +408,7 @@ func (l *queryLexer) peekNext() rune {
 
 func isDelimiter(ch rune) bool {
 	switch ch {
-	case ' ', '\t', '\n', '\r', '(', ')', ':':
+	case ' ', '\t', '\n', '\r', '(', ')', ':', '<', '>', '=':
 		return true
 	default:
 		return false
@@ -363,8 +416,12 @@ func isDelimiter(ch rune) bool {
 }
 
 type queryParser struct {
-	tokens []token
-	pos    int
+	tokens    []token
+	pos       int
+	resolve   queryResolver
+	expanding []string
+	warn      io.Writer
+	now       time.Time
 }
 
 func (p *queryParser) parseExpression() (queryExpr, error) {
@@ -436,10 +493,11 @@ func (p *queryParser) parsePrimary() (queryExpr, error) {
 
 func (p *queryParser) parsePredicate() (queryExpr, error) {
 	field := ""
+	op := ""
 	valueToken := p.next()
-	if valueToken.typ == tokenIdent && p.peek().typ == tokenColon {
+	if valueToken.typ == tokenIdent && (p.peek().typ == tokenColon || p.peek().typ == tokenCompare) {
 		field = valueToken.value
-		p.next()
+		op = p.next().value
 		valueToken = p.next()
 	}
 
@@ -448,6 +506,15 @@ func (p *queryParser) parsePredicate() (queryExpr, error) {
 	default:
 		return nil, fmt.Errorf("Error: expected value after %q", field)
 	}
+	if field == "" && valueToken.typ == tokenIdent && len(valueToken.value) > 1 && strings.HasPrefix(valueToken.value, "@") {
+		expr, found, err := p.parseSavedQuery(valueToken.value[1:])
+		if err != nil || found {
+			return expr, err
+		}
+	}
+	if field != "" {
+		return buildFieldPredicate(field, op, valueToken, p.now)
+	}
 
 	matcher, err := buildMatcher(valueToken)
 	if err != nil {
@@ -456,6 +523,46 @@ func (p *queryParser) parsePredicate() (queryExpr, error) {
 	return queryPredicate{Field: field, Matcher: matcher}, nil
 }
 
+// parseSavedQuery expands an unquoted @name into the saved query's
+// expression. In a top-level query an unknown name reports false, with a
+// warning, and the word is matched literally as it is when quoted ("@name");
+// inside a saved query it is an error.
+func (p *queryParser) parseSavedQuery(name string) (queryExpr, bool, error) {
+	for i, active := range p.expanding {
+		if active == name {
+			chain := append(append([]string{}, p.expanding[i:]...), name)
+			return nil, true, fmt.Errorf("Error: saved query cycle: @%s", strings.Join(chain, " -> @"))
+		}
+	}
+	found := false
+	text := ""
+	if p.resolve != nil {
+		var err error
+		text, found, err = p.resolve(name)
+		if err != nil {
+			return nil, true, err
+		}
+	}
+	if !found {
+		if len(p.expanding) > 0 {
+			return nil, true, fmt.Errorf("Error: unknown saved query @%s (in @%s)", name, p.expanding[len(p.expanding)-1])
+		}
+		if p.warn != nil {
+			fmt.Fprintf(p.warn, "Warning: no saved query @%s; matching %q literally.\n", name, "@"+name)
+		}
+		return nil, false, nil
+	}
+	expanding := append(append([]string{}, p.expanding...), name)
+	expr, err := parseRichQueryWith(text, p.resolve, expanding, nil)
+	if err != nil {
+		return nil, true, err
+	}
+	if expr == nil {
+		return nil, true, fmt.Errorf("Error: saved query @%s is empty", name)
+	}
+	return expr, true, nil
+}
+
 func buildMatcher(tok token) (matcher, error) {
 	switch tok.typ {
 	case tokenRegex:
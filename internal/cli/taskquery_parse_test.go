@@ -1,13 +1,16 @@
 package cli
 
 import (
+	"bytes"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
 )
 
 func TestParseRichQueryMatch(t *testing.T) {
-	expr, err := parseRichQuery("title:alpha AND tag:work")
+	expr, err := parseRichQuery("title:alpha AND tag:work", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -16,7 +19,7 @@ func TestParseRichQueryMatch(t *testing.T) {
 		{Title: "alpha task", Tags: []string{"home"}},
 		{Title: "beta task", Tags: []string{"work"}},
 	}
-	filtered := filterTasksByQuery(tasks, expr)
+	filtered := filterTasksByQuery(tasks, expr, nil)
 	if len(filtered) != 1 {
 		t.Fatalf("expected 1 match, got %d", len(filtered))
 	}
@@ -26,7 +29,7 @@ func TestParseRichQueryMatch(t *testing.T) {
 }
 
 func TestParseRichQueryRegex(t *testing.T) {
-	expr, err := parseRichQuery("title:/^alph/i")
+	expr, err := parseRichQuery("title:/^alph/i", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -34,14 +37,14 @@ func TestParseRichQueryRegex(t *testing.T) {
 		{Title: "Alpha"},
 		{Title: "beta"},
 	}
-	filtered := filterTasksByQuery(tasks, expr)
+	filtered := filterTasksByQuery(tasks, expr, nil)
 	if len(filtered) != 1 || filtered[0].Title != "Alpha" {
 		t.Fatalf("unexpected matches: %+v", filtered)
 	}
 }
 
 func TestParseRichQueryURLPredicate(t *testing.T) {
-	expr, err := parseRichQuery("url:true")
+	expr, err := parseRichQuery("url:true", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -49,14 +52,14 @@ func TestParseRichQueryURLPredicate(t *testing.T) {
 		{Notes: "see https://example.com"},
 		{Notes: "no links here"},
 	}
-	filtered := filterTasksByQuery(tasks, expr)
+	filtered := filterTasksByQuery(tasks, expr, nil)
 	if len(filtered) != 1 {
 		t.Fatalf("expected 1 match, got %d", len(filtered))
 	}
 }
 
 func TestParseRichQueryRepeatingPredicate(t *testing.T) {
-	expr, err := parseRichQuery("repeating:true")
+	expr, err := parseRichQuery("repeating:true", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -64,8 +67,125 @@ func TestParseRichQueryRepeatingPredicate(t *testing.T) {
 		{Title: "repeat", Repeating: true},
 		{Title: "once", Repeating: false},
 	}
-	filtered := filterTasksByQuery(tasks, expr)
+	filtered := filterTasksByQuery(tasks, expr, nil)
 	if len(filtered) != 1 || filtered[0].Title != "repeat" {
 		t.Fatalf("unexpected matches: %+v", filtered)
 	}
 }
+
+func TestParseRichQuerySavedReferences(t *testing.T) {
+	resolve := savedQueryResolver(map[string]SavedQuery{
+		"work":    {Name: "work", Query: "tag:work"},
+		"alpha":   {Name: "alpha", Query: "@work AND title:alpha"},
+		"loop":    {Name: "loop", Query: "@cycle OR tag:x"},
+		"cycle":   {Name: "cycle", Query: "@loop"},
+		"missing": {Name: "missing", Query: "@nope"},
+	})
+	tasks := []db.Task{
+		{Title: "alpha task", Tags: []string{"work"}},
+		{Title: "beta task", Tags: []string{"work"}},
+		{Title: "@alpha notes", Tags: []string{"home"}},
+		{Title: "ask @nope", Tags: []string{"home"}},
+	}
+
+	expr, err := parseRichQueryWith("@alpha OR tag:home", resolve, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filtered := filterTasksByQuery(tasks, expr, nil); len(filtered) != 3 || filtered[1].Title != "@alpha notes" {
+		t.Fatalf("unexpected matches: %+v", filtered)
+	}
+
+	expr, err = parseRichQueryWith(`"@alpha"`, resolve, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filtered := filterTasksByQuery(tasks, expr, nil); len(filtered) != 1 || filtered[0].Title != "@alpha notes" {
+		t.Fatalf("expected quoted reference to match literally, got %+v", filtered)
+	}
+
+	if _, err := parseRichQueryWith("@loop", resolve, nil, nil); err == nil || !strings.Contains(err.Error(), "@loop -> @cycle -> @loop") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if _, err := parseRichQueryWith("@missing", resolve, nil, nil); err == nil || !strings.Contains(err.Error(), "unknown saved query @nope") {
+		t.Fatalf("expected unknown reference error, got %v", err)
+	}
+
+	warn := &bytes.Buffer{}
+	expr, err = parseRichQueryWith("@nope", resolve, nil, warn)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filtered := filterTasksByQuery(tasks, expr, nil); len(filtered) != 1 || filtered[0].Title != "ask @nope" {
+		t.Fatalf("expected unknown top-level reference to match literally, got %+v", filtered)
+	}
+	if !strings.Contains(warn.String(), "no saved query @nope") {
+		t.Fatalf("expected a warning, got %q", warn.String())
+	}
+}
+
+func TestParseRichQueryTypedFields(t *testing.T) {
+	now := time.Now()
+	day := func(offset int) string { return now.AddDate(0, 0, offset).Format("2006-01-02") }
+	tasks := []db.Task{
+		{UUID: "A", Title: "Report", Status: db.StatusIncomplete, Start: "Anytime", Deadline: day(2), Created: day(-1) + " 09:30:00"},
+		{UUID: "B", Title: "Report draft", Status: db.StatusCompleted, Start: "Someday", Created: day(-30) + " 10:00:00",
+			Checklist: []db.ChecklistItem{{Status: db.StatusCompleted}}},
+		{UUID: "C", Title: "Call", Status: db.StatusIncomplete, Start: "Inbox", Deadline: "2099-11-15", Created: day(0) + " 08:00:00",
+			Checklist: []db.ChecklistItem{{Status: db.StatusCompleted}, {Status: db.StatusIncomplete}}},
+	}
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"deadline<+3d", "A"},
+		{"deadline>=2099-11-15", "C"},
+		{"created>=-7d", "AC"},
+		{"created<yesterday", "B"},
+		{"created='" + day(-1) + " 09:30'", "A"},
+		{"deadline:none", "B"},
+		{"deadline!=none AND NOT start:inbox", "A"},
+		{"status:completed OR start:someday", "B"},
+		{"status!=completed", "AC"},
+		{"checklist:incomplete", "C"},
+		{"checklist:complete", "B"},
+		{"checklist:none", "A"},
+		{"title=report", "A"},
+		{"title:report", "AB"},
+		{"(deadline<2099-11-16 AND title!=call) OR due=2099-11-15", "AC"},
+	}
+	for _, tc := range cases {
+		expr, err := parseRichQueryWith(tc.query, nil, nil, nil)
+		if tc.want == "" {
+			if err == nil {
+				t.Fatalf("%s: expected error", tc.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.query, err)
+		}
+		got := ""
+		for _, task := range filterTasksByQuery(tasks, expr, nil) {
+			got += task.UUID
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.query, tc.want, got)
+		}
+	}
+}
+
+func TestParseRichQueryTypedFieldErrors(t *testing.T) {
+	for query, message := range map[string]string{
+		"title<b":           "title does not support <",
+		"deadline:soon":     `invalid date "soon" for deadline`,
+		"status>=completed": "status does not support >=",
+		"status:later":      `invalid status "later"`,
+		"checklist:/x/":     "checklist does not accept a regex",
+		"checklist:half":    `invalid checklist state "half"`,
+	} {
+		if _, err := parseRichQueryWith(query, nil, nil, nil); err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("%s: expected %q, got %v", query, message, err)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// sqlQuery is a rich query compiled to a WHERE fragment for db.TaskFilter.
+// When Exact is false the fragment only narrows the rows (or is empty) and
+// the expression must still be matched in Go.
+type sqlQuery struct {
+	Where string
+	Args  []any
+	Exact bool
+}
+
+// queryCompiler turns a queryExpr into SQL over the aliases queryTasks uses
+// (t task, p project, a area, h heading). Predicates it cannot express
+// exactly — regexes, tag matches with --tag-recursive, and non-ASCII text,
+// which SQLite's lower() does not fold — are left to Go.
+type queryCompiler struct {
+	repeatColumn string
+	tagRecursive bool
+}
+
+func compileTaskQuery(expr queryExpr, schema *db.Schema, tagRecursive bool) sqlQuery {
+	if expr == nil {
+		return sqlQuery{Exact: true}
+	}
+	c := queryCompiler{
+		repeatColumn: "t." + schema.RepeatColumn("recurrenceRule"),
+		tagRecursive: tagRecursive,
+	}
+	part, ok := c.compile(expr)
+	if !ok {
+		return sqlQuery{}
+	}
+	return sqlQuery{Where: part.where, Args: part.args, Exact: part.exact}
+}
+
+// sqlPart is a compiled subexpression. An inexact part is implied by the
+// expression (every matching task satisfies it) but may admit more tasks.
+type sqlPart struct {
+	where string
+	args  []any
+	exact bool
+}
+
+func exactPart(where string, args ...any) (sqlPart, bool) {
+	return sqlPart{where: where, args: args, exact: true}, true
+}
+
+func (c queryCompiler) compile(expr queryExpr) (sqlPart, bool) {
+	switch q := expr.(type) {
+	case queryAnd:
+		left, leftOK := c.compile(q.Left)
+		right, rightOK := c.compile(q.Right)
+		switch {
+		case leftOK && rightOK:
+			return joinParts(left, right, "AND"), true
+		case leftOK:
+			left.exact = false
+			return left, true
+		case rightOK:
+			right.exact = false
+			return right, true
+		}
+		return sqlPart{}, false
+	case queryOr:
+		left, leftOK := c.compile(q.Left)
+		right, rightOK := c.compile(q.Right)
+		if !leftOK || !rightOK {
+			return sqlPart{}, false
+		}
+		return joinParts(left, right, "OR"), true
+	case queryNot:
+		inner, ok := c.compile(q.Inner)
+		if !ok || !inner.exact {
+			return sqlPart{}, false
+		}
+		return exactPart("NOT ("+inner.where+")", inner.args...)
+	case queryPredicate:
+		return c.compileText(q)
+	case queryDatePredicate:
+		return compileDate(q)
+	case queryStatusPredicate:
+		return exactPart("t.status = ?", q.Status)
+	case queryStartPredicate:
+		start := map[string]int{"inbox": 0, "anytime": 1, "someday": 2}
+		return exactPart("t.start = ?", start[strings.ToLower(q.Start)])
+	case queryChecklistPredicate:
+		const hasItems = "EXISTS (SELECT 1 FROM TMChecklistItem qc WHERE qc.task = t.uuid)"
+		const hasOpen = "EXISTS (SELECT 1 FROM TMChecklistItem qc WHERE qc.task = t.uuid AND qc.status = ?)"
+		switch q.State {
+		case "none":
+			return exactPart("NOT " + hasItems)
+		case "any":
+			return exactPart(hasItems)
+		case "incomplete":
+			return exactPart(hasOpen, db.StatusIncomplete)
+		default:
+			return exactPart(hasItems+" AND NOT "+hasOpen, db.StatusIncomplete)
+		}
+	}
+	return sqlPart{}, false
+}
+
+func joinParts(left sqlPart, right sqlPart, op string) sqlPart {
+	args := append(append([]any{}, left.args...), right.args...)
+	return sqlPart{
+		where: "(" + left.where + ") " + op + " (" + right.where + ")",
+		args:  args,
+		exact: left.exact && right.exact,
+	}
+}
+
+var queryTextColumns = map[string]string{
+	"title":   "t.title",
+	"notes":   "t.notes",
+	"project": "p.title",
+	"area":    "a.title",
+	"heading": "h.title",
+	"id":      "t.uuid",
+	"uuid":    "t.uuid",
+}
+
+func (c queryCompiler) compileText(q queryPredicate) (sqlPart, bool) {
+	m := q.Matcher
+	if m.Regex != nil || !isASCII(m.Value) {
+		return sqlPart{}, false
+	}
+	field := strings.ToLower(q.Field)
+	switch field {
+	case "":
+		if c.tagRecursive {
+			return sqlPart{}, false
+		}
+		parts := []string{}
+		args := []any{}
+		for _, column := range []string{"t.title", "t.notes"} {
+			where, arg := textCondition(column, m)
+			parts = append(parts, where)
+			args = append(args, arg)
+		}
+		where, arg := tagCondition(m)
+		parts = append(parts, where)
+		args = append(args, arg)
+		for _, column := range []string{"p.title", "a.title", "h.title"} {
+			where, arg := textCondition(column, m)
+			parts = append(parts, where)
+			args = append(args, arg)
+		}
+		return exactPart(strings.Join(parts, " OR "), args...)
+	case "tag", "tags":
+		if c.tagRecursive {
+			return sqlPart{}, false
+		}
+		where, arg := tagCondition(m)
+		return exactPart(where, arg)
+	case "url":
+		hasURL := "(lower(IFNULL(t.notes, '')) LIKE '%http://%' OR lower(IFNULL(t.notes, '')) LIKE '%https://%')"
+		switch strings.TrimSpace(m.Value) {
+		case "true":
+			return exactPart(hasURL)
+		case "false":
+			return exactPart("NOT " + hasURL)
+		}
+		where, arg := textCondition("t.notes", matcher{Value: m.Value})
+		return exactPart(where, arg)
+	case "repeating":
+		switch strings.TrimSpace(m.Value) {
+		case "true":
+			return exactPart(c.repeatColumn + " IS NOT NULL")
+		case "false":
+			return exactPart(c.repeatColumn + " IS NULL")
+		}
+		return sqlPart{}, false
+	}
+	column, ok := queryTextColumns[field]
+	if !ok {
+		// Unknown fields never match, as in queryPredicate.Match.
+		return exactPart("0")
+	}
+	where, arg := textCondition(column, m)
+	return exactPart(where, arg)
+}
+
+// textCondition mirrors matcher.Match on a possibly NULL column.
+func textCondition(column string, m matcher) (string, any) {
+	if m.Exact {
+		return "lower(IFNULL(" + column + ", '')) = ?", m.Value
+	}
+	return "lower(IFNULL(" + column + ", '')) LIKE ? ESCAPE '\\'", "%" + escapeLikePattern(m.Value) + "%"
+}
+
+func tagCondition(m matcher) (string, any) {
+	where, arg := textCondition("qtag.title", m)
+	return "EXISTS (SELECT 1 FROM TMTaskTag qtt JOIN TMTag qtag ON qtag.uuid = qtt.tags WHERE qtt.tasks = t.uuid AND " + where + ")", arg
+}
+
+// queryDateColumns maps date fields to their column and whether the column
+// holds a packed Things date (true) or a Unix timestamp (false).
+var queryDateColumns = map[string]struct {
+	column    string
+	thingsDay bool
+}{
+	"deadline":   {"t.deadline", true},
+	"start_date": {"t.startDate", true},
+	"created":    {"t.creationDate", false},
+	"modified":   {"t.userModificationDate", false},
+	"stop_date":  {"t.stopDate", false},
+}
+
+// compileDate mirrors queryDatePredicate.Match: dates format as empty when
+// the column is NULL or not positive, and empty dates never compare.
+func compileDate(q queryDatePredicate) (sqlPart, bool) {
+	info, ok := queryDateColumns[q.Field]
+	if !ok {
+		return sqlPart{}, false
+	}
+	set := "IFNULL(" + info.column + ", 0) > 0"
+	switch q.Presence {
+	case "none":
+		return exactPart("NOT " + set)
+	case "any":
+		return exactPart(set)
+	}
+
+	dateOnly := len(q.Value) == len("2006-01-02")
+	if info.thingsDay {
+		if !dateOnly {
+			return sqlPart{}, false
+		}
+		day, err := time.ParseInLocation("2006-01-02", q.Value, time.Local)
+		if err != nil {
+			return sqlPart{}, false
+		}
+		op := q.Op
+		if op == "" || op == queryOpContains {
+			op = queryOpEqual
+		}
+		return exactPart(set+" AND "+info.column+" "+op+" ?", thingsDateValue(day))
+	}
+
+	// Timestamps compare at the literal's precision, so the literal stands
+	// for the range [start, end) of its day or second.
+	var start, end time.Time
+	if dateOnly {
+		day, err := time.ParseInLocation("2006-01-02", q.Value, time.Local)
+		if err != nil {
+			return sqlPart{}, false
+		}
+		start, end = day, day.AddDate(0, 0, 1)
+	} else {
+		second, err := time.ParseInLocation("2006-01-02 15:04:05", q.Value, time.Local)
+		if err != nil {
+			return sqlPart{}, false
+		}
+		start, end = second, second.Add(time.Second)
+	}
+	lo, hi := float64(start.Unix()), float64(end.Unix())
+	column := info.column
+	switch q.Op {
+	case queryOpLess:
+		return exactPart(set+" AND "+column+" < ?", lo)
+	case queryOpLessEq:
+		return exactPart(set+" AND "+column+" < ?", hi)
+	case queryOpGreater:
+		return exactPart(set+" AND "+column+" >= ?", hi)
+	case queryOpGreatEq:
+		return exactPart(set+" AND "+column+" >= ?", lo)
+	default:
+		return exactPart(set+" AND "+column+" >= ? AND "+column+" < ?", lo, hi)
+	}
+}
+
+func isASCII(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if value[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeLikePattern escapes LIKE metacharacters so they match literally.
+func escapeLikePattern(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `%`, `\%`)
+	value = strings.ReplaceAll(value, `_`, `\_`)
+	return value
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+func TestCompiledQueriesMatchGoFiltering(t *testing.T) {
+	store, err := db.Open(writeTestDB(t))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer store.Close()
+
+	queries := []string{
+		"task",
+		"title:task AND NOT tag:urgent",
+		"title=\"task one\" OR area:home",
+		"notes:some OR heading:head",
+		"project:\"project one\" AND tag=urgent",
+		"id:inbox1 OR uuid=t1",
+		"url:true OR url:false",
+		"repeating:false",
+		"nosuchfield:x OR title:deadline",
+		"deadline<=+1d",
+		"deadline:none AND start:anytime",
+		"start>=today OR start:someday",
+		"created>=-1d AND created<=today",
+		"created<yesterday",
+		"stopped=today OR status:canceled",
+		"status!=incomplete",
+		"checklist:incomplete OR checklist:complete",
+		"NOT checklist:none",
+		"title:100%",
+	}
+	for _, query := range queries {
+		expr, err := parseRichQuery(query, nil)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", query, err)
+		}
+		all, err := store.Tasks(db.TaskFilter{IncludeChecklist: true})
+		if err != nil {
+			t.Fatalf("%s: load: %v", query, err)
+		}
+		want := []string{}
+		for _, task := range filterTasksByQuery(all, expr, nil) {
+			want = append(want, task.UUID)
+		}
+
+		schema, err := store.Schema()
+		if err != nil {
+			t.Fatalf("schema: %v", err)
+		}
+		compiled := compileTaskQuery(expr, schema, false)
+		if !compiled.Exact {
+			t.Fatalf("%s: expected an exact SQL translation", query)
+		}
+		tasks, err := store.Tasks(db.TaskFilter{Where: compiled.Where, WhereArgs: compiled.Args})
+		if err != nil {
+			t.Fatalf("%s: run %s: %v", query, compiled.Where, err)
+		}
+		got := []string{}
+		for _, task := range tasks {
+			got = append(got, task.UUID)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: SQL matched %v, Go matched %v", query, got, want)
+		}
+	}
+}
+
+func TestCompileTaskQueryFallsBackForRegex(t *testing.T) {
+	schema := &db.Schema{}
+	cases := []struct {
+		query    string
+		tagRec   bool
+		where    bool
+		exact    bool
+		contains string
+	}{
+		{query: "title:/^a/ AND status:completed", where: true, contains: "t.status = ?"},
+		{query: "title:/^a/ OR status:completed"},
+		{query: "NOT title:/^a/"},
+		{query: "tag:work", tagRec: true},
+		{query: "title:café"},
+		{query: "tag:work AND deadline<2099-01-01", where: true, exact: true, contains: "TMTaskTag"},
+	}
+	for _, tc := range cases {
+		expr, err := parseRichQuery(tc.query, nil)
+		if err != nil {
+			t.Fatalf("%s: parse: %v", tc.query, err)
+		}
+		compiled := compileTaskQuery(expr, schema, tc.tagRec)
+		if (compiled.Where != "") != tc.where || compiled.Exact != tc.exact || !strings.Contains(compiled.Where, tc.contains) {
+			t.Fatalf("%s: unexpected compilation %+v", tc.query, compiled)
+		}
+	}
+}
+
+func TestTasksExplainPrintsSQL(t *testing.T) {
+	dbPath := writeTestDB(t)
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"tasks", "--db", dbPath, "--query", "title:task AND deadline<+7d", "--sort", "-created", "--limit", "5", "--explain"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); !errors.Is(err, ErrExplainPrinted) {
+		t.Fatalf("expected ErrExplainPrinted, got %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"lower(IFNULL(t.title, '')) LIKE ?",
+		"t.deadline < ?",
+		"ORDER BY t.creationDate DESC LIMIT ?",
+		`"%task%"`,
+		"-- query: compiled to SQL",
+		"-- sort, offset, and limit: applied in SQL",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in explain output:\n%s", want, text)
+		}
+	}
+}
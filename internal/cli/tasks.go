@@ -16,6 +16,7 @@ func NewTasksCommand(app *App) *cobra.Command {
 	var selectRaw string
 	var asJSON bool
 	var noHeader bool
+	var templateFlags taskTemplateFlags
 
 	cmd := &cobra.Command{
 		Use:     "tasks",
@@ -29,10 +30,13 @@ func NewTasksCommand(app *App) *cobra.Command {
 			defer store.Close()
 
 			opts.HasURLSet = cmd.Flags().Changed("has-url")
-			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader)
+			outputOpts, err := resolveTaskOutputOptions(format, asJSON, selectRaw, noHeader, templateFlags)
 			if err != nil {
 				return err
 			}
+			if outputOpts.needsChecklist() {
+				opts.IncludeChecklist = true
+			}
 			tasks, err := fetchTasks(store, store.Tasks, opts, false, []int{db.TaskTypeTodo})
 			if err != nil {
 				return formatDBError(err)
@@ -44,7 +48,7 @@ func NewTasksCommand(app *App) *cobra.Command {
 	cmd.Flags().StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
 	cmd.Flags().StringVar(&dbPath, "database", "", "Alias for --db")
 	addTaskQueryFlags(cmd, &opts, true, true)
-	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader)
+	addTaskOutputFlags(cmd, &format, &selectRaw, &asJSON, &noHeader, &templateFlags)
 
 	return cmd
 }
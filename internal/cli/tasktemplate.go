@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// formatTemplate is the output format of --template and --template-file.
+const formatTemplate = "template"
+
+// taskTemplateFlags holds --template and --template-file.
+type taskTemplateFlags struct {
+	Text string
+	File string
+}
+
+// parseTaskTemplate returns nil when neither flag is set.
+func parseTaskTemplate(flags taskTemplateFlags) (*template.Template, error) {
+	text := flags.Text
+	name := "template"
+	switch {
+	case flags.Text != "" && flags.File != "":
+		return nil, fmt.Errorf("Error: --template and --template-file are mutually exclusive")
+	case flags.File != "":
+		data, err := os.ReadFile(flags.File)
+		if err != nil {
+			return nil, fmt.Errorf("Error: read template file: %v", err)
+		}
+		text = string(data)
+		name = flags.File
+	case flags.Text == "":
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Funcs(taskTemplateFuncs(time.Now)).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Error: invalid template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// templateUsesField reports whether tmpl reads the top-level field name (as
+// .Name or $.Name) anywhere, including in nested and defined templates.
+func templateUsesField(tmpl *template.Template, name string) bool {
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil && nodeUsesField(t.Tree.Root, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func nodeUsesField(node parse.Node, name string) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if nodeUsesField(child, name) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return nodeUsesField(n.Pipe, name)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if nodeUsesField(cmd, name) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if nodeUsesField(arg, name) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return nodeUsesField(n.Node, name)
+	case *parse.FieldNode:
+		return len(n.Ident) > 0 && n.Ident[0] == name
+	case *parse.VariableNode:
+		return len(n.Ident) > 1 && n.Ident[0] == "$" && n.Ident[1] == name
+	case *parse.IfNode:
+		return branchUsesField(&n.BranchNode, name)
+	case *parse.RangeNode:
+		return branchUsesField(&n.BranchNode, name)
+	case *parse.WithNode:
+		return branchUsesField(&n.BranchNode, name)
+	case *parse.TemplateNode:
+		return nodeUsesField(n.Pipe, name)
+	}
+	return false
+}
+
+func branchUsesField(n *parse.BranchNode, name string) bool {
+	return nodeUsesField(n.Pipe, name) || nodeUsesField(n.List, name) || nodeUsesField(n.ElseList, name)
+}
+
+// writeTemplate executes tmpl once per task, ending each result with a
+// newline unless the template already does.
+func writeTemplate(out io.Writer, tasks []db.Task, tmpl *template.Template) error {
+	var buf bytes.Buffer
+	for _, task := range tasks {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, task); err != nil {
+			return fmt.Errorf("Error: template: %v", err)
+		}
+		if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+		if _, err := out.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// taskTemplateFuncs are the helpers available to output templates. Argument
+// order puts the value last so each works at the end of a pipeline, as in
+// {{.Deadline | date "Mon Jan 2"}}.
+func taskTemplateFuncs(now func() time.Time) template.FuncMap {
+	return template.FuncMap{
+		"default": templateDefault,
+		"date":    templateDate,
+		"relative": func(value string) string {
+			return relativeDate(value, now())
+		},
+		"join":     templateJoin,
+		"truncate": templateTruncate,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"status":   db.StatusLabel,
+	}
+}
+
+// templateDefault returns fallback when value is empty or zero.
+func templateDefault(fallback any, value any) any {
+	if value == nil {
+		return fallback
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		if v.Len() == 0 {
+			return fallback
+		}
+	default:
+		if v.IsZero() {
+			return fallback
+		}
+	}
+	return value
+}
+
+// templateDate reformats a date or timestamp with a Go time layout. Values
+// that are not dates pass through unchanged.
+func templateDate(layout string, value string) string {
+	parsed, ok := parseTemplateTime(value)
+	if !ok {
+		return value
+	}
+	return parsed.Format(layout)
+}
+
+func parseTemplateTime(value string) (time.Time, bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+		if parsed, err := time.ParseInLocation(layout, strings.TrimSpace(value), time.Local); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// relativeDate describes value in days from now: "today", "tomorrow",
+// "in 3 days", "2 days ago".
+func relativeDate(value string, now time.Time) string {
+	parsed, ok := parseTemplateTime(value)
+	if !ok {
+		return value
+	}
+	day := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.Local)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	// Round to absorb daylight saving shifts.
+	days := int(day.Sub(today).Round(24*time.Hour) / (24 * time.Hour))
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 1:
+		return fmt.Sprintf("in %d days", days)
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
+
+func templateJoin(sep string, items []string) string {
+	return strings.Join(items, sep)
+}
+
+// templateTruncate shortens value to at most n runes, ending in "…" when
+// anything was cut.
+func templateTruncate(n int, value string) string {
+	runes := []rune(value)
+	if n < 0 || len(runes) <= n {
+		return value
+	}
+	if n == 0 {
+		return ""
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTaskTemplateHelpers(t *testing.T) {
+	now := time.Date(2026, 1, 5, 15, 0, 0, 0, time.Local)
+	cases := map[string]string{
+		"2026-01-05":          "today",
+		"2026-01-06":          "tomorrow",
+		"2026-01-04 09:00:00": "yesterday",
+		"2026-01-12":          "in 7 days",
+		"2025-12-31":          "5 days ago",
+		"someday":             "someday",
+	}
+	for value, want := range cases {
+		if got := relativeDate(value, now); got != want {
+			t.Fatalf("relativeDate(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	if got := templateDate("Mon Jan 2", "2026-01-05"); got != "Mon Jan 5" {
+		t.Fatalf("unexpected date: %q", got)
+	}
+	if got := templateTruncate(5, "Quarterly review"); got != "Quar…" {
+		t.Fatalf("unexpected truncate: %q", got)
+	}
+	if got := templateDefault("none", ""); got != "none" {
+		t.Fatalf("unexpected default: %v", got)
+	}
+	if got := templateDefault("none", []string{"a"}); len(got.([]string)) != 1 {
+		t.Fatalf("unexpected default for a non-empty list: %v", got)
+	}
+}
+
+func TestTasksTemplateOutput(t *testing.T) {
+	dbPath := writeTestDB(t)
+	useTempActionLog(t)
+
+	template := `{{.Title}} ({{.Deadline | default "none"}}) {{join "," .Tags}}{{range .Checklist}} [{{.Title}}]{{end}}`
+	// .Checklist in the template loads checklist items without --recursive.
+	output, err := runWithArgs(t, "tasks", "--db", dbPath, "--project", "P1", "--template", template)
+	if err != nil {
+		t.Fatalf("tasks --template: %v", err)
+	}
+	if output != "Task One (none) urgent [Check Item]\nHeading Task (none) \n" {
+		t.Fatalf("unexpected template output: %q", output)
+	}
+
+	path := filepath.Join(t.TempDir(), "standup.tmpl")
+	if err := os.WriteFile(path, []byte("- {{.Title | upper}}: {{status .Status}}\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	output, err = runWithArgs(t, "tasks", "--db", dbPath, "--project", "P1", "--template-file", path)
+	if err != nil {
+		t.Fatalf("tasks --template-file: %v", err)
+	}
+	if output != "- TASK ONE: incomplete\n- HEADING TASK: incomplete\n" {
+		t.Fatalf("unexpected template file output: %q", output)
+	}
+
+	if _, err := runWithArgs(t, "tasks", "--db", dbPath, "--template", "{{.Title}}", "--json"); err == nil {
+		t.Fatalf("expected --template with --json to fail")
+	}
+	if _, err := runWithArgs(t, "tasks", "--db", dbPath, "--template", "{{.Title"); err == nil || !strings.Contains(err.Error(), "invalid template") {
+		t.Fatalf("expected a parse error, got %v", err)
+	}
+}
+
+func TestTemplateUsesField(t *testing.T) {
+	cases := map[string]bool{
+		`{{.Title}}`:                                        false,
+		`{{range .Checklist}}{{.Title}}{{end}}`:             true,
+		`{{if .Tags}}{{len .Checklist}}{{end}}`:             true,
+		`{{with .Notes}}{{$.Checklist}}{{end}}`:             true,
+		`{{define "items"}}{{.Checklist}}{{end}}{{.Title}}`: true,
+		`{{.Title | default "Checklist"}}`:                  false,
+	}
+	for text, want := range cases {
+		tmpl, err := parseTaskTemplate(taskTemplateFlags{Text: text})
+		if err != nil {
+			t.Fatalf("parse %q: %v", text, err)
+		}
+		if got := templateUsesField(tmpl, "Checklist"); got != want {
+			t.Fatalf("templateUsesField(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
+
+func TestAllTemplateOutput(t *testing.T) {
+	dbPath := writeTestDB(t)
+
+	output, err := runWithArgs(t, "all", "--db", dbPath, "--template", "{{.Title}}{{range .Checklist}} [{{.Title}}]{{end}}")
+	if err != nil {
+		t.Fatalf("all --template: %v", err)
+	}
+	if !strings.HasPrefix(output, "Inbox\nInbox Task\n") || !strings.Contains(output, "Task One [Check Item]\n") {
+		t.Fatalf("unexpected all template output: %q", output)
+	}
+	if strings.Contains(output, "No Area") || strings.Contains(output, "Areas") {
+		t.Fatalf("expected project and area sections to be left out: %q", output)
+	}
+	if _, err := runWithArgs(t, "all", "--db", dbPath, "--template", "{{.Title}}", "--format", "markdown"); err == nil {
+		t.Fatalf("expected --template with --format to fail")
+	}
+}
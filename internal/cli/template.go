@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/templates"
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewTemplateCommand builds the template command group.
+func NewTemplateCommand(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template <apply|export>",
+		Short: "Apply or export project templates",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printHelp(app.Out, formatHelpText(templateHelp, isTTY(app.Out)))
+			return ErrHelpPrinted
+		},
+	}
+	cmd.AddCommand(newTemplateApplyCommand(app))
+	cmd.AddCommand(newTemplateExportCommand(app))
+	return cmd
+}
+
+func newTemplateApplyCommand(app *App) *cobra.Command {
+	var varsRaw []string
+	var start string
+	var area string
+	var reveal bool
+	var maxURLLength int
+
+	cmd := &cobra.Command{
+		Use:   "apply [OPTIONS...] [-|FILE]",
+		Short: "Create a project from a template",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := readJSONPayload(app.In, args)
+			if err != nil {
+				return err
+			}
+			tmpl, err := templates.Parse(data)
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			vars, err := templates.ParseVars(varsRaw)
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			base := time.Now()
+			if strings.TrimSpace(start) != "" {
+				parsed, _, err := parseDateOrTime(start)
+				if err != nil {
+					return err
+				}
+				base = parsed
+			}
+			expanded, err := tmpl.Expand(vars, base)
+			if err != nil {
+				return fmt.Errorf("Error: %v", err)
+			}
+			if strings.TrimSpace(area) != "" {
+				expanded.Area = area
+				expanded.AreaID = ""
+			}
+
+			urls, err := things.BuildJSONURLs(things.JSONOptions{
+				Reveal:       reveal,
+				MaxURLLength: maxURLLength,
+			}, expanded.JSONItems())
+			if err != nil {
+				return err
+			}
+			return openURLs(app, urls)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringArrayVar(&varsRaw, "var", nil, "Template variable as name=value (repeatable)")
+	flags.StringVar(&start, "start", "", "Base date for relative when/deadline offsets (default: today)")
+	flags.StringVar(&area, "area", "", "Override the template area")
+	flags.BoolVar(&reveal, "reveal", false, "Reveal the created project")
+	flags.IntVar(&maxURLLength, "max-url-length", things.DefaultJSONMaxURLLength, "Maximum length of a single Things URL")
+
+	return cmd
+}
+
+func newTemplateExportCommand(app *App) *cobra.Command {
+	var dbPath string
+	var id string
+	var base string
+	var format string
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export [OPTIONS...]",
+		Short: "Capture a template from an existing project",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(id) == "" {
+				return fmt.Errorf("Error: Must specify --id=PROJECT")
+			}
+			format = strings.ToLower(strings.TrimSpace(format))
+			if format != "yaml" && format != "json" {
+				return fmt.Errorf("Error: invalid format %q (use yaml or json)", format)
+			}
+			baseDate := time.Now()
+			if strings.TrimSpace(base) != "" {
+				parsed, _, err := parseDateOrTime(base)
+				if err != nil {
+					return err
+				}
+				baseDate = parsed
+			}
+
+			store, _, err := db.OpenDefault(dbPath)
+			if err != nil {
+				return formatDBError(err)
+			}
+			defer store.Close()
+
+			tmpl, err := exportProjectTemplate(store, id, baseDate)
+			if err != nil {
+				return err
+			}
+
+			var encoded []byte
+			if format == "json" {
+				encoded, err = json.MarshalIndent(tmpl, "", "  ")
+				encoded = append(encoded, '\n')
+			} else {
+				encoded, err = templates.Marshal(tmpl)
+			}
+			if err != nil {
+				return fmt.Errorf("Error: encode template: %v", err)
+			}
+			if output != "" && output != "-" {
+				if err := os.WriteFile(output, encoded, 0o644); err != nil {
+					return fmt.Errorf("Error: write %s: %v", output, err)
+				}
+				return nil
+			}
+			_, err = app.Out.Write(encoded)
+			return err
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
+	flags.StringVar(&id, "id", "", "Project ID or title to export")
+	flags.StringVar(&base, "base", "", "Base date for relative when/deadline offsets (default: today)")
+	flags.StringVar(&format, "format", "yaml", "Output format: yaml or json")
+	flags.StringVarP(&output, "output", "o", "", "Write the template to a file instead of stdout")
+
+	return cmd
+}
+
+func exportProjectTemplate(store *db.Store, input string, base time.Time) (*templates.Template, error) {
+	projectID, err := store.ResolveProjectID(input)
+	if err != nil {
+		return nil, fmt.Errorf("Error: %s", err)
+	}
+	project, err := store.TaskByID(projectID)
+	if err != nil {
+		return nil, formatDBError(err)
+	}
+
+	status := db.StatusIncomplete
+	filter := db.TaskFilter{
+		Status:                &status,
+		ExcludeTrashedContext: true,
+		ProjectID:             projectID,
+		IncludeRepeating:      true,
+	}
+	trees, err := store.ProjectsTree(filter, false)
+	if err != nil {
+		return nil, formatDBError(err)
+	}
+	if len(trees) == 0 {
+		return nil, fmt.Errorf("Error: project not found: %s", input)
+	}
+
+	taskFilter := filter
+	taskFilter.Types = []int{db.TaskTypeTodo}
+	taskFilter.IncludeChecklist = true
+	tasks, err := store.Tasks(taskFilter)
+	if err != nil {
+		return nil, formatDBError(err)
+	}
+	byID := make(map[string]db.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.UUID] = task
+	}
+	return templates.FromProject(*project, trees[0], byID, base), nil
+}
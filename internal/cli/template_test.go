@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTemplateApplyOpensJSONURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.yaml")
+	body := "title: \"Release {{version}}\"\nheadings:\n  - title: Prep\n    todos:\n      - title: Tag {{version}}\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	launcher := &recordLauncher{}
+	app := &App{
+		In:       strings.NewReader(""),
+		Out:      &bytes.Buffer{},
+		Err:      &bytes.Buffer{},
+		Launcher: launcher,
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"template", "apply", "--var", "version=2.0", path})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	raw := requireOpenURL(t, launcher)
+	if !strings.HasPrefix(raw, "things:///json?data=") {
+		t.Fatalf("expected json url, got %q", raw)
+	}
+	decoded, err := url.QueryUnescape(raw)
+	if err != nil {
+		t.Fatalf("unescape: %v", err)
+	}
+	if !strings.Contains(decoded, "Release 2.0") || !strings.Contains(decoded, "Tag 2.0") {
+		t.Fatalf("expected substituted titles, got %q", decoded)
+	}
+}
+
+func TestTemplateApplyMissingVar(t *testing.T) {
+	launcher := &recordLauncher{}
+	app := &App{
+		In:       strings.NewReader("title: \"{{client}} onboarding\"\n"),
+		Out:      &bytes.Buffer{},
+		Err:      &bytes.Buffer{},
+		Launcher: launcher,
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"template", "apply"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "client") {
+		t.Fatalf("expected missing variable error, got %v", err)
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no open invocation")
+	}
+}
+
+func TestTemplateExportFromDatabase(t *testing.T) {
+	dbPath := writeTestDB(t)
+	out := &bytes.Buffer{}
+	app := &App{
+		In:  strings.NewReader(""),
+		Out: out,
+		Err: &bytes.Buffer{},
+	}
+
+	root := NewRoot(app)
+	root.SetArgs([]string{"template", "export", "--db", dbPath, "--id", "P1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("execute failed: %v", err)
+	}
+	text := out.String()
+	for _, want := range []string{"title: Project One", "area: Home", "- title: Heading", "- title: Task One"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, text)
+		}
+	}
+}
@@ -1,9 +1,18 @@
 package cli
 
-import "fmt"
+import (
+	"fmt"
+	"os/exec"
+)
 
 const thingsBundleID = "com.culturedcode.ThingsMac"
 
+// thingsRunning reports whether the Things app is running. Without pgrep it
+// reports false.
+var thingsRunning = func() bool {
+	return exec.Command("pgrep", "-x", "Things3").Run() == nil
+}
+
 func ensureThingsLaunched(app *App) {
 	if app == nil || app.Launcher == nil {
 		return
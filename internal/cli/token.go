@@ -12,12 +12,31 @@ func authTokenFromEnv() string {
 	return strings.TrimSpace(os.Getenv("THINGS_AUTH_TOKEN"))
 }
 
+// configuredAuthToken finds the token without an explicit --auth-token: the
+// active profile's token source first, then THINGS_AUTH_TOKEN. An empty
+// token with no error means none is configured.
+func configuredAuthToken(app *App) (string, string, error) {
+	if app != nil && app.Profile != nil {
+		token, source, err := app.Profile.Token()
+		if err != nil {
+			return "", "", fmt.Errorf("Error: profile %s: %v", app.ProfileName, err)
+		}
+		if token != "" {
+			return token, fmt.Sprintf("profile %s, %s", app.ProfileName, source), nil
+		}
+	}
+	return authTokenFromEnv(), "THINGS_AUTH_TOKEN", nil
+}
+
 func resolveAuthToken(app *App, explicit string) (string, error) {
 	token := strings.TrimSpace(explicit)
 	source := "--auth-token"
 	if token == "" {
-		token = authTokenFromEnv()
-		source = "THINGS_AUTH_TOKEN"
+		var err error
+		token, source, err = configuredAuthToken(app)
+		if err != nil {
+			return "", err
+		}
 	}
 	if token == "" {
 		return "", things.ErrMissingAuthToken
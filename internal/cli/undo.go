@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
 	"github.com/ossianhempel/things3-cli/internal/things"
@@ -12,23 +13,34 @@ import (
 
 // NewUndoCommand builds the undo subcommand.
 func NewUndoCommand(app *App) *cobra.Command {
+	var dbPath string
+	var id string
 	var authToken string
 	var yes bool
+	var force bool
 
 	cmd := &cobra.Command{
-		Use:   "undo",
-		Short: "Undo the last bulk action",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			entry, err := readLastAction()
+		Use:   "undo [OPTIONS...]",
+		Short: "Undo a logged action",
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			entries, err := readActions()
 			if err != nil {
 				return fmt.Errorf("Error: %s", err)
 			}
+			idx, err := findAction(entries, id, false)
+			if err != nil {
+				return fmt.Errorf("Error: %s", err)
+			}
+			entry := entries[idx]
+			if entry.Undone {
+				return fmt.Errorf("Error: action %s is already undone (use things redo --id=%s)", entry.ID, entry.ID)
+			}
 			if len(entry.Items) == 0 {
-				return fmt.Errorf("Error: no actions logged")
+				return fmt.Errorf("Error: action %s has no items to undo", entry.ID)
 			}
 
 			if app.DryRun {
-				fmt.Fprintf(app.Out, "Would undo %s for %d tasks\n", entry.Type, len(entry.Items))
+				fmt.Fprintf(app.Out, "Would undo %s %s for %d items\n", entry.Type, entry.ID, len(entry.Items))
 				return previewActionItems(app.Out, entry.Items)
 			}
 
@@ -36,80 +48,24 @@ func NewUndoCommand(app *App) *cobra.Command {
 				return fmt.Errorf("Error: %d tasks matched (rerun with --yes to apply)", len(entry.Items))
 			}
 
-			switch entry.Type {
-			case ActionUpdate:
-				token, err := resolveAuthToken(app, authToken)
-				if err != nil {
-					return err
-				}
-				warnIncomplete := 0
-				for _, item := range entry.Items {
-					opts := things.UpdateOptions{
-						AuthToken: token,
-						ID:        item.UUID,
-						Notes:     item.Notes,
-						Tags:      strings.Join(item.Tags, ","),
-						Deadline:  item.Deadline,
-						Heading:   item.HeadingTitle,
-					}
-					when := whenFromActionItem(item)
-					if when != "" {
-						opts.When = when
-					}
-					if item.ProjectID != "" {
-						opts.ListID = item.ProjectID
-					} else if item.AreaID != "" {
-						opts.ListID = item.AreaID
-					}
-					switch item.Status {
-					case db.StatusCompleted:
-						opts.Completed = true
-					case db.StatusCanceled:
-						opts.Canceled = true
-					case db.StatusIncomplete:
-						warnIncomplete++
-					}
-					url, err := things.BuildUpdateURL(opts, item.Title)
-					if err != nil {
-						return err
-					}
-					if err := openURL(app, url); err != nil {
-						return err
-					}
-				}
-				if warnIncomplete > 0 {
-					fmt.Fprintln(app.Err, "Warning: Things URL scheme cannot un-complete tasks; some items may remain completed.")
+			store, err := openActionStore(dbPath, entry.Type == ActionRepeat)
+			if err != nil {
+				if actionNeedsStore(entry.Type) {
+					return formatDBError(err)
 				}
-			case ActionTrash:
-				for _, item := range entry.Items {
-					opts := things.AddOptions{
-						Notes:    item.Notes,
-						Tags:     strings.Join(item.Tags, ","),
-						Deadline: item.Deadline,
-					}
-					when := whenFromActionItem(item)
-					if when != "" {
-						opts.When = when
-					}
-					if item.ProjectID != "" {
-						opts.ListID = item.ProjectID
-					} else if item.AreaID != "" {
-						opts.ListID = item.AreaID
-					}
-					if item.HeadingTitle != "" {
-						opts.Heading = item.HeadingTitle
-					}
-					url := things.BuildAddURL(opts, item.Title)
-					if err := openURL(app, url); err != nil {
-						return err
-					}
+				fmt.Fprintf(app.Err, "Warning: could not verify current state: %v\n", err)
+			}
+			if store != nil {
+				defer closeWriteStore(app, store, &err)
+				if err := checkActionDrift(app, store, entry, true, force); err != nil {
+					return err
 				}
-				fmt.Fprintln(app.Err, "Warning: restored tasks are new items; trashed originals remain in Trash.")
-			default:
-				return fmt.Errorf("Error: unsupported action type %q", entry.Type)
 			}
 
-			if err := removeLastAction(); err != nil {
+			if err := undoAction(app, store, entry, authToken); err != nil {
+				return err
+			}
+			if err := setActionUndone(entry.ID, true); err != nil {
 				fmt.Fprintf(app.Err, "Warning: failed to update action log: %v\n", err)
 			}
 			return nil
@@ -117,12 +73,429 @@ func NewUndoCommand(app *App) *cobra.Command {
 	}
 
 	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
+	flags.StringVar(&id, "id", "", "ID of the logged action to undo (see things history)")
 	flags.StringVar(&authToken, "auth-token", "", "Things URL scheme authorization token")
 	flags.BoolVar(&yes, "yes", false, "Confirm undo for multiple tasks")
+	flags.BoolVar(&force, "force", false, "Undo even if items changed since the action was logged")
 
 	return cmd
 }
 
+func undoAction(app *App, store *db.Store, entry ActionEntry, authToken string) error {
+	switch entry.Type {
+	case ActionUpdate:
+		token, err := resolveAuthToken(app, authToken)
+		if err != nil {
+			return err
+		}
+		var reopen []things.StatusTarget
+		for _, item := range entry.Items {
+			opts := things.UpdateOptions{
+				AuthToken: token,
+				ID:        item.UUID,
+				Notes:     item.Notes,
+				Tags:      strings.Join(item.Tags, ","),
+				Deadline:  item.Deadline,
+				Heading:   item.HeadingTitle,
+			}
+			when := whenFromActionItem(item)
+			if when != "" {
+				opts.When = when
+			}
+			if item.ProjectID != "" {
+				opts.ListID = item.ProjectID
+			} else if item.AreaID != "" {
+				opts.ListID = item.AreaID
+			}
+			switch item.Status {
+			case db.StatusCompleted:
+				opts.Completed = true
+			case db.StatusCanceled:
+				opts.Canceled = true
+			case db.StatusIncomplete:
+				if item.Expected == nil || item.Expected.Status != db.StatusLabel(db.StatusIncomplete) {
+					reopen = append(reopen, things.StatusTarget{ID: item.UUID, Status: things.StatusOpen})
+				}
+			}
+			url, err := things.BuildUpdateURL(opts, item.Title)
+			if err != nil {
+				return err
+			}
+			if err := openURL(app, url); err != nil {
+				return err
+			}
+		}
+		// The URL scheme cannot un-complete todos, so reopen them via AppleScript.
+		if len(reopen) > 0 {
+			script, err := things.BuildStatusScript(reopen)
+			if err != nil {
+				return err
+			}
+			return runScript(app, script)
+		}
+	case ActionUpdateProject:
+		token, err := resolveAuthToken(app, authToken)
+		if err != nil {
+			return err
+		}
+		for _, item := range entry.Items {
+			opts := things.UpdateProjectOptions{
+				AuthToken: token,
+				ID:        item.UUID,
+				Notes:     item.Notes,
+				Tags:      strings.Join(item.Tags, ","),
+				Deadline:  item.Deadline,
+				When:      whenFromActionItem(item),
+				AreaID:    item.AreaID,
+			}
+			switch item.Status {
+			case db.StatusCompleted:
+				opts.Completed = true
+			case db.StatusCanceled:
+				opts.Canceled = true
+			}
+			url, err := things.BuildUpdateProjectURL(opts, item.Title)
+			if err != nil {
+				return err
+			}
+			if err := openURL(app, url); err != nil {
+				return err
+			}
+		}
+	case ActionTrash:
+		targets := make([]things.RestoreTarget, 0, len(entry.Items))
+		for _, item := range entry.Items {
+			targets = append(targets, restoreTarget(item))
+		}
+		script, err := things.BuildRestoreScript(targets)
+		if err != nil {
+			return err
+		}
+		if err := runScript(app, script); err != nil {
+			return err
+		}
+		warnUnrestoredHeadings(app, entry.Items)
+	case ActionStatus:
+		targets := make([]things.StatusTarget, 0, len(entry.Items))
+		for _, item := range entry.Items {
+			targets = append(targets, things.StatusTarget{ID: item.UUID, Status: scriptStatus(item.Status)})
+		}
+		script, err := things.BuildStatusScript(targets)
+		if err != nil {
+			return err
+		}
+		return runScript(app, script)
+	case ActionRestore:
+		ids := make([]string, 0, len(entry.Items))
+		for _, item := range entry.Items {
+			ids = append(ids, item.UUID)
+		}
+		script, err := things.BuildTrashScript(ids)
+		if err != nil {
+			return err
+		}
+		return runScript(app, script)
+	case ActionAdd, ActionAddProject:
+		taskType := db.TaskTypeTodo
+		if entry.Type == ActionAddProject {
+			taskType = db.TaskTypeProject
+		}
+		ids := make([]string, 0, len(entry.Items))
+		for _, item := range entry.Items {
+			createdID, err := findLoggedCreation(store, item, taskType, entry.Timestamp)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, createdID)
+		}
+		script, err := things.BuildTrashScript(ids)
+		if err != nil {
+			return err
+		}
+		return runScript(app, script)
+	case ActionAddArea:
+		// Areas are only deleted by ID: another area may share the title.
+		areas, err := store.Areas()
+		if err != nil {
+			return formatDBError(err)
+		}
+		current := map[string]bool{}
+		for _, area := range areas {
+			current[area.UUID] = true
+		}
+		for _, item := range entry.Items {
+			if item.UUID == "" {
+				return fmt.Errorf("Error: the ID of area %q was not logged; delete it manually", item.Title)
+			}
+			if !current[item.UUID] {
+				return fmt.Errorf("Error: area %q (%s) no longer exists", item.Title, item.UUID)
+			}
+		}
+		for _, item := range entry.Items {
+			script, err := things.BuildDeleteAreaScript(things.DeleteAreaOptions{ID: item.UUID}, "")
+			if err != nil {
+				return err
+			}
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+		}
+	case ActionUpdateArea:
+		for _, item := range entry.Items {
+			opts := things.UpdateAreaOptions{
+				ID:        item.UUID,
+				Title:     item.Title,
+				Tags:      strings.Join(item.Tags, ","),
+				ClearTags: item.TagsKnown && len(item.Tags) == 0,
+			}
+			if !item.TagsKnown {
+				fmt.Fprintf(app.Err, "Warning: tags of area %q were not logged and are left unchanged.\n", item.Title)
+			}
+			script, err := things.BuildUpdateAreaScript(opts, item.Title)
+			if err != nil {
+				return err
+			}
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+		}
+	case ActionDeleteArea:
+		for _, item := range entry.Items {
+			script, err := things.BuildAddAreaScript(things.AddAreaOptions{Tags: strings.Join(item.Tags, ",")}, item.Title)
+			if err != nil {
+				return err
+			}
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintln(app.Err, "Warning: restored areas are new and empty; projects and todos of the deleted area are not restored.")
+		for _, item := range entry.Items {
+			if !item.TagsKnown {
+				fmt.Fprintf(app.Err, "Warning: tags of area %q were not logged and are not restored.\n", item.Title)
+			}
+		}
+	case ActionRepeat:
+		for _, item := range entry.Items {
+			var err error
+			if item.Repeat == nil {
+				err = store.ClearRepeatRule(item.UUID)
+			} else {
+				err = store.ApplyRepeatRule(item.UUID, *item.Repeat)
+			}
+			if err != nil {
+				return formatDBError(err)
+			}
+		}
+	default:
+		return fmt.Errorf("Error: unsupported action type %q", entry.Type)
+	}
+	return nil
+}
+
+func actionNeedsStore(actionType ActionType) bool {
+	switch actionType {
+	case ActionAdd, ActionAddProject, ActionAddArea, ActionRepeat:
+		return true
+	default:
+		return false
+	}
+}
+
+func openActionStore(dbPath string, writable bool) (*db.Store, error) {
+	if writable {
+		store, _, err := db.OpenDefaultWritable(dbPath)
+		return store, err
+	}
+	store, _, err := db.OpenDefault(dbPath)
+	return store, err
+}
+
+// findLoggedCreation locates the item created by a logged add: the logged
+// UUID while that item is still untrashed, and otherwise the one item with
+// the title created since the action (after an undo/redo, the newest copy).
+// Items are never matched by their modification date, so an older item
+// with the same title that was edited later is left alone.
+func findLoggedCreation(store *db.Store, item ActionItem, taskType int, timestamp string) (string, error) {
+	title := item.Title
+	if item.UUID != "" {
+		if task, err := store.TaskByID(item.UUID); err == nil && !task.Trashed {
+			return item.UUID, nil
+		}
+	}
+	logged, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "", fmt.Errorf("Error: invalid action timestamp %q", timestamp)
+	}
+	matches, err := store.TasksCreatedSince(title, taskType, float64(logged.Add(-5*time.Second).Unix()))
+	if err != nil {
+		return "", formatDBError(err)
+	}
+	ids := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.UUID)
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("Error: could not find the item created as %q", title)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("Error: multiple items titled %q were created since the action; trash the right one manually", title)
+	}
+}
+
+// checkActionDrift refuses to undo (or redo) when the logged items no longer
+// look the way the action left them (or found them), unless force is set.
+func checkActionDrift(app *App, store *db.Store, entry ActionEntry, undo bool, force bool) error {
+	problems := actionDrift(store, entry, undo)
+	if len(problems) == 0 {
+		return nil
+	}
+	verb := "undo"
+	if !undo {
+		verb = "redo"
+	}
+	if force {
+		fmt.Fprintf(app.Err, "Warning: items changed since action %s was logged:\n  %s\n", entry.ID, strings.Join(problems, "\n  "))
+		return nil
+	}
+	return fmt.Errorf("Error: items changed since action %s was logged:\n  %s\n(rerun with --force to %s anyway)", entry.ID, strings.Join(problems, "\n  "), verb)
+}
+
+func actionDrift(store *db.Store, entry ActionEntry, undo bool) []string {
+	problems := []string{}
+	switch entry.Type {
+	case ActionUpdate, ActionUpdateProject, ActionStatus:
+		for _, item := range entry.Items {
+			expected := planStateFromActionItem(item)
+			if undo {
+				if item.Expected == nil {
+					continue
+				}
+				expected = *item.Expected
+			}
+			current, err := store.TaskByID(item.UUID)
+			if err != nil {
+				problems = append(problems, item.UUID+": not found")
+				continue
+			}
+			if fields := stateDrift(expected, planStateFromTask(*current)); len(fields) > 0 {
+				problems = append(problems, fmt.Sprintf("%s: %s changed", item.UUID, strings.Join(fields, ", ")))
+			}
+		}
+	case ActionTrash, ActionRestore:
+		// Undoing a trash (or redoing a restore) expects the items in Trash;
+		// the opposite direction expects them out of it.
+		wantTrashed := (entry.Type == ActionTrash) == undo
+		for _, item := range entry.Items {
+			current, err := store.TaskByID(item.UUID)
+			if err != nil {
+				problems = append(problems, item.UUID+": not found")
+				continue
+			}
+			if current.Trashed != wantTrashed {
+				if wantTrashed {
+					problems = append(problems, item.UUID+": no longer in Trash")
+				} else {
+					problems = append(problems, item.UUID+": already in Trash")
+				}
+			}
+		}
+	case ActionRepeat:
+		for i, item := range entry.Items {
+			want := item.Repeat
+			if undo {
+				want = nil
+				if i < len(entry.Redo) {
+					want = entry.Redo[i].Repeat
+				}
+			}
+			current, err := store.RepeatRuleByID(item.UUID)
+			if err != nil {
+				problems = append(problems, item.UUID+": not found")
+				continue
+			}
+			if !sameRepeatRule(want, current) {
+				problems = append(problems, item.UUID+": repeat rule changed")
+			}
+		}
+	}
+	return problems
+}
+
+func sameRepeatRule(a, b *db.RepeatUpdate) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return string(a.RecurrenceRule) == string(b.RecurrenceRule) &&
+		a.InstanceCreationPaused == b.InstanceCreationPaused &&
+		a.InstanceCreationStartDate == b.InstanceCreationStartDate &&
+		sameDateValue(a.NextInstanceStartDate, b.NextInstanceStartDate) &&
+		sameDateValue(a.Deadline, b.Deadline)
+}
+
+func sameDateValue(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// stateDrift lists fields where current differs from expected. When and
+// deadline are compared only when expected holds a concrete date, since
+// keywords such as "today" are stored as dates. List, heading, and checklist
+// are not compared because titles and IDs cannot be matched reliably.
+func stateDrift(expected, current PlanState) []string {
+	fields := []string{}
+	for _, change := range diffPlanStates(expected, current) {
+		switch change.Field {
+		case "when", "deadline":
+			if !isISODate(change.Before) {
+				continue
+			}
+		case "tags":
+			if sameTagSet(expected.Tags, current.Tags) {
+				continue
+			}
+		case "list", "heading", "checklist":
+			continue
+		}
+		fields = append(fields, change.Field)
+	}
+	return fields
+}
+
+func isISODate(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+func sameTagSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, tag := range a {
+		if !containsFold(b, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func planStateFromActionItem(item ActionItem) PlanState {
+	return PlanState{
+		Title:    item.Title,
+		Status:   db.StatusLabel(item.Status),
+		Notes:    item.Notes,
+		Tags:     item.Tags,
+		When:     whenFromActionItem(item),
+		Deadline: item.Deadline,
+	}
+}
+
 func whenFromActionItem(item ActionItem) string {
 	if item.StartDate != "" {
 		return item.StartDate
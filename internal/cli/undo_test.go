@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+func useTempActionLog(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+}
+
+func TestReadActionsNumbersLegacyEntries(t *testing.T) {
+	useTempActionLog(t)
+	if err := writeActions([]ActionEntry{{Type: ActionTrash}, {Type: ActionUpdate}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	// Entries without IDs predate numbering and are numbered by position.
+	entries, err := readActions()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if entries[0].ID != "1" || entries[1].ID != "2" {
+		t.Fatalf("unexpected ids: %+v", entries)
+	}
+	if err := appendAction(ActionEntry{Type: ActionAdd, Items: []ActionItem{{Title: "New"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	entries, _ = readActions()
+	if got := entries[len(entries)-1].ID; got != "3" {
+		t.Fatalf("expected id 3, got %q", got)
+	}
+
+	if err := setActionUndone("3", true); err != nil {
+		t.Fatalf("mark undone: %v", err)
+	}
+	entries, _ = readActions()
+	if idx, err := findAction(entries, "", false); err != nil || entries[idx].ID != "2" {
+		t.Fatalf("expected latest active entry 2, got %d (%v)", idx, err)
+	}
+	if idx, err := findAction(entries, "", true); err != nil || entries[idx].ID != "3" {
+		t.Fatalf("expected latest undone entry 3, got %d (%v)", idx, err)
+	}
+}
+
+func TestUndoRefusesWhenStateDrifted(t *testing.T) {
+	useTempActionLog(t)
+	t.Setenv("THINGS_AUTH_TOKEN", "token")
+	dbPath := writeTestDB(t)
+	expected := PlanState{Title: "Task One", Status: "incomplete", Notes: "Different notes", Tags: []string{"urgent"}}
+	if err := appendAction(ActionEntry{
+		Type:  ActionUpdate,
+		Items: []ActionItem{{UUID: "T1", Title: "Task One", Notes: "Old", Expected: &expected}},
+		Redo:  []ActionStep{{URL: "things:///update?auth-token=***&id=T1&notes=Different%20notes"}},
+	}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	launcher := &recordLauncher{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+	root := NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath, "--id", "1"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "T1: notes changed") {
+		t.Fatalf("expected drift error, got %v", err)
+	}
+	if len(launcher.args) != 0 {
+		t.Fatalf("expected no open invocation")
+	}
+
+	root = NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath, "--id", "1", "--force"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("forced undo failed: %v", err)
+	}
+	url := requireOpenURL(t, launcher)
+	if !strings.Contains(url, "id=T1") || !strings.Contains(url, "notes=Old") {
+		t.Fatalf("unexpected undo url %q", url)
+	}
+	entries, _ := readActions()
+	if !entries[0].Undone {
+		t.Fatalf("expected entry marked undone")
+	}
+}
+
+func TestRedoReplaysStoredURLWithToken(t *testing.T) {
+	useTempActionLog(t)
+	t.Setenv("THINGS_AUTH_TOKEN", "secret")
+	// Nothing creates the area, so don't wait for it.
+	defer func(timeout time.Duration) { createdAreaTimeout = timeout }(createdAreaTimeout)
+	createdAreaTimeout = 0
+	if err := writeActions([]ActionEntry{{
+		ID:     "1",
+		Type:   ActionAddArea,
+		Undone: true,
+		Items:  []ActionItem{{Title: "Garden"}},
+		Redo:   []ActionStep{{URL: "things:///update?auth-token=***&id=X"}},
+	}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	launcher := &recordLauncher{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Launcher: launcher}
+	root := NewRoot(app)
+	root.SetArgs([]string{"redo", "--db", writeTestDB(t)})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("redo failed: %v", err)
+	}
+	if url := requireOpenURL(t, launcher); url != "things:///update?auth-token=secret&id=X" {
+		t.Fatalf("unexpected redo url %q", url)
+	}
+	entries, _ := readActions()
+	if entries[0].Undone {
+		t.Fatalf("expected entry marked done after redo")
+	}
+}
+
+func TestUndoAddTrashesCreatedTask(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	if err := appendAction(ActionEntry{Type: ActionAdd, Items: []ActionItem{{Title: "Task One"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+	root := NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `"T1"`) {
+		t.Fatalf("expected T1 to be trashed, got %q", script)
+	}
+}
+
+func TestUndoAddUsesLoggedUUIDAndCreationDate(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	// An older todo with the logged title, edited after the add.
+	now := float64(time.Now().Unix())
+	if _, err := conn.Exec(`UPDATE TMTask SET creationDate = ?, userModificationDate = ? WHERE uuid = 'T1'`, now-86400, now); err != nil {
+		t.Fatalf("age task: %v", err)
+	}
+	conn.Close()
+
+	undo := func() (*recordScriptRunner, error) {
+		runner := &recordScriptRunner{}
+		app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+		root := NewRoot(app)
+		root.SetArgs([]string{"undo", "--db", dbPath})
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		return runner, root.Execute()
+	}
+
+	if err := appendAction(ActionEntry{Type: ActionAdd, Items: []ActionItem{{Title: "Task One"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if _, err := undo(); err == nil || !strings.Contains(err.Error(), "could not find") {
+		t.Fatalf("expected the edited older todo to be left alone, got %v", err)
+	}
+
+	// A logged UUID wins even when the todo was renamed since.
+	if err := appendAction(ActionEntry{Type: ActionAdd, Items: []ActionItem{{UUID: "ANY1", Title: "Renamed"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	runner, err := undo()
+	if err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if script := requireScript(t, runner); !strings.Contains(script, `"ANY1"`) {
+		t.Fatalf("expected ANY1 to be trashed, got %q", script)
+	}
+}
+
+func TestUndoUpdateAreaRestoresTags(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE TMAreaTag (areas TEXT NOT NULL, tags TEXT NOT NULL);
+		INSERT INTO TMAreaTag (areas, tags) VALUES ('A1', 'TAG1');`); err != nil {
+		t.Fatalf("tag area: %v", err)
+	}
+	conn.Close()
+
+	run := func(args ...string) *recordScriptRunner {
+		runner := &recordScriptRunner{}
+		app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%s failed: %v", args[0], err)
+		}
+		return runner
+	}
+
+	run("update-area", "--db", dbPath, "--id", "A1", "--tags", "Focus")
+	entries, err := readActions()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one logged action, got %#v, %v", entries, err)
+	}
+	if item := entries[0].Items[0]; !item.TagsKnown || len(item.Tags) != 1 || item.Tags[0] != "urgent" {
+		t.Fatalf("expected area tags in the log, got %#v", item)
+	}
+
+	script := requireScript(t, run("undo", "--db", dbPath))
+	if !strings.Contains(script, `set tag names of targetArea to "urgent"`) {
+		t.Fatalf("expected tags restored, got %q", script)
+	}
+}
+
+func TestHistoryListsEntries(t *testing.T) {
+	useTempActionLog(t)
+	if err := appendAction(ActionEntry{Type: ActionAdd, Items: []ActionItem{{Title: "Milk"}, {Title: "Eggs"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := appendAction(ActionEntry{Type: ActionTrash, Items: []ActionItem{{UUID: "T1", Title: "Old"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := setActionUndone("2", true); err != nil {
+		t.Fatalf("mark undone: %v", err)
+	}
+
+	out := &bytes.Buffer{}
+	app := &App{In: strings.NewReader(""), Out: out, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"history"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("history failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %q", out.String())
+	}
+	if !strings.HasPrefix(lines[1], "2") || !strings.Contains(lines[1], "undone") {
+		t.Fatalf("expected newest undone entry first, got %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "Milk (+1 more)") {
+		t.Fatalf("expected summary, got %q", lines[2])
+	}
+}
+
+func TestUndoAddAreaDeletesLoggedID(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	if err := appendAction(ActionEntry{Type: ActionAddArea, Items: []ActionItem{{UUID: "A1", Title: "Home"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+	root := NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	script := requireScript(t, runner)
+	if !strings.Contains(script, `first area whose id is "A1"`) || strings.Contains(script, `area "Home"`) {
+		t.Fatalf("expected delete by ID, got %q", script)
+	}
+}
+
+func TestUndoAddAreaRefusesWithoutID(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	if err := appendAction(ActionEntry{Type: ActionAddArea, Items: []ActionItem{{Title: "Home"}}}); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+
+	runner := &recordScriptRunner{}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+	root := NewRoot(app)
+	root.SetArgs([]string{"undo", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "was not logged") {
+		t.Fatalf("expected missing ID error, got %v", err)
+	}
+	if runner.script != "" {
+		t.Fatalf("expected no script, got %q", runner.script)
+	}
+}
+
+func TestRedoAddAreaLogsNewAreaID(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	if err := writeActions([]ActionEntry{{
+		ID:     "1",
+		Type:   ActionAddArea,
+		Undone: true,
+		Items:  []ActionItem{{UUID: "OLD", Title: "Garden"}},
+		Redo:   []ActionStep{{Script: "make new area"}},
+	}}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	runner := &createAreaRunner{dbPath: dbPath, uuid: "A2", title: "Garden"}
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}, Scripter: runner}
+	root := NewRoot(app)
+	root.SetArgs([]string{"redo", "--db", dbPath})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err != nil {
+		t.Fatalf("redo failed: %v", err)
+	}
+	entries, _ := readActions()
+	if entries[0].Undone || entries[0].Items[0].UUID != "A2" {
+		t.Fatalf("expected redone entry with the new area ID, got %+v", entries[0])
+	}
+}
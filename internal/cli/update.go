@@ -18,15 +18,17 @@ func NewUpdateCommand(app *App) *cobra.Command {
 	var noVerify bool
 	var allowNonToday bool
 	var yes bool
+	var planPath string
 	queryOpts := TaskQueryOptions{
-		Status: "incomplete",
-		Limit:  200,
+		Status:     "incomplete",
+		Limit:      200,
+		ExactMatch: true,
 	}
 
 	cmd := &cobra.Command{
 		Use:   "update [OPTIONS...] [--] [-|TITLE]",
 		Short: "Update an existing todo",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			rawInput, err := readInput(app.In, args)
 			if err != nil {
 				return err
@@ -64,6 +66,58 @@ func NewUpdateCommand(app *App) *cobra.Command {
 				return fmt.Errorf("Error: use either --id or query filters")
 			}
 
+			if planPath != "" {
+				if repeatSpec.Enabled {
+					return fmt.Errorf("Error: --plan does not support repeat options")
+				}
+				if strings.TrimSpace(opts.ID) == "" && !hasExplicitSelector(map[string]bool{"status": changedStatus}, queryOpts) {
+					return fmt.Errorf("Error: --plan requires --id or query filters")
+				}
+				store, _, err := db.OpenDefault(dbPath)
+				if err != nil {
+					return formatDBError(err)
+				}
+				defer store.Close()
+
+				var tasks []db.Task
+				if strings.TrimSpace(opts.ID) != "" {
+					task, err := store.TaskByID(opts.ID)
+					if err != nil {
+						return formatDBError(err)
+					}
+					tasks = []db.Task{*task}
+				} else {
+					queryOpts.IncludeChecklist = true
+					tasks, err = fetchTasks(store, store.Tasks, queryOpts, false, []int{db.TaskTypeTodo})
+					if err != nil {
+						return formatDBError(err)
+					}
+				}
+				if len(tasks) == 0 {
+					return fmt.Errorf("Error: no tasks matched")
+				}
+				if rawInput != "" && len(tasks) > 1 {
+					return fmt.Errorf("Error: bulk update does not accept input (use --id or refine the query)")
+				}
+				if err := checkWhenTargets(tasks, opts, allowNonToday); err != nil {
+					return err
+				}
+
+				plan, err := buildUpdatePlan(store, tasks, opts, rawInput)
+				if err != nil {
+					return formatDBError(err)
+				}
+				plan.AllowNonToday = allowNonToday
+				if err := writeUpdatePlan(app.Out, planPath, plan); err != nil {
+					return err
+				}
+				if planPath != "-" {
+					printPlanDiff(app.Out, plan)
+					fmt.Fprintf(app.Out, "\nWrote plan to %s (run: things apply %s)\n", planPath, planPath)
+				}
+				return nil
+			}
+
 			if strings.TrimSpace(opts.ID) == "" {
 				if !hasExplicitSelector(map[string]bool{"status": changedStatus}, queryOpts) {
 					if err := ensureAuth(); err != nil {
@@ -94,19 +148,8 @@ func NewUpdateCommand(app *App) *cobra.Command {
 				if app.DryRun {
 					return previewTasks(app.Out, tasks)
 				}
-				if guardEvening {
-					for _, task := range tasks {
-						if err := validateEveningTask(task, allowNonToday); err != nil {
-							return err
-						}
-					}
-				}
-				if verifyWhen != "" {
-					for _, task := range tasks {
-						if task.Repeating {
-							return fmt.Errorf("Error: cannot update when for repeating todos (id %s)", task.UUID)
-						}
-					}
+				if err := checkWhenTargets(tasks, opts, allowNonToday); err != nil {
+					return err
 				}
 				if len(tasks) > 1 && !yes {
 					return fmt.Errorf("Error: %d tasks matched (rerun with --yes to apply)", len(tasks))
@@ -119,20 +162,21 @@ func NewUpdateCommand(app *App) *cobra.Command {
 					Type:  ActionUpdate,
 					Items: make([]ActionItem, 0, len(tasks)),
 				}
-				for _, task := range tasks {
-					entry.Items = append(entry.Items, taskToActionItem(task))
-				}
-				if err := appendAction(entry); err != nil {
-					fmt.Fprintf(app.Err, "Warning: failed to write action log: %v\n", err)
-				}
-
+				urls := make([]string, 0, len(tasks))
 				for _, task := range tasks {
 					opts.ID = task.UUID
 					url, err := things.BuildUpdateURL(opts, rawInput)
 					if err != nil {
 						return err
 					}
-					if err := openURL(app, url); err != nil {
+					urls = append(urls, url)
+					entry.Items = append(entry.Items, updateActionItem(task, opts, rawInput))
+					entry.Redo = append(entry.Redo, urlStep(url))
+				}
+				logAction(app, entry)
+
+				for i, task := range tasks {
+					if err := openURL(app, urls[i]); err != nil {
 						return err
 					}
 					if verifyWhenEnabled {
@@ -195,13 +239,11 @@ func NewUpdateCommand(app *App) *cobra.Command {
 				}
 				if logStore != nil {
 					if task, err := logStore.TaskByID(opts.ID); err == nil {
-						entry := ActionEntry{
+						logAction(app, ActionEntry{
 							Type:  ActionUpdate,
-							Items: []ActionItem{taskToActionItem(*task)},
-						}
-						if err := appendAction(entry); err != nil {
-							fmt.Fprintf(app.Err, "Warning: failed to write action log: %v\n", err)
-						}
+							Items: []ActionItem{updateActionItem(*task, opts, rawInput)},
+							Redo:  []ActionStep{urlStep(url)},
+						})
 					}
 					logStore.Close()
 				}
@@ -273,13 +315,11 @@ func NewUpdateCommand(app *App) *cobra.Command {
 				}
 				if logStore != nil {
 					if task, err := logStore.TaskByID(opts.ID); err == nil {
-						entry := ActionEntry{
+						logAction(app, ActionEntry{
 							Type:  ActionUpdate,
-							Items: []ActionItem{taskToActionItem(*task)},
-						}
-						if err := appendAction(entry); err != nil {
-							fmt.Fprintf(app.Err, "Warning: failed to write action log: %v\n", err)
-						}
+							Items: []ActionItem{updateActionItem(*task, opts, rawInput)},
+							Redo:  []ActionStep{urlStep(url)},
+						})
 					}
 					logStore.Close()
 				}
@@ -305,7 +345,7 @@ func NewUpdateCommand(app *App) *cobra.Command {
 			if err != nil {
 				return formatDBError(err)
 			}
-			defer store.Close()
+			defer closeWriteStore(app, store, &err)
 
 			targetID, usedTemplate, err := resolveRepeatTarget(store, opts.ID, db.TaskTypeTodo)
 			if err != nil {
@@ -314,9 +354,23 @@ func NewUpdateCommand(app *App) *cobra.Command {
 			if usedTemplate {
 				fmt.Fprintf(app.Err, "Note: resolved repeating template %s for update\n", targetID)
 			}
-			if err := applyRepeatSpec(store, targetID, repeatSpec); err != nil {
+			before, err := store.RepeatRuleByID(targetID)
+			if err != nil {
+				return formatDBError(err)
+			}
+			after, err := applyRepeatSpec(store, targetID, repeatSpec)
+			if err != nil {
 				return formatDBError(err)
 			}
+			targetTitle := targetID
+			if task, err := store.TaskByID(targetID); err == nil {
+				targetTitle = task.Title
+			}
+			logAction(app, ActionEntry{
+				Type:  ActionRepeat,
+				Items: []ActionItem{{UUID: targetID, Title: targetTitle, Repeat: before}},
+				Redo:  []ActionStep{{RepeatID: targetID, Repeat: after, RepeatClear: after == nil}},
+			})
 			return nil
 		},
 	}
@@ -342,12 +396,14 @@ func NewUpdateCommand(app *App) *cobra.Command {
 	flags.StringVar(&opts.CompletionDate, "completion-date", "", "Completion date (ISO8601)")
 	flags.StringVar(&opts.CreationDate, "creation-date", "", "Creation date (ISO8601)")
 	flags.StringVar(&opts.Heading, "heading", "", "Heading within a project")
+	flags.StringVar(&opts.HeadingID, "heading-id", "", "Heading ID to move to")
 	flags.StringVar(&opts.List, "list", "", "Project or area to move to")
 	flags.StringVar(&opts.ListID, "list-id", "", "Project or area ID to move to")
 	flags.StringArrayVar(&opts.ChecklistItems, "checklist-item", nil, "Checklist item (repeatable)")
 	flags.StringArrayVar(&opts.PrependChecklistItems, "prepend-checklist-item", nil, "Prepend checklist item (repeatable)")
 	flags.StringArrayVar(&opts.AppendChecklistItems, "append-checklist-item", nil, "Append checklist item (repeatable)")
 	flags.BoolVar(&yes, "yes", false, "Confirm bulk update")
+	flags.StringVar(&planPath, "plan", "", "Write a reviewable update plan to FILE instead of updating")
 	flags.BoolVar(&allowUnsafeTitle, "allow-unsafe-title", false, "Allow titles that look like flag assignments")
 	flags.BoolVar(&noVerify, "no-verify", false, "Skip verification of when updates against the Things database")
 	flags.BoolVar(&allowNonToday, "allow-non-today", false, "Allow moving non-today tasks to This Evening")
@@ -357,6 +413,15 @@ func NewUpdateCommand(app *App) *cobra.Command {
 	return cmd
 }
 
+// updateActionItem snapshots task for the action log along with the state the
+// update is expected to produce.
+func updateActionItem(task db.Task, opts things.UpdateOptions, rawInput string) ActionItem {
+	item := taskToActionItem(task)
+	expected := applyUpdateOptions(planStateFromTask(task), opts, rawInput)
+	item.Expected = &expected
+	return item
+}
+
 func hasTodoUpdateChanges(opts things.UpdateOptions, rawInput string) bool {
 	if strings.TrimSpace(rawInput) != "" {
 		return true
@@ -382,7 +447,7 @@ func hasTodoUpdateChanges(opts things.UpdateOptions, rawInput string) bool {
 	if opts.CompletionDate != "" || opts.CreationDate != "" {
 		return true
 	}
-	if opts.Heading != "" || opts.List != "" || opts.ListID != "" {
+	if opts.Heading != "" || opts.HeadingID != "" || opts.List != "" || opts.ListID != "" {
 		return true
 	}
 	if len(opts.ChecklistItems) > 0 || len(opts.PrependChecklistItems) > 0 || len(opts.AppendChecklistItems) > 0 {
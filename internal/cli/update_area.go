@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
 	"github.com/ossianhempel/things3-cli/internal/things"
 	"github.com/spf13/cobra"
 )
@@ -8,6 +11,7 @@ import (
 // NewUpdateAreaCommand builds the update-area subcommand.
 func NewUpdateAreaCommand(app *App) *cobra.Command {
 	opts := things.UpdateAreaOptions{}
+	var dbPath string
 
 	cmd := &cobra.Command{
 		Use:   "update-area [OPTIONS...] [--] [-|TITLE]",
@@ -22,11 +26,24 @@ func NewUpdateAreaCommand(app *App) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return runScript(app, script)
+			area, found := lookupArea(app, dbPath, opts.ID, rawInput)
+			if err := runScript(app, script); err != nil {
+				return err
+			}
+			if found {
+				logAction(app, ActionEntry{
+					Type:  ActionUpdateArea,
+					Items: []ActionItem{area},
+					Redo:  []ActionStep{{Script: script}},
+				})
+			}
+			return nil
 		},
 	}
 
 	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
 	flags.StringVar(&opts.ID, "id", "", "ID of the area to update")
 	flags.StringVar(&opts.Title, "title", "", "New title for the area")
 	flags.StringVar(&opts.Tags, "tags", "", "Replace tags")
@@ -34,3 +51,33 @@ func NewUpdateAreaCommand(app *App) *cobra.Command {
 
 	return cmd
 }
+
+// lookupArea finds an area by ID or title for the action log, with its
+// current tags when they can be read. Failures are ignored; the action simply
+// is not logged.
+func lookupArea(app *App, dbPath string, id string, rawInput string) (ActionItem, bool) {
+	if app.DryRun {
+		return ActionItem{}, false
+	}
+	store, _, err := db.OpenDefault(dbPath)
+	if err != nil {
+		return ActionItem{}, false
+	}
+	defer store.Close()
+	areas, err := store.Areas()
+	if err != nil {
+		return ActionItem{}, false
+	}
+	title := strings.TrimSpace(rawInput)
+	for _, area := range areas {
+		if (id != "" && area.UUID == id) || (id == "" && title != "" && strings.EqualFold(area.Title, title)) {
+			item := ActionItem{UUID: area.UUID, Title: area.Title}
+			if tags, err := store.AreaTags(area.UUID); err == nil {
+				item.Tags = tags
+				item.TagsKnown = true
+			}
+			return item, true
+		}
+	}
+	return ActionItem{}, false
+}
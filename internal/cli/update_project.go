@@ -1,6 +1,10 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
 	"github.com/ossianhempel/things3-cli/internal/things"
 	"github.com/spf13/cobra"
 )
@@ -8,16 +12,25 @@ import (
 // NewUpdateProjectCommand builds the update-project subcommand.
 func NewUpdateProjectCommand(app *App) *cobra.Command {
 	opts := things.UpdateProjectOptions{}
+	repeatOpts := RepeatOptions{}
+	var dbPath string
 	var allowUnsafeTitle bool
 
 	cmd := &cobra.Command{
 		Use:   "update-project [OPTIONS...] [--] [-|TITLE]",
 		Short: "Update an existing project",
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			rawInput, err := readInput(app.In, args)
 			if err != nil {
 				return err
 			}
+			repeatSpec, err := parseRepeatSpec(cmd, repeatOpts)
+			if err != nil {
+				return err
+			}
+			if repeatSpec.Enabled && strings.TrimSpace(opts.ID) == "" {
+				return fmt.Errorf("Error: repeating updates require --id")
+			}
 			title := extractTitle(rawInput, "")
 			if err := guardUnsafeTitle(title, allowUnsafeTitle); err != nil {
 				return err
@@ -26,21 +39,62 @@ func NewUpdateProjectCommand(app *App) *cobra.Command {
 				return err
 			}
 
-			token, err := resolveAuthToken(app, opts.AuthToken)
+			if !repeatSpec.Enabled || hasProjectUpdateChanges(opts, rawInput) {
+				if err := updateProjectURL(app, dbPath, opts, rawInput); err != nil {
+					return err
+				}
+			}
+			if !repeatSpec.Enabled {
+				return nil
+			}
+			if app.DryRun {
+				fmt.Fprintf(app.Out, "Would update repeating rule for %s\n", opts.ID)
+				fmt.Fprintln(app.Err, "Note: --repeat is skipped in --dry-run mode.")
+				return nil
+			}
+
+			store, _, err := db.OpenDefaultWritable(dbPath)
 			if err != nil {
-				return err
+				return formatDBError(err)
 			}
-			opts.AuthToken = token
+			defer closeWriteStore(app, store, &err)
 
-			url, err := things.BuildUpdateProjectURL(opts, rawInput)
+			targetID, usedTemplate, err := resolveRepeatTarget(store, opts.ID, db.TaskTypeProject)
 			if err != nil {
-				return err
+				return formatDBError(err)
+			}
+			if usedTemplate {
+				fmt.Fprintf(app.Err, "Note: resolved repeating template %s for update\n", targetID)
+			}
+			if !repeatSpec.Clear {
+				if err := checkProjectTemplate(store, targetID); err != nil {
+					return err
+				}
+			}
+			before, err := store.RepeatRuleByID(targetID)
+			if err != nil {
+				return formatDBError(err)
 			}
-			return openURL(app, url)
+			after, err := applyRepeatSpec(store, targetID, repeatSpec)
+			if err != nil {
+				return formatDBError(err)
+			}
+			targetTitle := targetID
+			if project, err := store.TaskByID(targetID); err == nil {
+				targetTitle = project.Title
+			}
+			logAction(app, ActionEntry{
+				Type:  ActionRepeat,
+				Items: []ActionItem{{UUID: targetID, Title: targetTitle, Repeat: before}},
+				Redo:  []ActionStep{{RepeatID: targetID, Repeat: after, RepeatClear: after == nil}},
+			})
+			return nil
 		},
 	}
 
 	flags := cmd.Flags()
+	flags.StringVarP(&dbPath, "db", "d", "", "Path to Things database (overrides THINGSDB)")
+	flags.StringVar(&dbPath, "database", "", "Alias for --db")
 	flags.StringVar(&opts.AuthToken, "auth-token", "", "Things URL scheme authorization token")
 	flags.StringVar(&opts.ID, "id", "", "ID of the project to update")
 	flags.StringVar(&opts.Notes, "notes", "", "Replace notes")
@@ -61,6 +115,81 @@ func NewUpdateProjectCommand(app *App) *cobra.Command {
 	flags.StringVar(&opts.CreationDate, "creation-date", "", "Creation date (ISO8601)")
 	flags.StringArrayVar(&opts.Todos, "todo", nil, "Todo title to add (repeatable)")
 	flags.BoolVar(&allowUnsafeTitle, "allow-unsafe-title", false, "Allow titles that look like flag assignments")
+	addRepeatFlags(cmd, &repeatOpts, true)
 
 	return cmd
 }
+
+// updateProjectURL applies the URL-scheme part of update-project and logs it.
+func updateProjectURL(app *App, dbPath string, opts things.UpdateProjectOptions, rawInput string) error {
+	token, err := resolveAuthToken(app, opts.AuthToken)
+	if err != nil {
+		return err
+	}
+	opts.AuthToken = token
+
+	url, err := things.BuildUpdateProjectURL(opts, rawInput)
+	if err != nil {
+		return err
+	}
+	var item *ActionItem
+	if !app.DryRun {
+		if store, _, err := db.OpenDefault(dbPath); err == nil {
+			if project, err := store.TaskByID(opts.ID); err == nil {
+				snapshot := projectActionItem(*project, opts, rawInput)
+				item = &snapshot
+			}
+			store.Close()
+		}
+	}
+	if err := openURL(app, url); err != nil {
+		return err
+	}
+	if item != nil {
+		logAction(app, ActionEntry{
+			Type:  ActionUpdateProject,
+			Items: []ActionItem{*item},
+			Redo:  []ActionStep{urlStep(url)},
+		})
+	}
+	return nil
+
+}
+
+func hasProjectUpdateChanges(opts things.UpdateProjectOptions, rawInput string) bool {
+	if strings.TrimSpace(rawInput) != "" {
+		return true
+	}
+	if opts.Notes != "" || opts.PrependNotes != "" || opts.AppendNotes != "" {
+		return true
+	}
+	if opts.When != "" || opts.Deadline != "" || opts.Tags != "" || opts.AddTags != "" {
+		return true
+	}
+	if opts.Area != "" || opts.AreaID != "" || len(opts.Todos) > 0 {
+		return true
+	}
+	if opts.Completed || opts.Canceled || opts.Reveal || opts.Duplicate {
+		return true
+	}
+	return opts.CompletionDate != "" || opts.CreationDate != ""
+}
+
+// projectActionItem snapshots a project for the action log along with the
+// state the update is expected to produce.
+func projectActionItem(project db.Task, opts things.UpdateProjectOptions, rawInput string) ActionItem {
+	item := taskToActionItem(project)
+	expected := applyUpdateOptions(planStateFromTask(project), things.UpdateOptions{
+		Notes:        opts.Notes,
+		PrependNotes: opts.PrependNotes,
+		AppendNotes:  opts.AppendNotes,
+		When:         opts.When,
+		Deadline:     opts.Deadline,
+		Tags:         opts.Tags,
+		AddTags:      opts.AddTags,
+		Completed:    opts.Completed,
+		Canceled:     opts.Canceled,
+	}, rawInput)
+	item.Expected = &expected
+	return item
+}
@@ -2,8 +2,11 @@ package cli
 
 import (
 	"bytes"
+	"database/sql"
 	"strings"
 	"testing"
+
+	"github.com/ossianhempel/things3-cli/internal/repeat"
 )
 
 func TestUpdateProjectCommandRequiresAuthToken(t *testing.T) {
@@ -79,3 +82,62 @@ func TestUpdateProjectCommandAddTags(t *testing.T) {
 		t.Fatalf("expected add-tags in url, got %q", url)
 	}
 }
+
+func TestUpdateProjectRepeatRule(t *testing.T) {
+	useTempActionLog(t)
+	dbPath := writeTestDB(t)
+	conn, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	addRepeatColumns(t, conn)
+
+	run := func(args ...string) string {
+		t.Helper()
+		errOut := &bytes.Buffer{}
+		app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: errOut, Launcher: &recordLauncher{}}
+		root := NewRoot(app)
+		root.SetArgs(args)
+		root.SetOut(app.Out)
+		root.SetErr(app.Err)
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return errOut.String()
+	}
+
+	run("update-project", "--db", dbPath, "--id", "P1", "--repeat=month", "--repeat-on=last-day", "--repeat-mode=schedule")
+	var rule []byte
+	if err := conn.QueryRow(`SELECT rt1_recurrenceRule FROM TMTask WHERE uuid = 'P1'`).Scan(&rule); err != nil {
+		t.Fatalf("select rule: %v", err)
+	}
+	spec, err := repeat.DecodeRule(rule)
+	if err != nil {
+		t.Fatalf("decode rule: %v", err)
+	}
+	if got := spec.Describe(); got != "every month on the last day" {
+		t.Fatalf("unexpected rule %q", got)
+	}
+
+	run("undo", "--db", dbPath)
+	rule = nil
+	if err := conn.QueryRow(`SELECT rt1_recurrenceRule FROM TMTask WHERE uuid = 'P1'`).Scan(&rule); err != nil {
+		t.Fatalf("select rule: %v", err)
+	}
+	if len(rule) != 0 {
+		t.Fatalf("expected undo to clear the project rule")
+	}
+
+	if _, err := conn.Exec(`UPDATE TMTask SET rt1_recurrenceRule = X'01' WHERE uuid = 'T1'`); err != nil {
+		t.Fatalf("mark todo repeating: %v", err)
+	}
+	conn.Close()
+	app := &App{In: strings.NewReader(""), Out: &bytes.Buffer{}, Err: &bytes.Buffer{}}
+	root := NewRoot(app)
+	root.SetArgs([]string{"update-project", "--db", dbPath, "--id", "P1", "--repeat=week"})
+	root.SetOut(app.Out)
+	root.SetErr(app.Err)
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "repeating todos (T1)") {
+		t.Fatalf("expected nested repeat error, got %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"github.com/ossianhempel/things3-cli/internal/things"
+	"github.com/spf13/cobra"
+)
+
+// NewUpdateTagCommand builds the update-tag subcommand.
+func NewUpdateTagCommand(app *App) *cobra.Command {
+	opts := things.UpdateTagOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "update-tag [OPTIONS...] [--] [-|TITLE]",
+		Short: "Update an existing tag",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rawInput, err := readInput(app.In, args)
+			if err != nil {
+				return err
+			}
+
+			script, err := things.BuildUpdateTagScript(opts, rawInput)
+			if err != nil {
+				return err
+			}
+			return runScript(app, script)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.ID, "id", "", "ID of the tag to update")
+	flags.StringVar(&opts.Title, "title", "", "New title for the tag")
+	flags.StringVar(&opts.Parent, "parent", "", "Move the tag under this parent tag")
+	flags.BoolVar(&opts.ClearParent, "no-parent", false, "Move the tag to the top level")
+	flags.StringVar(&opts.Shortcut, "shortcut", "", "Keyboard shortcut")
+
+	return cmd
+}
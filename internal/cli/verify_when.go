@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
 )
 
 const whenVerifyTimeout = 4 * time.Second
@@ -84,6 +85,30 @@ func dateString(t time.Time) string {
 	return t.In(time.Local).Format("2006-01-02")
 }
 
+// checkWhenTargets runs the --when checks made before updating tasks: the
+// value must parse, This Evening only takes todos scheduled for today unless
+// allowNonToday is set, and repeating todos cannot be rescheduled.
+func checkWhenTargets(tasks []db.Task, opts things.UpdateOptions, allowNonToday bool) error {
+	if err := validateWhenInput(opts.When); err != nil {
+		return err
+	}
+	when := resolveWhenValue(opts.When, opts.Later)
+	if when == "" {
+		return nil
+	}
+	for _, task := range tasks {
+		if strings.EqualFold(when, "evening") {
+			if err := validateEveningTask(task, allowNonToday); err != nil {
+				return err
+			}
+		}
+		if task.Repeating {
+			return fmt.Errorf("Error: cannot update when for repeating todos (id %s)", task.UUID)
+		}
+	}
+	return nil
+}
+
 func validateEveningTask(task db.Task, allowNonToday bool) error {
 	if allowNonToday {
 		return nil
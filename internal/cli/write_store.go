@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// closeWriteStore closes a store used for direct writes. Writable stores get a
+// PRAGMA integrity_check first; a failure becomes the command's error (or a
+// warning when it already failed) and points at the pre-write backup.
+func closeWriteStore(app *App, store *db.Store, errp *error) {
+	if store == nil {
+		return
+	}
+	var checkErr error
+	if store.Writable() {
+		checkErr = store.IntegrityCheck()
+	}
+	_ = store.Close()
+	if checkErr == nil {
+		return
+	}
+	hint := "things db restore-backup"
+	if backup := store.BackupPath(); backup != "" {
+		hint = fmt.Sprintf("things db restore-backup %s", backup)
+	}
+	if *errp != nil {
+		fmt.Fprintf(app.Err, "Warning: %v after write (roll back with %s)\n", checkErr, hint)
+		return
+	}
+	*errp = fmt.Errorf("Error: %v after write (roll back with %s)", checkErr, hint)
+}
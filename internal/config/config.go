@@ -0,0 +1,242 @@
+// Package config loads things3-cli's config.toml: named profiles holding the
+// database path, auth token source, output defaults, and command aliases.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the parsed config file.
+type Config struct {
+	DefaultProfile string             `toml:"default_profile"`
+	Profiles       map[string]Profile `toml:"profiles"`
+
+	path string
+}
+
+// Defaults are output and query defaults applied to flags the user did not
+// set.
+type Defaults struct {
+	Format string   `toml:"format"`
+	Select []string `toml:"select"`
+	Sort   string   `toml:"sort"`
+	Limit  *int     `toml:"limit"`
+}
+
+// Profile is one named set of settings.
+type Profile struct {
+	Defaults
+
+	DB string `toml:"db"`
+	// Token sources, in priority order: a literal token, an environment
+	// variable name, or a command whose trimmed output is the token.
+	AuthToken        string `toml:"auth_token"`
+	AuthTokenEnv     string `toml:"auth_token_env"`
+	AuthTokenCommand string `toml:"auth_token_command"`
+
+	// Aliases map a name to the command line it expands to.
+	Aliases map[string]string `toml:"aliases"`
+	// Commands overrides Defaults for one command, keyed by its path without
+	// the leading "things" (e.g. "today" or "template export").
+	Commands map[string]Defaults `toml:"commands"`
+}
+
+// Path returns the config file location: THINGS_CONFIG, or config.toml in
+// Dir.
+func Path() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("THINGS_CONFIG")); path != "" {
+		return path, nil
+	}
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Dir returns the things3-cli config directory, which holds the config
+// file, the action log, saved queries, and backups: the directory of
+// THINGS_CONFIG when it is set, otherwise things3-cli in the user config
+// directory.
+func Dir() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("THINGS_CONFIG")); path != "" {
+		return filepath.Dir(path), nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "things3-cli"), nil
+}
+
+// Load reads the config file at path. A missing file yields an empty config;
+// unknown keys are rejected so typos do not pass silently.
+func Load(path string) (*Config, error) {
+	cfg := &Config{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	meta, err := toml.Decode(string(data), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("%s: unknown keys: %s", path, strings.Join(keys, ", "))
+	}
+	return cfg, nil
+}
+
+// Profile returns the named profile, falling back to default_profile when
+// name is empty. It returns nil without error when no profile applies.
+func (c *Config) Profile(name string) (*Profile, string, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil, "", nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, name, fmt.Errorf("profile %q not found in %s", name, c.path)
+	}
+	return &profile, name, nil
+}
+
+// DefaultsFor merges the command's overrides over the profile defaults.
+// Profile-level defaults only apply when taskList is set, since other
+// commands reuse flag names like --format with different meanings.
+func (p *Profile) DefaultsFor(command string, taskList bool) Defaults {
+	var defaults Defaults
+	if taskList {
+		defaults = p.Defaults
+	}
+	override, ok := p.Commands[command]
+	if !ok {
+		return defaults
+	}
+	if override.Format != "" {
+		defaults.Format = override.Format
+	}
+	if len(override.Select) > 0 {
+		defaults.Select = override.Select
+	}
+	if override.Sort != "" {
+		defaults.Sort = override.Sort
+	}
+	if override.Limit != nil {
+		defaults.Limit = override.Limit
+	}
+	return defaults
+}
+
+// Token resolves the profile's auth token and describes where it came from.
+// An empty token means the profile sets no source.
+func (p *Profile) Token() (string, string, error) {
+	if token := strings.TrimSpace(p.AuthToken); token != "" {
+		return token, "auth_token", nil
+	}
+	if name := strings.TrimSpace(p.AuthTokenEnv); name != "" {
+		token := strings.TrimSpace(os.Getenv(name))
+		if token == "" {
+			return "", "", fmt.Errorf("auth_token_env %s is not set", name)
+		}
+		return token, name, nil
+	}
+	if command := strings.TrimSpace(p.AuthTokenCommand); command != "" {
+		out, err := exec.Command("sh", "-c", command).Output()
+		if err != nil {
+			return "", "", fmt.Errorf("auth_token_command failed: %w", err)
+		}
+		token := strings.TrimSpace(string(out))
+		if token == "" {
+			return "", "", fmt.Errorf("auth_token_command printed no token")
+		}
+		return token, "auth_token_command", nil
+	}
+	return "", "", nil
+}
+
+// ExpandAlias replaces a leading alias in args with its expansion. Aliases
+// do not expand recursively.
+func (p *Profile) ExpandAlias(args []string) ([]string, error) {
+	if p == nil || len(args) == 0 {
+		return args, nil
+	}
+	expansion, ok := p.Aliases[args[0]]
+	if !ok {
+		return args, nil
+	}
+	words, err := SplitWords(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("alias %q: %w", args[0], err)
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("alias %q is empty", args[0])
+	}
+	return append(words, args[1:]...), nil
+}
+
+// SplitWords splits a command line on whitespace, honoring single and double
+// quotes and backslash escapes outside single quotes.
+func SplitWords(input string) ([]string, error) {
+	words := []string{}
+	var current strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range input {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash")
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleConfig = `
+default_profile = "work"
+
+[profiles.work]
+db = "~/work.sqlite"
+auth_token_env = "WORK_TOKEN"
+format = "json"
+select = ["title", "project"]
+sort = "-deadline"
+limit = 25
+
+[profiles.work.aliases]
+td = "today --select 'title,deadline'"
+
+[profiles.work.commands.history]
+limit = 5
+
+[profiles.home]
+auth_token_command = "echo home-token"
+`
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestDirFollowsThingsConfig(t *testing.T) {
+	custom := filepath.Join(t.TempDir(), "custom", "things.toml")
+	t.Setenv("THINGS_CONFIG", custom)
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("dir: %v", err)
+	}
+	if dir != filepath.Dir(custom) {
+		t.Fatalf("expected %s, got %s", filepath.Dir(custom), dir)
+	}
+
+	home := t.TempDir()
+	t.Setenv("THINGS_CONFIG", "")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	path, err := Path()
+	if err != nil {
+		t.Fatalf("path: %v", err)
+	}
+	if dir, _ := Dir(); path != filepath.Join(dir, "config.toml") || !strings.Contains(dir, "things3-cli") {
+		t.Fatalf("unexpected default path %s in %s", path, dir)
+	}
+}
+
+func TestLoadProfilesAndDefaults(t *testing.T) {
+	cfg, err := Load(writeConfig(t, sampleConfig))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	profile, name, err := cfg.Profile("")
+	if err != nil || name != "work" {
+		t.Fatalf("expected default profile work, got %q (%v)", name, err)
+	}
+	if profile.DB != "~/work.sqlite" || profile.Format != "json" || profile.Sort != "-deadline" {
+		t.Fatalf("unexpected profile: %+v", profile)
+	}
+
+	list := profile.DefaultsFor("today", true)
+	if list.Format != "json" || !reflect.DeepEqual(list.Select, []string{"title", "project"}) || list.Limit == nil || *list.Limit != 25 {
+		t.Fatalf("unexpected task list defaults: %+v", list)
+	}
+	history := profile.DefaultsFor("history", false)
+	if history.Format != "" || history.Limit == nil || *history.Limit != 5 {
+		t.Fatalf("expected only the history override, got %+v", history)
+	}
+
+	if _, _, err := cfg.Profile("missing"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+		t.Fatalf("expected missing profile error, got %v", err)
+	}
+}
+
+func TestLoadMissingFileAndUnknownKeys(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "absent.toml"))
+	if err != nil {
+		t.Fatalf("load missing: %v", err)
+	}
+	if profile, _, err := cfg.Profile(""); profile != nil || err != nil {
+		t.Fatalf("expected no profile, got %+v (%v)", profile, err)
+	}
+
+	_, err = Load(writeConfig(t, "[profiles.work]\nformats = \"json\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "unknown keys: profiles.work.formats") {
+		t.Fatalf("expected unknown key error, got %v", err)
+	}
+}
+
+func TestProfileToken(t *testing.T) {
+	cfg, err := Load(writeConfig(t, sampleConfig))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	work, _, _ := cfg.Profile("work")
+	t.Setenv("WORK_TOKEN", "")
+	if _, _, err := work.Token(); err == nil {
+		t.Fatalf("expected error for unset auth_token_env")
+	}
+	t.Setenv("WORK_TOKEN", " secret ")
+	if token, source, err := work.Token(); err != nil || token != "secret" || source != "WORK_TOKEN" {
+		t.Fatalf("unexpected env token %q from %q (%v)", token, source, err)
+	}
+	home, _, _ := cfg.Profile("home")
+	if token, source, err := home.Token(); err != nil || token != "home-token" || source != "auth_token_command" {
+		t.Fatalf("unexpected command token %q from %q (%v)", token, source, err)
+	}
+}
+
+func TestExpandAlias(t *testing.T) {
+	profile := &Profile{Aliases: map[string]string{"td": `today --select 'title,deadline' --query "tag:a b"`}}
+	got, err := profile.ExpandAlias([]string{"td", "--limit", "3"})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	want := []string{"today", "--select", "title,deadline", "--query", "tag:a b", "--limit", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, _ := profile.ExpandAlias([]string{"today"}); !reflect.DeepEqual(got, []string{"today"}) {
+		t.Fatalf("expected non-alias untouched, got %q", got)
+	}
+	if _, err := SplitWords(`today "unterminated`); err == nil {
+		t.Fatalf("expected unterminated quote error")
+	}
+}
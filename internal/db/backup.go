@@ -0,0 +1,228 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/config"
+	"modernc.org/sqlite"
+)
+
+// DefaultBackupKeep is how many backups are kept when THINGS_BACKUP_KEEP is
+// not set.
+const DefaultBackupKeep = 10
+
+const (
+	backupPrefix     = "main-"
+	backupSuffix     = ".sqlite"
+	backupTimeLayout = "20060102-150405.000"
+)
+
+// BackupFile describes one snapshot in the backups directory.
+type BackupFile struct {
+	Path    string    `json:"path"`
+	Created time.Time `json:"created"`
+	Size    int64     `json:"size"`
+}
+
+// BackupDir returns the directory that holds pre-write snapshots. It honors
+// THINGS_BACKUP_DIR and otherwise lives next to the action log.
+func BackupDir() (string, error) {
+	if dir := strings.TrimSpace(os.Getenv("THINGS_BACKUP_DIR")); dir != "" {
+		return expandHome(dir), nil
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
+
+// BackupKeep returns how many snapshots to retain. THINGS_BACKUP_KEEP=0
+// disables automatic backups.
+func BackupKeep() (int, error) {
+	raw := strings.TrimSpace(os.Getenv("THINGS_BACKUP_KEEP"))
+	if raw == "" {
+		return DefaultBackupKeep, nil
+	}
+	keep, err := strconv.Atoi(raw)
+	if err != nil || keep < 0 {
+		return 0, fmt.Errorf("invalid THINGS_BACKUP_KEEP %q", raw)
+	}
+	return keep, nil
+}
+
+// Backup writes a consistent snapshot of the database into dir with VACUUM
+// INTO, then deletes all but the newest keep snapshots. keep <= 0 skips
+// rotation.
+func (s *Store) Backup(dir string, keep int) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create backup directory: %w", err)
+	}
+	name := backupPrefix + time.Now().Format(backupTimeLayout) + backupSuffix
+	path := filepath.Join(dir, name)
+	if _, err := s.conn.Exec(`VACUUM INTO ?`, path); err != nil {
+		_ = os.Remove(path)
+		return "", fmt.Errorf("back up database: %w", err)
+	}
+	if keep <= 0 {
+		return path, nil
+	}
+	if err := pruneBackups(dir, keep); err != nil {
+		return path, err
+	}
+	return path, nil
+}
+
+// beforeWrite takes the snapshot promised by OpenDefaultWritable, once.
+// Methods that change the database call it before writing.
+func (s *Store) beforeWrite() error {
+	if s.backupDir == "" || s.backupPath != "" {
+		return nil
+	}
+	path, err := s.Backup(s.backupDir, s.backupKeep)
+	if err != nil {
+		return err
+	}
+	s.backupPath = path
+	return nil
+}
+
+// BackupPath returns the snapshot taken before the store's first write, if
+// any.
+func (s *Store) BackupPath() string {
+	if s == nil {
+		return ""
+	}
+	return s.backupPath
+}
+
+// IntegrityCheck runs PRAGMA integrity_check and reports any problems.
+func (s *Store) IntegrityCheck() error {
+	rows, err := s.conn.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	defer rows.Close()
+
+	problems := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return fmt.Errorf("integrity check: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// RestoreBackup overwrites the database with the snapshot at path using the
+// SQLite online backup API. The snapshot is checked before anything is
+// copied.
+func (s *Store) RestoreBackup(path string) error {
+	backup, err := Open(path)
+	if err != nil {
+		return err
+	}
+	checkErr := backup.IntegrityCheck()
+	_ = backup.Close()
+	if checkErr != nil {
+		return fmt.Errorf("backup %s: %w", path, checkErr)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("resolve backup path: %w", err)
+	}
+	conn, err := s.conn.Conn(context.Background())
+	if err != nil {
+		return fmt.Errorf("restore backup: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		restorer, ok := driverConn.(interface {
+			NewRestore(string) (*sqlite.Backup, error)
+		})
+		if !ok {
+			return fmt.Errorf("restore backup: driver does not support the backup API")
+		}
+		restore, err := restorer.NewRestore(sqliteDSN(abs, "ro"))
+		if err != nil {
+			return fmt.Errorf("restore backup: %w", err)
+		}
+		for {
+			more, err := restore.Step(-1)
+			if err != nil {
+				_ = restore.Finish()
+				return fmt.Errorf("restore backup: %w", err)
+			}
+			if !more {
+				break
+			}
+		}
+		if err := restore.Finish(); err != nil {
+			return fmt.Errorf("restore backup: %w", err)
+		}
+		return nil
+	})
+}
+
+// Backups lists the snapshots in dir, newest first.
+func Backups(dir string) ([]BackupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	backups := []BackupFile{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix)
+		created, err := time.ParseInLocation(backupTimeLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupFile{Path: filepath.Join(dir, name), Created: created, Size: info.Size()})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+	return backups, nil
+}
+
+func pruneBackups(dir string, keep int) error {
+	backups, err := Backups(dir)
+	if err != nil {
+		return err
+	}
+	for i := keep; i < len(backups); i++ {
+		if err := os.Remove(backups[i].Path); err != nil {
+			return fmt.Errorf("rotate backups: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestBackupRotateAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "things.sqlite3")
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE TMTask (uuid TEXT PRIMARY KEY, title TEXT);`); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO TMTask (uuid, title) VALUES ('T1', 'Before');`); err != nil {
+		t.Fatalf("insert task: %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("close db: %v", err)
+	}
+
+	store, err := OpenWritable(path)
+	if err != nil {
+		t.Fatalf("open writable: %v", err)
+	}
+	defer store.Close()
+
+	dir := t.TempDir()
+	var first string
+	for i := 0; i < 3; i++ {
+		backup, err := store.Backup(dir, 2)
+		if err != nil {
+			t.Fatalf("backup: %v", err)
+		}
+		if i == 0 {
+			first = backup
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	backups, err := Backups(dir)
+	if err != nil {
+		t.Fatalf("list backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups after rotation, got %d", len(backups))
+	}
+	for _, backup := range backups {
+		if backup.Path == first {
+			t.Fatalf("expected oldest backup to be rotated out")
+		}
+	}
+	if !backups[0].Created.After(backups[1].Created) {
+		t.Fatalf("expected newest backup first: %+v", backups)
+	}
+
+	if _, err := store.conn.Exec(`UPDATE TMTask SET title = 'After' WHERE uuid = 'T1'`); err != nil {
+		t.Fatalf("update task: %v", err)
+	}
+	if err := store.IntegrityCheck(); err != nil {
+		t.Fatalf("integrity check: %v", err)
+	}
+	if err := store.RestoreBackup(backups[0].Path); err != nil {
+		t.Fatalf("restore backup: %v", err)
+	}
+	var title string
+	if err := store.conn.QueryRow(`SELECT title FROM TMTask WHERE uuid = 'T1'`).Scan(&title); err != nil {
+		t.Fatalf("select restored: %v", err)
+	}
+	if title != "Before" {
+		t.Fatalf("expected restored title, got %q", title)
+	}
+}
+
+func TestOpenDefaultWritableBacksUpBeforeFirstWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "things.sqlite3")
+	conn, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE TMTask (uuid TEXT PRIMARY KEY);`); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	_ = conn.Close()
+
+	dir := t.TempDir()
+	t.Setenv("THINGS_BACKUP_DIR", dir)
+	store, _, err := OpenDefaultWritable(path)
+	if err != nil {
+		t.Fatalf("open writable: %v", err)
+	}
+	if store.BackupPath() != "" {
+		t.Fatalf("expected no backup before the first write, got %q", store.BackupPath())
+	}
+	for i := 0; i < 2; i++ {
+		if err := store.beforeWrite(); err != nil {
+			t.Fatalf("before write: %v", err)
+		}
+	}
+	if store.BackupPath() == "" || filepath.Dir(store.BackupPath()) != dir {
+		t.Fatalf("unexpected backup path %q", store.BackupPath())
+	}
+	if backups, err := Backups(dir); err != nil || len(backups) != 1 {
+		t.Fatalf("expected one backup per store, got %v, %v", backups, err)
+	}
+	_ = store.Close()
+
+	t.Setenv("THINGS_BACKUP_KEEP", "0")
+	store, _, err = OpenDefaultWritable(path)
+	if err != nil {
+		t.Fatalf("open writable: %v", err)
+	}
+	defer store.Close()
+	if err := store.beforeWrite(); err != nil {
+		t.Fatalf("before write: %v", err)
+	}
+	if store.BackupPath() != "" {
+		t.Fatalf("expected no backup with THINGS_BACKUP_KEEP=0")
+	}
+}
@@ -52,3 +52,12 @@ func loadChecklistItems(conn *sql.DB, taskIDs []string) (map[string][]ChecklistI
 	}
 	return items, rows.Err()
 }
+
+// ChecklistItems returns the checklist items for a task in display order.
+func (s *Store) ChecklistItems(taskID string) ([]ChecklistItem, error) {
+	items, err := loadChecklistItems(s.conn, []string{taskID})
+	if err != nil {
+		return nil, err
+	}
+	return items[taskID], nil
+}
@@ -5,17 +5,28 @@ import (
 	"time"
 )
 
-func formatThingsDate(value int64) string {
+// ThingsDate decodes a packed Things date (year<<16 | month<<12 | day<<7),
+// as stored in startDate, deadline, and the rt1_ date columns, into local
+// midnight. It reports false for zero and malformed values.
+func ThingsDate(value int64) (time.Time, bool) {
 	if value <= 0 {
-		return ""
+		return time.Time{}, false
 	}
 	year := int(value >> 16)
 	month := int((value >> 12) & 0x0f)
 	day := int((value >> 7) & 0x1f)
-	if year <= 0 || month <= 0 || day <= 0 {
+	if year <= 0 || month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local), true
+}
+
+func formatThingsDate(value int64) string {
+	date, ok := ThingsDate(value)
+	if !ok {
 		return ""
 	}
-	return fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	return fmt.Sprintf("%04d-%02d-%02d", date.Year(), int(date.Month()), date.Day())
 }
 
 func formatTimestamp(value float64) string {
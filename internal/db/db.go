@@ -11,8 +11,15 @@ import (
 
 // Store wraps a Things database connection.
 type Store struct {
-	conn *sql.DB
-	path string
+	conn       *sql.DB
+	path       string
+	writable   bool
+	backupDir  string
+	backupKeep int
+	backupPath string
+	schema     *Schema
+	explain    bool
+	explained  []TaskQuery
 }
 
 // Open opens a Things database at the provided path in read-only mode.
@@ -33,7 +40,12 @@ func Open(path string) (*Store, error) {
 		_ = conn.Close()
 		return nil, fmt.Errorf("open database: %w", err)
 	}
-	return &Store{conn: conn, path: abs}, nil
+	schema, err := detectSchema(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return &Store{conn: conn, path: abs, schema: schema}, nil
 }
 
 // OpenDefault resolves the Things database path and opens it.
@@ -83,22 +95,48 @@ func OpenWritable(path string) (*Store, error) {
 		_ = conn.Close()
 		return nil, fmt.Errorf("open database: %w", err)
 	}
-	return &Store{conn: conn, path: abs}, nil
+	schema, err := detectSchema(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return &Store{conn: conn, path: abs, writable: true, schema: schema}, nil
 }
 
-// OpenDefaultWritable resolves the Things database path and opens it in read-write mode.
+// OpenDefaultWritable resolves the Things database path and opens it in
+// read-write mode. Unless THINGS_BACKUP_KEEP is 0, a snapshot is written to
+// BackupDir before the first write through the store; the write fails if
+// that does.
 func OpenDefaultWritable(override string) (*Store, string, error) {
 	path, err := ResolveDatabasePath(override)
 	if err != nil {
 		return nil, "", err
 	}
+	keep, err := BackupKeep()
+	if err != nil {
+		return nil, path, err
+	}
+	var dir string
+	if keep != 0 {
+		dir, err = BackupDir()
+		if err != nil {
+			return nil, path, fmt.Errorf("back up database: %w", err)
+		}
+	}
 	store, err := OpenWritable(path)
 	if err != nil {
 		return nil, path, err
 	}
+	store.backupDir = dir
+	store.backupKeep = keep
 	return store, path, nil
 }
 
+// Writable reports whether the store was opened in read-write mode.
+func (s *Store) Writable() bool {
+	return s != nil && s.writable
+}
+
 func sqliteDSN(path string, mode string) string {
 	p := filepath.ToSlash(path)
 	if len(p) > 0 && p[0] != '/' {
@@ -0,0 +1,202 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxAmbiguousCandidates caps how many candidates an AmbiguousError lists.
+const maxAmbiguousCandidates = 10
+
+// Candidate is an item a title could refer to.
+type Candidate struct {
+	UUID    string `json:"uuid"`
+	Title   string `json:"title"`
+	Type    string `json:"type"`
+	Context string `json:"context,omitempty"`
+}
+
+// AmbiguousError reports a title that matched more than one item equally
+// well. Candidates are ordered best first.
+type AmbiguousError struct {
+	Kind       string
+	Input      string
+	Candidates []Candidate
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%q matches %d %s; use a longer title or the UUID:", e.Input, len(e.Candidates), e.Kind)
+	for _, candidate := range e.Candidates {
+		b.WriteString("\n  " + candidate.Label() + "  " + candidate.UUID)
+	}
+	return b.String()
+}
+
+// Label describes the candidate for pickers and error messages.
+func (c Candidate) Label() string {
+	label := c.Title
+	if c.Context != "" {
+		label += " (" + c.Context + ")"
+	}
+	if c.Type != "" {
+		label += " [" + c.Type + "]"
+	}
+	return label
+}
+
+// Fuzzy match tiers, best first. A prefix match beats one where every word
+// of the input starts a word of the title, which beats a near miss by edit
+// distance.
+const (
+	matchNone = iota
+	matchEdit
+	matchToken
+	matchPrefix
+	matchExact
+)
+
+type rankedCandidate struct {
+	Candidate
+	tier     int
+	distance int
+}
+
+// rankCandidates returns the candidates matching input, best first.
+func rankCandidates(input string, candidates []Candidate) []rankedCandidate {
+	query := strings.ToLower(strings.TrimSpace(input))
+	ranked := []rankedCandidate{}
+	if query == "" {
+		return ranked
+	}
+	for _, candidate := range candidates {
+		tier, distance := fuzzyMatch(query, strings.ToLower(strings.TrimSpace(candidate.Title)))
+		if tier == matchNone {
+			continue
+		}
+		ranked = append(ranked, rankedCandidate{Candidate: candidate, tier: tier, distance: distance})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].tier != ranked[j].tier {
+			return ranked[i].tier > ranked[j].tier
+		}
+		if ranked[i].distance != ranked[j].distance {
+			return ranked[i].distance < ranked[j].distance
+		}
+		return strings.ToLower(ranked[i].Title) < strings.ToLower(ranked[j].Title)
+	})
+	return ranked
+}
+
+func fuzzyMatch(query string, title string) (int, int) {
+	distance := editDistance(query, title)
+	switch {
+	case query == title:
+		return matchExact, 0
+	case strings.HasPrefix(title, query):
+		return matchPrefix, distance
+	case tokensMatch(query, title):
+		return matchToken, distance
+	}
+	// Allow about one typo per four characters, against the whole title or
+	// any single word of it.
+	limit := len([]rune(query)) / 4
+	if limit < 1 {
+		limit = 1
+	}
+	if len([]rune(query)) < 3 {
+		return matchNone, distance
+	}
+	if distance <= limit {
+		return matchEdit, distance
+	}
+	for _, word := range titleWords(title) {
+		if editDistance(query, word) <= limit {
+			return matchEdit, distance
+		}
+	}
+	return matchNone, distance
+}
+
+// tokensMatch reports whether every word of query starts some word of title.
+func tokensMatch(query string, title string) bool {
+	words := titleWords(title)
+	for _, token := range titleWords(query) {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word, token) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+func titleWords(title string) []string {
+	return strings.FieldsFunc(title, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// editDistance is the Levenshtein distance between a and b in runes.
+func editDistance(a string, b string) int {
+	left, right := []rune(a), []rune(b)
+	previous := make([]int, len(right)+1)
+	current := make([]int, len(right)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(left); i++ {
+		current[0] = i
+		for j := 1; j <= len(right); j++ {
+			cost := 1
+			if left[i-1] == right[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(right)]
+}
+
+// resolveCandidate picks the item input names: the only exact title match,
+// or else the only best fuzzy match among candidates. Anything less
+// clear-cut is an *AmbiguousError; no match at all returns an empty ID.
+func resolveCandidate(kind string, input string, exact []Candidate, candidates []Candidate) (string, error) {
+	switch {
+	case len(exact) == 1:
+		return exact[0].UUID, nil
+	case len(exact) > 1:
+		return "", ambiguous(kind, input, exact)
+	}
+	ranked := rankCandidates(input, candidates)
+	if len(ranked) == 0 {
+		return "", nil
+	}
+	best := 1
+	for best < len(ranked) && ranked[best].tier == ranked[0].tier {
+		best++
+	}
+	if best == 1 {
+		return ranked[0].UUID, nil
+	}
+	list := make([]Candidate, len(ranked))
+	for i, candidate := range ranked {
+		list[i] = candidate.Candidate
+	}
+	return "", ambiguous(kind, input, list)
+}
+
+func ambiguous(kind string, input string, candidates []Candidate) *AmbiguousError {
+	if len(candidates) > maxAmbiguousCandidates {
+		candidates = candidates[:maxAmbiguousCandidates]
+	}
+	return &AmbiguousError{Kind: kind, Input: strings.TrimSpace(input), Candidates: candidates}
+}
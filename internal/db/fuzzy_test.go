@@ -0,0 +1,94 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRankCandidatesOrdersByTier(t *testing.T) {
+	candidates := []Candidate{
+		{UUID: "E", Title: "Budgat"},
+		{UUID: "T", Title: "Q3 Budget Review"},
+		{UUID: "P", Title: "Budget"},
+		{UUID: "N", Title: "Groceries"},
+	}
+	ranked := rankCandidates("budget", candidates)
+	got := []string{}
+	for _, candidate := range ranked {
+		got = append(got, candidate.UUID)
+	}
+	if strings.Join(got, ",") != "P,T,E" {
+		t.Fatalf("unexpected ranking: %v", got)
+	}
+
+	if got := editDistance("kitten", "sitting"); got != 3 {
+		t.Fatalf("unexpected edit distance: %d", got)
+	}
+	if ranked := rankCandidates("ab", []Candidate{{Title: "xy"}}); len(ranked) != 0 {
+		t.Fatalf("short inputs should not match by edit distance: %#v", ranked)
+	}
+}
+
+func TestMatchProjectIDFuzzy(t *testing.T) {
+	store := openSeededStore(t)
+
+	cases := map[string]string{
+		"proj":         "P1",
+		"one":          "P1",
+		"Projct One":   "P1",
+		"project done": "P2",
+		"P2":           "P2",
+	}
+	for input, want := range cases {
+		got, err := store.MatchProjectID(input)
+		if err != nil || got != want {
+			t.Fatalf("match %q: got %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := store.MatchProjectID("groceries"); err == nil || err.Error() != "project not found: groceries" {
+		t.Fatalf("expected not found, got %v", err)
+	}
+
+	// The exact resolver used by writes never falls back to fuzzy matches.
+	if got, err := store.ResolveProjectID("project one"); err != nil || got != "P1" {
+		t.Fatalf("resolve exact title: got %q, %v", got, err)
+	}
+	if _, err := store.ResolveProjectID("Projct One"); err == nil || err.Error() != "project not found: Projct One" {
+		t.Fatalf("expected the exact resolver to reject a typo, got %v", err)
+	}
+
+	if _, err := store.conn.Exec(`INSERT INTO TMTask (uuid, type, status, trashed, title) VALUES ('P3', ?, ?, 0, 'Project Two')`, TaskTypeProject, StatusIncomplete); err != nil {
+		t.Fatalf("insert project: %v", err)
+	}
+	_, err := store.MatchProjectID("proj")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 {
+		t.Fatalf("expected two candidates, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "Project One (Home)  P1") || !strings.Contains(err.Error(), "Project Two  P3") {
+		t.Fatalf("expected candidates with UUIDs, got %q", err.Error())
+	}
+}
+
+func TestResolveItemFuzzy(t *testing.T) {
+	store := openSeededStore(t)
+
+	item, err := store.ResolveItem("task on")
+	if err != nil || item.UUID != "T1" {
+		t.Fatalf("unexpected item: %#v, %v", item, err)
+	}
+	if _, err := store.ResolveItem("zzzz"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected no rows, got %v", err)
+	}
+	if _, err := store.conn.Exec(`INSERT INTO TMTask (uuid, type, status, trashed, title) VALUES ('T3', ?, ?, 0, 'Task Two')`, TaskTypeTodo, StatusIncomplete); err != nil {
+		t.Fatalf("insert todo: %v", err)
+	}
+	_, err = store.ResolveItem("task")
+	var ambiguous *AmbiguousError
+	if !errors.As(err, &ambiguous) || ambiguous.Kind != "items" {
+		t.Fatalf("expected ambiguity between todos, got %v", err)
+	}
+}
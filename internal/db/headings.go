@@ -0,0 +1,120 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Heading is a heading inside a project.
+type Heading struct {
+	UUID         string `json:"uuid"`
+	Title        string `json:"title"`
+	ProjectID    string `json:"project_id"`
+	ProjectTitle string `json:"project_title,omitempty"`
+	Archived     bool   `json:"archived,omitempty"`
+	Index        int    `json:"index"`
+	OpenTasks    int    `json:"open_tasks"`
+}
+
+// Headings returns the headings of a project (or of all projects when
+// projectID is empty) in display order. Archived headings are skipped unless
+// includeArchived is set.
+func (s *Store) Headings(projectID string, includeArchived bool) ([]Heading, error) {
+	if s == nil || s.conn == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	var b strings.Builder
+	b.WriteString(`SELECT h.uuid, h.title, h.project, p.title, h.status, h."index",
+		(SELECT COUNT(*) FROM TMTask t WHERE t.heading = h.uuid AND t.trashed = 0 AND t.status = ?)
+		FROM TMTask h
+		LEFT JOIN TMTask p ON h.project = p.uuid
+		WHERE h.type = ? AND h.trashed = 0`)
+	args := []any{StatusIncomplete, TaskTypeHeading}
+	if projectID != "" {
+		b.WriteString(" AND h.project = ?")
+		args = append(args, projectID)
+	}
+	if !includeArchived {
+		b.WriteString(" AND h.status = ?")
+		args = append(args, StatusIncomplete)
+	}
+	b.WriteString(` ORDER BY p.title COLLATE NOCASE, h."index"`)
+
+	rows, err := s.conn.Query(b.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	headings := []Heading{}
+	for rows.Next() {
+		var h Heading
+		var project sql.NullString
+		var projectTitle sql.NullString
+		var status int
+		var index sql.NullInt64
+		if err := rows.Scan(&h.UUID, &h.Title, &project, &projectTitle, &status, &index, &h.OpenTasks); err != nil {
+			return nil, err
+		}
+		h.ProjectID = project.String
+		h.ProjectTitle = projectTitle.String
+		h.Archived = status != StatusIncomplete
+		h.Index = int(index.Int64)
+		headings = append(headings, h)
+	}
+	return headings, rows.Err()
+}
+
+// ResolveHeadingID resolves a heading by UUID or title within a project.
+func (s *Store) ResolveHeadingID(projectID string, input string) (string, error) {
+	return resolveHeadingID(s.conn, projectID, input)
+}
+
+func resolveHeadingID(conn *sql.DB, projectID string, input string) (string, error) {
+	if input == "" {
+		return "", nil
+	}
+	var id string
+	if err := conn.QueryRow("SELECT uuid FROM TMTask WHERE type = ? AND uuid = ? AND trashed = 0", TaskTypeHeading, input).Scan(&id); err == nil {
+		return id, nil
+	} else if err != sql.ErrNoRows {
+		return "", err
+	}
+	if projectID == "" {
+		return "", fmt.Errorf("heading not found: %s (specify a project to match by title)", input)
+	}
+	rows, err := conn.Query("SELECT uuid FROM TMTask WHERE type = ? AND project = ? AND trashed = 0 AND lower(title) = lower(?)", TaskTypeHeading, projectID, input)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	ids := []string{}
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			return "", err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("heading not found: %s", input)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf("multiple headings titled %q; use the heading ID", input)
+	}
+}
+
+// HeadingProjectID returns the project a heading belongs to.
+func (s *Store) HeadingProjectID(headingID string) (string, error) {
+	var project sql.NullString
+	err := s.conn.QueryRow("SELECT project FROM TMTask WHERE type = ? AND uuid = ?", TaskTypeHeading, headingID).Scan(&project)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("heading not found: %s", headingID)
+	}
+	return project.String, err
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestHeadingsListAndResolve(t *testing.T) {
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+
+	if err := seedTestDB(conn); err != nil {
+		t.Fatalf("seed db: %v", err)
+	}
+
+	store := &Store{conn: conn, path: ":memory:"}
+
+	headings, err := store.Headings("P1", false)
+	if err != nil {
+		t.Fatalf("headings: %v", err)
+	}
+	if len(headings) != 1 || headings[0].UUID != "H1" || headings[0].ProjectTitle != "Project One" || headings[0].OpenTasks == 0 {
+		t.Fatalf("unexpected headings: %#v", headings)
+	}
+
+	if _, err := conn.Exec(`INSERT INTO TMTask (uuid, type, status, trashed, title, project, "index") VALUES ('H2', ?, ?, 0, 'Later', 'P1', 5)`, TaskTypeHeading, StatusCompleted); err != nil {
+		t.Fatalf("insert heading: %v", err)
+	}
+	resolved, err := store.ResolveHeadingID("P1", "later")
+	if err != nil || resolved != "H2" {
+		t.Fatalf("resolve heading: %q %v", resolved, err)
+	}
+	if project, err := store.HeadingProjectID("H2"); err != nil || project != "P1" {
+		t.Fatalf("heading project: %q %v", project, err)
+	}
+	if _, err := store.ResolveHeadingID("", "later"); err == nil {
+		t.Fatalf("expected title lookup without project to fail")
+	}
+
+	headings, err = store.Headings("P1", false)
+	if err != nil {
+		t.Fatalf("headings: %v", err)
+	}
+	if len(headings) != 1 {
+		t.Fatalf("expected archived heading to be hidden, got %#v", headings)
+	}
+	headings, err = store.Headings("P1", true)
+	if err != nil {
+		t.Fatalf("headings: %v", err)
+	}
+	if len(headings) != 2 || !headings[1].Archived || headings[1].Title != "Later" {
+		t.Fatalf("expected archived heading listed last, got %#v", headings)
+	}
+}
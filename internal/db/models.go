@@ -45,6 +45,8 @@ type Task struct {
 	Start        string          `json:"start,omitempty"`
 	StartDate    string          `json:"start_date,omitempty"`
 	Repeating    bool            `json:"repeating,omitempty"`
+	RepeatRule   string          `json:"repeat_rule,omitempty"`
+	Occurrences  []Occurrence    `json:"occurrences,omitempty"`
 	Deadline     string          `json:"deadline,omitempty"`
 	StopDate     string          `json:"stop_date,omitempty"`
 	Created      string          `json:"created,omitempty"`
@@ -59,6 +61,13 @@ type Task struct {
 	AreaTitle    string          `json:"area_title,omitempty"`
 	HeadingID    string          `json:"heading_id,omitempty"`
 	HeadingTitle string          `json:"heading_title,omitempty"`
+	// HeadingProjectID and HeadingProjectTitle name the project of the
+	// task's heading; Things leaves project unset on todos under a heading.
+	HeadingProjectID    string `json:"heading_project_id,omitempty"`
+	HeadingProjectTitle string `json:"heading_project_title,omitempty"`
+	// Score and Snippet are set by ranked full-text search only.
+	Score   float64 `json:"score,omitempty"`
+	Snippet string  `json:"snippet,omitempty"`
 }
 
 type ChecklistItem struct {
@@ -72,26 +81,38 @@ type ChecklistItem struct {
 }
 
 type Item struct {
-	UUID         string `json:"uuid"`
-	Type         string `json:"type"`
-	Title        string `json:"title"`
-	Status       *int   `json:"status,omitempty"`
-	Trashed      *bool  `json:"trashed,omitempty"`
-	ProjectTitle string `json:"project_title,omitempty"`
-	AreaTitle    string `json:"area_title,omitempty"`
-	HeadingTitle string `json:"heading_title,omitempty"`
-	Visible      *bool  `json:"visible,omitempty"`
-	Shortcut     string `json:"shortcut,omitempty"`
-	ParentID     string `json:"parent_id,omitempty"`
+	UUID         string       `json:"uuid"`
+	Type         string       `json:"type"`
+	Title        string       `json:"title"`
+	Status       *int         `json:"status,omitempty"`
+	Trashed      *bool        `json:"trashed,omitempty"`
+	ProjectTitle string       `json:"project_title,omitempty"`
+	AreaTitle    string       `json:"area_title,omitempty"`
+	HeadingTitle string       `json:"heading_title,omitempty"`
+	Visible      *bool        `json:"visible,omitempty"`
+	Shortcut     string       `json:"shortcut,omitempty"`
+	ParentID     string       `json:"parent_id,omitempty"`
+	Occurrences  []Occurrence `json:"occurrences,omitempty"`
+}
+
+// Occurrence is a projected instance of a repeating template (YYYY-MM-DD).
+type Occurrence struct {
+	Start    string `json:"start"`
+	Deadline string `json:"deadline,omitempty"`
 }
 
 type TreeItem struct {
-	UUID    string     `json:"uuid"`
-	Type    string     `json:"type"`
-	Title   string     `json:"title"`
-	Status  *int       `json:"status,omitempty"`
-	Trashed *bool      `json:"trashed,omitempty"`
-	Items   []TreeItem `json:"items,omitempty"`
+	UUID      string          `json:"uuid"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Status    *int            `json:"status,omitempty"`
+	Trashed   *bool           `json:"trashed,omitempty"`
+	StartDate string          `json:"start_date,omitempty"`
+	Deadline  string          `json:"deadline,omitempty"`
+	StopDate  string          `json:"stop_date,omitempty"`
+	Tags      []string        `json:"tags,omitempty"`
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	Items     []TreeItem      `json:"items,omitempty"`
 }
 
 type ProjectFilter struct {
@@ -107,6 +128,7 @@ type TaskFilter struct {
 	ProjectID             string
 	AreaID                string
 	TagID                 string
+	TagRecursive          bool
 	Search                string
 	Limit                 int
 	Offset                int
@@ -122,6 +144,17 @@ type TaskFilter struct {
 	Order                 string
 	IncludeRepeating      bool
 	RepeatingOnly         bool
+	// Where is an extra condition ANDed into the query, with WhereArgs bound
+	// to its placeholders. SECURITY: like Order, it must be built from fixed
+	// SQL fragments; user values go in WhereArgs only.
+	Where     string
+	WhereArgs []any
+}
+
+// TaskQuery is a task listing statement recorded by ExplainTaskQueries.
+type TaskQuery struct {
+	SQL  string
+	Args []any
 }
 
 func StatusLabel(status int) string {
@@ -11,36 +11,51 @@ import (
 
 var ErrDatabaseNotFound = errors.New("things database not found")
 
+// Database path sources reported by ResolveDatabasePathSource.
+const (
+	PathSourceFlag   = "--db"
+	PathSourceEnv    = "THINGSDB"
+	PathSourceGlob   = "ThingsData-*"
+	PathSourceLegacy = "legacy"
+)
+
 // ResolveDatabasePath finds the Things database path.
 //
 // Priority: override arg, THINGSDB env, default ThingsData-* locations, legacy path.
 func ResolveDatabasePath(override string) (string, error) {
+	path, _, err := ResolveDatabasePathSource(override)
+	return path, err
+}
+
+// ResolveDatabasePathSource finds the Things database path and reports which
+// of the PathSource* rules produced it.
+func ResolveDatabasePathSource(override string) (string, string, error) {
 	if override != "" {
-		return expandHome(override), nil
+		return expandHome(override), PathSourceFlag, nil
 	}
 	if env := strings.TrimSpace(os.Getenv("THINGSDB")); env != "" {
-		return expandHome(env), nil
+		return expandHome(env), PathSourceEnv, nil
 	}
 
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return "", fmt.Errorf("resolve home directory: %w", err)
+		return "", "", fmt.Errorf("resolve home directory: %w", err)
 	}
 
 	pattern := filepath.Join(home, "Library/Group Containers/JLMPQHK86H.com.culturedcode.ThingsMac", "ThingsData-*", "Things Database.thingsdatabase", "main.sqlite")
 	matches, _ := filepath.Glob(pattern)
 	if len(matches) > 0 {
 		if path := newestFile(matches); path != "" {
-			return path, nil
+			return path, PathSourceGlob, nil
 		}
 	}
 
 	legacy := filepath.Join(home, "Library/Group Containers/JLMPQHK86H.com.culturedcode.ThingsMac", "Things Database.thingsdatabase", "main.sqlite")
 	if fileExists(legacy) {
-		return legacy, nil
+		return legacy, PathSourceLegacy, nil
 	}
 
-	return "", ErrDatabaseNotFound
+	return "", "", ErrDatabaseNotFound
 }
 
 func expandHome(path string) string {
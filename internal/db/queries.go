@@ -15,6 +15,10 @@ func (s *Store) Projects(filter ProjectFilter) ([]Project, error) {
 	if s == nil || s.conn == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
+	schema, err := s.requireColumns((*Schema).taskQueryColumns)
+	if err != nil {
+		return nil, err
+	}
 	var b strings.Builder
 	b.WriteString("SELECT t.uuid, t.title, t.status, t.trashed, t.area, a.title ")
 	b.WriteString("FROM TMTask t ")
@@ -33,7 +37,7 @@ func (s *Store) Projects(filter ProjectFilter) ([]Project, error) {
 		b.WriteString(" AND t.status = ?")
 		args = append(args, *filter.Status)
 	}
-	b.WriteString(" AND t.rt1_recurrenceRule IS NULL")
+	b.WriteString(" AND t." + schema.RepeatColumn("recurrenceRule") + " IS NULL")
 	b.WriteString(" ORDER BY t.\"index\"")
 
 	rows, err := s.conn.Query(b.String(), args...)
@@ -66,6 +70,10 @@ func (s *Store) ProjectsWithoutArea(filter ProjectFilter) ([]Project, error) {
 	if s == nil || s.conn == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
+	schema, err := s.requireColumns((*Schema).taskQueryColumns)
+	if err != nil {
+		return nil, err
+	}
 	var b strings.Builder
 	b.WriteString("SELECT t.uuid, t.title, t.status, t.trashed, t.area, a.title ")
 	b.WriteString("FROM TMTask t ")
@@ -80,7 +88,7 @@ func (s *Store) ProjectsWithoutArea(filter ProjectFilter) ([]Project, error) {
 		b.WriteString(" AND t.status = ?")
 		args = append(args, *filter.Status)
 	}
-	b.WriteString(" AND t.rt1_recurrenceRule IS NULL")
+	b.WriteString(" AND t." + schema.RepeatColumn("recurrenceRule") + " IS NULL")
 	b.WriteString(" ORDER BY t.\"index\"")
 
 	rows, err := s.conn.Query(b.String(), args...)
@@ -196,16 +204,25 @@ func (s *Store) TaskByID(id string) (*Task, error) {
 
 // TodayTasks returns tasks that belong in Today according to Things rules.
 func (s *Store) TodayTasks(filter TaskFilter) ([]Task, error) {
+	schema, err := s.Schema()
+	if err != nil {
+		return nil, err
+	}
 	todayExpr := thingsDateTodayExpr()
-	regular, err := s.queryTasks("t.start = 1 AND t.startDate IS NOT NULL", nil, filter, "t.todayIndex")
+	order := schema.todayOrder()
+	regular, err := s.queryTasks("t.start = 1 AND t.startDate IS NOT NULL", nil, filter, order)
 	if err != nil {
 		return nil, err
 	}
-	unconfirmedScheduled, err := s.queryTasks("t.start = 2 AND t.startDate IS NOT NULL AND t.startDate <= "+todayExpr, nil, filter, "t.todayIndex")
+	unconfirmedScheduled, err := s.queryTasks("t.start = 2 AND t.startDate IS NOT NULL AND t.startDate <= "+todayExpr, nil, filter, order)
 	if err != nil {
 		return nil, err
 	}
-	unconfirmedOverdue, err := s.queryTasks("t.startDate IS NULL AND t.deadline IS NOT NULL AND t.deadline <= "+todayExpr+" AND t.deadlineSuppressionDate IS NULL", nil, filter, "t.todayIndex")
+	overdue := "t.startDate IS NULL AND t.deadline IS NOT NULL AND t.deadline <= " + todayExpr
+	if schema.HasDeadlineSuppression {
+		overdue += " AND t.deadlineSuppressionDate IS NULL"
+	}
+	unconfirmedOverdue, err := s.queryTasks(overdue, nil, filter, order)
 	if err != nil {
 		return nil, err
 	}
@@ -488,6 +505,90 @@ func (s *Store) ItemsByTitle(title string) ([]Item, error) {
 	return items, nil
 }
 
+// ResolveItem finds the item (area, project, heading, tag, or todo) that
+// input names. Without an exact title match it falls back to fuzzy matching
+// over open items. A title that fits several items returns an
+// *AmbiguousError, and no match returns sql.ErrNoRows.
+func (s *Store) ResolveItem(input string) (*Item, error) {
+	items, err := s.ItemsByTitle(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 1 {
+		return &items[0], nil
+	}
+	exact := make([]Candidate, 0, len(items))
+	for _, item := range items {
+		context := item.ProjectTitle
+		if context == "" {
+			context = item.AreaTitle
+		}
+		exact = append(exact, Candidate{UUID: item.UUID, Title: item.Title, Type: item.Type, Context: context})
+	}
+	var candidates []Candidate
+	if len(items) == 0 {
+		if candidates, err = s.itemCandidates(); err != nil {
+			return nil, err
+		}
+	}
+	id, err := resolveCandidate("items", input, exact, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return nil, sql.ErrNoRows
+	}
+	return s.ItemByID(id)
+}
+
+// itemCandidates lists open, untrashed tasks along with all areas and tags.
+func (s *Store) itemCandidates() ([]Candidate, error) {
+	rows, err := s.conn.Query(
+		`SELECT t.uuid, t.title, t.type, IFNULL(p.title, IFNULL(a.title, ''))
+		 FROM TMTask t
+		 LEFT JOIN TMTask p ON t.project = p.uuid
+		 LEFT JOIN TMArea a ON t.area = a.uuid
+		 WHERE t.trashed = 0 AND t.status = ?`,
+		StatusIncomplete,
+	)
+	if err != nil {
+		return nil, err
+	}
+	candidates := []Candidate{}
+	for rows.Next() {
+		var candidate Candidate
+		var title sql.NullString
+		var taskType int
+		if err := rows.Scan(&candidate.UUID, &title, &taskType, &candidate.Context); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		candidate.Title = title.String
+		candidate.Type = taskTypeLabel(taskType)
+		candidates = append(candidates, candidate)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	for _, source := range []struct{ query, kind string }{
+		{`SELECT uuid, title, '' FROM TMArea`, "area"},
+		{`SELECT uuid, title, '' FROM TMTag`, "tag"},
+	} {
+		more, err := queryCandidates(s.conn, source.query)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range more {
+			candidate.Type = source.kind
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates, nil
+}
+
 func taskTypeLabel(taskType int) string {
 	switch taskType {
 	case TaskTypeTodo:
@@ -501,22 +602,45 @@ func taskTypeLabel(taskType int) string {
 	}
 }
 
-// ResolveAreaID resolves an area by UUID or title.
+// ResolveAreaID resolves an area by UUID or exact title (case-insensitive).
+// Several areas with the title return an *AmbiguousError.
 func (s *Store) ResolveAreaID(input string) (string, error) {
-	return resolveAreaID(s.conn, input)
+	return resolveAreaID(s.conn, input, false)
 }
 
-// ResolveProjectID resolves a project by UUID or title.
+// ResolveProjectID resolves a project by UUID or exact title
+// (case-insensitive). Several projects with the title return an
+// *AmbiguousError.
 func (s *Store) ResolveProjectID(input string) (string, error) {
-	return resolveProjectID(s.conn, input)
+	return resolveProjectID(s.conn, input, false)
 }
 
-// ResolveTagID resolves a tag by UUID or title.
+// ResolveTagID resolves a tag by UUID or exact title (case-insensitive).
+// Several tags with the title return an *AmbiguousError.
 func (s *Store) ResolveTagID(input string) (string, error) {
-	return resolveTagID(s.conn, input)
+	return resolveTagID(s.conn, input, false)
+}
+
+// MatchAreaID is ResolveAreaID with a fuzzy title match as the fallback.
+// It is meant for read filters; commands that change data use the exact
+// resolvers so a typo cannot pick the wrong target.
+func (s *Store) MatchAreaID(input string) (string, error) {
+	return resolveAreaID(s.conn, input, true)
+}
+
+// MatchProjectID is ResolveProjectID with a fuzzy title match against open
+// projects as the fallback. It is meant for read filters.
+func (s *Store) MatchProjectID(input string) (string, error) {
+	return resolveProjectID(s.conn, input, true)
+}
+
+// MatchTagID is ResolveTagID with a fuzzy title match as the fallback. It
+// is meant for read filters.
+func (s *Store) MatchTagID(input string) (string, error) {
+	return resolveTagID(s.conn, input, true)
 }
 
-func resolveAreaID(conn *sql.DB, input string) (string, error) {
+func resolveAreaID(conn *sql.DB, input string, fuzzy bool) (string, error) {
 	if input == "" {
 		return "", nil
 	}
@@ -526,15 +650,11 @@ func resolveAreaID(conn *sql.DB, input string) (string, error) {
 	} else if err != sql.ErrNoRows {
 		return "", err
 	}
-	if err := conn.QueryRow("SELECT uuid FROM TMArea WHERE lower(title) = lower(?)", input).Scan(&id); err == nil {
-		return id, nil
-	} else if err != sql.ErrNoRows {
-		return "", err
-	}
-	return "", fmt.Errorf("area not found: %s", input)
+	const query = `SELECT t.uuid, t.title, '' FROM TMArea t WHERE %s ORDER BY t."index"`
+	return resolveTitle(conn, "areas", input, query, "1", nil, fuzzy, "area not found: %s")
 }
 
-func resolveProjectID(conn *sql.DB, input string) (string, error) {
+func resolveProjectID(conn *sql.DB, input string, fuzzy bool) (string, error) {
 	if input == "" {
 		return "", nil
 	}
@@ -544,15 +664,14 @@ func resolveProjectID(conn *sql.DB, input string) (string, error) {
 	} else if err != sql.ErrNoRows {
 		return "", err
 	}
-	if err := conn.QueryRow("SELECT uuid FROM TMTask WHERE type = ? AND lower(title) = lower(?)", TaskTypeProject, input).Scan(&id); err == nil {
-		return id, nil
-	} else if err != sql.ErrNoRows {
-		return "", err
-	}
-	return "", fmt.Errorf("project not found: %s", input)
+	const query = `SELECT t.uuid, t.title, IFNULL(a.title, '') FROM TMTask t
+		LEFT JOIN TMArea a ON t.area = a.uuid
+		WHERE t.type = ? AND %s ORDER BY t."index"`
+	active := fmt.Sprintf("t.trashed = 0 AND t.status = %d", StatusIncomplete)
+	return resolveTitle(conn, "projects", input, query, active, []any{TaskTypeProject}, fuzzy, "project not found: %s")
 }
 
-func resolveTagID(conn *sql.DB, input string) (string, error) {
+func resolveTagID(conn *sql.DB, input string, fuzzy bool) (string, error) {
 	if input == "" {
 		return "", nil
 	}
@@ -562,18 +681,74 @@ func resolveTagID(conn *sql.DB, input string) (string, error) {
 	} else if err != sql.ErrNoRows {
 		return "", err
 	}
-	if err := conn.QueryRow("SELECT uuid FROM TMTag WHERE lower(title) = lower(?)", input).Scan(&id); err == nil {
-		return id, nil
-	} else if err != sql.ErrNoRows {
+	const query = `SELECT t.uuid, t.title, IFNULL(p.title, '') FROM TMTag t
+		LEFT JOIN TMTag p ON t.parent = p.uuid
+		WHERE %s ORDER BY t.title COLLATE NOCASE`
+	return resolveTitle(conn, "tags", input, query, "1", nil, fuzzy, "tag not found: %s")
+}
+
+// resolveTitle resolves input against the rows of query, a SELECT of uuid,
+// title, and context with a %s placeholder for its condition. Exact titles
+// are looked up among all rows; with fuzzy set, fuzzy matches are tried
+// among rows meeting active when no title is exact.
+func resolveTitle(conn *sql.DB, kind string, input string, query string, active string, args []any, fuzzy bool, notFound string) (string, error) {
+	exactArgs := append(append([]any{}, args...), input)
+	exact, err := queryCandidates(conn, fmt.Sprintf(query, "lower(t.title) = lower(?)"), exactArgs...)
+	if err != nil {
+		return "", err
+	}
+	var candidates []Candidate
+	if fuzzy && len(exact) == 0 {
+		if candidates, err = queryCandidates(conn, fmt.Sprintf(query, active), args...); err != nil {
+			return "", err
+		}
+	}
+	id, err := resolveCandidate(kind, input, exact, candidates)
+	if err != nil {
 		return "", err
 	}
-	return "", fmt.Errorf("tag not found: %s", input)
+	if id == "" {
+		return "", fmt.Errorf(notFound, input)
+	}
+	return id, nil
+}
+
+func queryCandidates(conn *sql.DB, query string, args ...any) ([]Candidate, error) {
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	candidates := []Candidate{}
+	for rows.Next() {
+		var candidate Candidate
+		var title sql.NullString
+		if err := rows.Scan(&candidate.UUID, &title, &candidate.Context); err != nil {
+			return nil, err
+		}
+		candidate.Title = title.String
+		candidates = append(candidates, candidate)
+	}
+	return candidates, rows.Err()
 }
 
 func thingsDateTodayExpr() string {
 	return "((strftime('%Y', date('now', 'localtime')) << 16) | (strftime('%m', date('now', 'localtime')) << 12) | (strftime('%d', date('now', 'localtime')) << 7))"
 }
 
+// ExplainTaskQueries makes task listings record the SQL they would run and
+// return no rows, for --explain.
+func (s *Store) ExplainTaskQueries() {
+	s.explain = true
+	s.explained = nil
+}
+
+// ExplainedTaskQueries returns the statements recorded since
+// ExplainTaskQueries, in the order they would have run.
+func (s *Store) ExplainedTaskQueries() []TaskQuery {
+	return s.explained
+}
+
 // queryTasks runs the common task query with the given WHERE clause and filter.
 // SECURITY: where must only contain hardcoded SQL fragments with ? placeholders
 // for parameter binding — never pass unsanitized user input as the where string.
@@ -581,9 +756,19 @@ func (s *Store) queryTasks(where string, args []any, filter TaskFilter, order st
 	if s == nil || s.conn == nil {
 		return nil, fmt.Errorf("database not initialized")
 	}
+	schema, err := s.requireColumns((*Schema).taskQueryColumns)
+	if err != nil {
+		return nil, err
+	}
+	recurrence := "t." + schema.RepeatColumn("recurrenceRule")
+	todayIndex := "t.todayIndex"
+	if !schema.HasTodayIndex {
+		todayIndex = "NULL"
+	}
+
 	var b strings.Builder
-	b.WriteString("SELECT t.uuid, t.type, t.title, t.status, t.trashed, t.notes, t.start, t.startDate, t.deadline, t.stopDate, t.creationDate, t.userModificationDate, t.\"index\", t.todayIndex, (t.rt1_recurrenceRule IS NOT NULL) AS repeating, ")
-	b.WriteString("t.project, p.title, t.area, a.title, t.heading, h.title, ")
+	b.WriteString("SELECT t.uuid, t.type, t.title, t.status, t.trashed, t.notes, t.start, t.startDate, t.deadline, t.stopDate, t.creationDate, t.userModificationDate, t.\"index\", " + todayIndex + ", (" + recurrence + " IS NOT NULL) AS repeating, ")
+	b.WriteString("t.project, p.title, t.area, a.title, t.heading, h.title, hp.uuid, hp.title, ")
 	b.WriteString("(SELECT group_concat(title, '" + tagSeparator + "') FROM (")
 	b.WriteString("SELECT tag.title AS title FROM TMTag tag ")
 	b.WriteString("JOIN TMTaskTag tt ON tt.tags = tag.uuid ")
@@ -609,6 +794,10 @@ func (s *Store) queryTasks(where string, args []any, filter TaskFilter, order st
 		b.WriteString(" AND (" + where + ")")
 		params = append(params, args...)
 	}
+	if filter.Where != "" {
+		b.WriteString(" AND (" + filter.Where + ")")
+		params = append(params, filter.WhereArgs...)
+	}
 	if !filter.IncludeTrashed {
 		b.WriteString(" AND t.trashed = 0")
 	}
@@ -629,7 +818,7 @@ func (s *Store) queryTasks(where string, args []any, filter TaskFilter, order st
 		params = append(params, filter.AreaID)
 	}
 	if filter.TagID != "" {
-		b.WriteString(" AND EXISTS (SELECT 1 FROM TMTaskTag tt WHERE tt.tasks = t.uuid AND tt.tags = ?)")
+		b.WriteString(" AND " + tagFilterClause(filter.TagRecursive))
 		params = append(params, filter.TagID)
 	}
 	if filter.Search != "" {
@@ -669,9 +858,9 @@ func (s *Store) queryTasks(where string, args []any, filter TaskFilter, order st
 		}
 	}
 	if filter.RepeatingOnly {
-		b.WriteString(" AND t.rt1_recurrenceRule IS NOT NULL")
+		b.WriteString(" AND " + recurrence + " IS NOT NULL")
 	} else if !filter.IncludeRepeating {
-		b.WriteString(" AND t.rt1_recurrenceRule IS NULL")
+		b.WriteString(" AND " + recurrence + " IS NULL")
 	}
 
 	orderClause := order
@@ -681,6 +870,7 @@ func (s *Store) queryTasks(where string, args []any, filter TaskFilter, order st
 	if orderClause == "" {
 		orderClause = "t.\"index\""
 	}
+	orderClause = strings.ReplaceAll(orderClause, "t.todayIndex", schema.todayOrder())
 	b.WriteString(" ORDER BY " + orderClause)
 	if filter.Limit > 0 {
 		b.WriteString(" LIMIT ?")
@@ -694,6 +884,11 @@ func (s *Store) queryTasks(where string, args []any, filter TaskFilter, order st
 		params = append(params, filter.Offset)
 	}
 
+	if s.explain {
+		s.explained = append(s.explained, TaskQuery{SQL: b.String(), Args: params})
+		return []Task{}, nil
+	}
+
 	rows, err := s.conn.Query(b.String(), params...)
 	if err != nil {
 		return nil, err
@@ -743,8 +938,10 @@ func scanTaskRows(rows *sql.Rows) ([]Task, error) {
 		var areaTitle sql.NullString
 		var headingID sql.NullString
 		var headingTitle sql.NullString
+		var headingProjectID sql.NullString
+		var headingProjectTitle sql.NullString
 		var tagTitles sql.NullString
-		if err := rows.Scan(&t.UUID, &taskType, &t.Title, &t.Status, &t.Trashed, &notes, &start, &startDate, &deadline, &stopDate, &created, &modified, &index, &todayIndex, &repeating, &projectID, &projectTitle, &areaID, &areaTitle, &headingID, &headingTitle, &tagTitles); err != nil {
+		if err := rows.Scan(&t.UUID, &taskType, &t.Title, &t.Status, &t.Trashed, &notes, &start, &startDate, &deadline, &stopDate, &created, &modified, &index, &todayIndex, &repeating, &projectID, &projectTitle, &areaID, &areaTitle, &headingID, &headingTitle, &headingProjectID, &headingProjectTitle, &tagTitles); err != nil {
 			return nil, err
 		}
 		t.Type = taskTypeLabel(taskType)
@@ -797,6 +994,8 @@ func scanTaskRows(rows *sql.Rows) ([]Task, error) {
 		if headingTitle.Valid {
 			t.HeadingTitle = headingTitle.String
 		}
+		t.HeadingProjectID = headingProjectID.String
+		t.HeadingProjectTitle = headingProjectTitle.String
 		if tagTitles.Valid && tagTitles.String != "" {
 			t.Tags = strings.Split(tagTitles.String, tagSeparator)
 		}
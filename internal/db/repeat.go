@@ -38,14 +38,19 @@ func (s *Store) RepeatTargetByID(id string) (*RepeatTarget, error) {
 	if strings.TrimSpace(id) == "" {
 		return nil, sql.ErrNoRows
 	}
+	schema, err := s.requireColumns(repeatColumns("recurrenceRule", "repeatingTemplate"))
+	if err != nil {
+		return nil, err
+	}
 	var target RepeatTarget
 	var repeating sql.NullInt64
 	var repeatingTemplate sql.NullString
-	if err := s.conn.QueryRow(
-		`SELECT uuid, title, type, status, trashed, (rt1_recurrenceRule IS NOT NULL), rt1_repeatingTemplate
+	query := fmt.Sprintf(
+		`SELECT uuid, title, type, status, trashed, (%s IS NOT NULL), %s
 		 FROM TMTask WHERE uuid = ?`,
-		id,
-	).Scan(&target.UUID, &target.Title, &target.Type, &target.Status, &target.Trashed, &repeating, &repeatingTemplate); err != nil {
+		schema.RepeatColumn("recurrenceRule"), schema.RepeatColumn("repeatingTemplate"),
+	)
+	if err := s.conn.QueryRow(query, id).Scan(&target.UUID, &target.Title, &target.Type, &target.Status, &target.Trashed, &repeating, &repeatingTemplate); err != nil {
 		return nil, err
 	}
 	if repeating.Valid {
@@ -95,6 +100,41 @@ func (s *Store) TasksByTitleSince(title string, taskType int, since float64) ([]
 	return matches, rows.Err()
 }
 
+// TasksCreatedSince returns untrashed tasks with the given title whose
+// creation date is at or after the timestamp, newest first. Unlike
+// TasksByTitleSince it never matches an older task just because it was
+// edited recently.
+func (s *Store) TasksCreatedSince(title string, taskType int, since float64) ([]TaskMatch, error) {
+	if s == nil || s.conn == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("title required")
+	}
+	rows, err := s.conn.Query(
+		`SELECT uuid, creationDate FROM TMTask
+		 WHERE type = ? AND lower(title) = lower(?) AND trashed = 0 AND creationDate >= ?
+		 ORDER BY creationDate DESC`,
+		taskType,
+		title,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []TaskMatch
+	for rows.Next() {
+		var match TaskMatch
+		if err := rows.Scan(&match.UUID, &match.Created); err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+	return matches, rows.Err()
+}
+
 // TaskMatch identifies a task result for matching repeat updates after creation.
 type TaskMatch struct {
 	UUID    string
@@ -112,19 +152,25 @@ func (s *Store) ApplyRepeatRule(id string, update RepeatUpdate) error {
 	if len(update.RecurrenceRule) == 0 {
 		return fmt.Errorf("recurrence rule required")
 	}
+	schema, err := s.requireColumns(repeatWriteColumns)
+	if err != nil {
+		return err
+	}
 	modified := float64(time.Now().Unix())
 
 	var b strings.Builder
 	b.WriteString("UPDATE TMTask SET ")
-	b.WriteString("rt1_recurrenceRule = ?, ")
-	b.WriteString("rt1_instanceCreationStartDate = ?, ")
-	b.WriteString("rt1_instanceCreationPaused = ?, ")
-	b.WriteString("rt1_instanceCreationCount = ?, ")
-	b.WriteString("rt1_afterCompletionReferenceDate = ?, ")
-	b.WriteString("rt1_nextInstanceStartDate = ?, ")
+	for _, field := range []string{
+		"recurrenceRule", "instanceCreationStartDate", "instanceCreationPaused",
+		"instanceCreationCount", "afterCompletionReferenceDate", "nextInstanceStartDate",
+	} {
+		b.WriteString(schema.RepeatColumn(field) + " = ?, ")
+	}
 	if update.SetDeadline {
 		b.WriteString("deadline = ?, ")
-		b.WriteString("deadlineSuppressionDate = NULL, ")
+		if schema.HasDeadlineSuppression {
+			b.WriteString("deadlineSuppressionDate = NULL, ")
+		}
 	}
 	b.WriteString("userModificationDate = ? ")
 	b.WriteString("WHERE uuid = ?")
@@ -142,7 +188,10 @@ func (s *Store) ApplyRepeatRule(id string, update RepeatUpdate) error {
 	}
 	args = append(args, modified, id)
 
-	_, err := s.conn.Exec(b.String(), args...)
+	if err := s.beforeWrite(); err != nil {
+		return err
+	}
+	_, err = s.conn.Exec(b.String(), args...)
 	return err
 }
 
@@ -154,21 +203,159 @@ func (s *Store) ClearRepeatRule(id string) error {
 	if strings.TrimSpace(id) == "" {
 		return fmt.Errorf("task id required")
 	}
+	schema, err := s.requireColumns(repeatWriteColumns)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	b.WriteString("UPDATE TMTask SET ")
+	for _, field := range []string{"recurrenceRule", "instanceCreationStartDate", "afterCompletionReferenceDate", "nextInstanceStartDate"} {
+		b.WriteString(schema.RepeatColumn(field) + " = NULL, ")
+	}
+	for _, field := range []string{"instanceCreationPaused", "instanceCreationCount"} {
+		b.WriteString(schema.RepeatColumn(field) + " = 0, ")
+	}
+	b.WriteString("deadline = NULL, ")
+	if schema.HasDeadlineSuppression {
+		b.WriteString("deadlineSuppressionDate = NULL, ")
+	}
+	b.WriteString("userModificationDate = ? WHERE uuid = ?")
 	modified := float64(time.Now().Unix())
-	_, err := s.conn.Exec(
-		`UPDATE TMTask SET
-			rt1_recurrenceRule = NULL,
-			rt1_instanceCreationStartDate = NULL,
-			rt1_instanceCreationPaused = 0,
-			rt1_instanceCreationCount = 0,
-			rt1_afterCompletionReferenceDate = NULL,
-			rt1_nextInstanceStartDate = NULL,
-			deadline = NULL,
-			deadlineSuppressionDate = NULL,
-			userModificationDate = ?
-		  WHERE uuid = ?`,
-		modified,
-		id,
-	)
+	if err := s.beforeWrite(); err != nil {
+		return err
+	}
+	_, err = s.conn.Exec(b.String(), modified, id)
 	return err
 }
+
+// RepeatRuleByID returns the current repeat fields of a task/project, or nil
+// when the item does not repeat. The result can be passed to ApplyRepeatRule
+// to restore the rule later.
+func (s *Store) RepeatRuleByID(id string) (*RepeatUpdate, error) {
+	if s == nil || s.conn == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if strings.TrimSpace(id) == "" {
+		return nil, sql.ErrNoRows
+	}
+	fields := []string{
+		"recurrenceRule", "instanceCreationStartDate", "instanceCreationPaused",
+		"instanceCreationCount", "afterCompletionReferenceDate", "nextInstanceStartDate",
+	}
+	schema, err := s.requireColumns(repeatColumns(fields...))
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, len(fields)+1)
+	for _, field := range fields {
+		columns = append(columns, schema.RepeatColumn(field))
+	}
+	columns = append(columns, "deadline")
+	var rule []byte
+	var startDate, paused, count sql.NullInt64
+	var afterCompletion, nextStart, deadline sql.NullInt64
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM TMTask WHERE uuid = ?"
+	if err := s.conn.QueryRow(query, id).Scan(&rule, &startDate, &paused, &count, &afterCompletion, &nextStart, &deadline); err != nil {
+		return nil, err
+	}
+	if len(rule) == 0 {
+		return nil, nil
+	}
+	update := &RepeatUpdate{
+		RecurrenceRule:            rule,
+		InstanceCreationStartDate: int(startDate.Int64),
+		InstanceCreationPaused:    int(paused.Int64),
+		InstanceCreationCount:     int(count.Int64),
+		SetDeadline:               true,
+	}
+	if afterCompletion.Valid {
+		value := int(afterCompletion.Int64)
+		update.AfterCompletionReference = &value
+	}
+	if nextStart.Valid {
+		value := int(nextStart.Int64)
+		update.NextInstanceStartDate = &value
+	}
+	if deadline.Valid {
+		value := int(deadline.Int64)
+		update.Deadline = &value
+	}
+	return update, nil
+}
+
+// RecurrenceRules returns the raw recurrence rule plists for the given
+// task IDs, keyed by UUID. IDs without a rule are omitted.
+func (s *Store) RecurrenceRules(ids []string) (map[string][]byte, error) {
+	if s == nil || s.conn == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	schema, err := s.requireColumns(repeatColumns("recurrenceRule"))
+	if err != nil {
+		return nil, err
+	}
+	rules := make(map[string][]byte, len(ids))
+	if len(ids) == 0 {
+		return rules, nil
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	column := schema.RepeatColumn("recurrenceRule")
+	query := fmt.Sprintf(
+		`SELECT uuid, %s FROM TMTask
+		 WHERE uuid IN (%s) AND %s IS NOT NULL`,
+		column, strings.Join(placeholders, ","), column,
+	)
+	rows, err := s.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var rule []byte
+		if err := rows.Scan(&id, &rule); err != nil {
+			return nil, err
+		}
+		if len(rule) > 0 {
+			rules[id] = rule
+		}
+	}
+	return rules, rows.Err()
+}
+
+// ProjectRepeatingTodos lists the open, non-trashed todos of a project
+// (including todos under its headings) that carry their own repeat rule,
+// which Things does not allow inside a repeating project.
+func (s *Store) ProjectRepeatingTodos(projectID string) ([]string, error) {
+	if s == nil || s.conn == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	schema, err := s.requireColumns(repeatColumns("recurrenceRule"))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.conn.Query(
+		`SELECT uuid FROM TMTask
+		 WHERE type = ? AND trashed = 0 AND status = ? AND `+schema.RepeatColumn("recurrenceRule")+` IS NOT NULL
+		   AND (project = ? OR heading IN (SELECT uuid FROM TMTask WHERE type = ? AND project = ?))
+		 ORDER BY uuid`,
+		TaskTypeTodo, StatusIncomplete, projectID, TaskTypeHeading, projectID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"database/sql"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -95,6 +96,21 @@ func TestApplyAndClearRepeatRule(t *testing.T) {
 		t.Fatalf("expected userModificationDate to be set")
 	}
 
+	snapshot, err := store.RepeatRuleByID("T1")
+	if err != nil {
+		t.Fatalf("repeat rule: %v", err)
+	}
+	if snapshot == nil || string(snapshot.RecurrenceRule) != string(update.RecurrenceRule) || snapshot.Deadline == nil || *snapshot.Deadline != deadline {
+		t.Fatalf("unexpected repeat snapshot: %+v", snapshot)
+	}
+	rules, err := store.RecurrenceRules([]string{"T1", "T2"})
+	if err != nil {
+		t.Fatalf("recurrence rules: %v", err)
+	}
+	if len(rules) != 1 || string(rules["T1"]) != string(update.RecurrenceRule) {
+		t.Fatalf("unexpected recurrence rules: %v", rules)
+	}
+
 	if err := store.ClearRepeatRule("T1"); err != nil {
 		t.Fatalf("clear repeat: %v", err)
 	}
@@ -108,6 +124,9 @@ func TestApplyAndClearRepeatRule(t *testing.T) {
 	if dbDeadline.Valid {
 		t.Fatalf("expected deadline cleared")
 	}
+	if snapshot, err := store.RepeatRuleByID("T1"); err != nil || snapshot != nil {
+		t.Fatalf("expected no repeat rule after clear, got %+v (%v)", snapshot, err)
+	}
 }
 
 func TestTasksByTitleSinceUsesModificationDate(t *testing.T) {
@@ -155,3 +174,65 @@ func TestTasksByTitleSinceUsesModificationDate(t *testing.T) {
 		t.Fatalf("expected T1, got %s", matches[0].UUID)
 	}
 }
+
+func TestProjectRepeatingTodos(t *testing.T) {
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+	if err := seedTestDB(conn); err != nil {
+		t.Fatalf("seed db: %v", err)
+	}
+	store := &Store{conn: conn, path: ":memory:"}
+
+	repeating, err := store.ProjectRepeatingTodos("P1")
+	if err != nil {
+		t.Fatalf("repeating todos: %v", err)
+	}
+	if len(repeating) != 1 || repeating[0] != "T2" {
+		t.Fatalf("unexpected repeating todos: %v", repeating)
+	}
+}
+
+// TestRepeatInstancesAreCreatedByThings reads the repeating to-do exported
+// from Things in integration/fixtures: the instance is a separate row that
+// Things created from the template, carries no rule of its own, and points
+// back through rt1_repeatingTemplate. The fixture has no repeating project;
+// projects use the same columns, so repeat writes only ever touch the
+// template and leave instances (and their contents) to Things.
+func TestRepeatInstancesAreCreatedByThings(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.sqlite", "main.sqlite-shm", "main.sqlite-wal"} {
+		data, err := os.ReadFile(filepath.Join("..", "..", "integration", "fixtures", name))
+		if err != nil {
+			t.Fatalf("read fixture: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("copy fixture: %v", err)
+		}
+	}
+	store, err := Open(filepath.Join(dir, "main.sqlite"))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer store.Close()
+
+	template, err := store.RepeatTargetByID("N1PJHsbjct4mb1bhcs7aHa")
+	if err != nil {
+		t.Fatalf("template: %v", err)
+	}
+	if !template.Repeating || template.RepeatingTemplateID != "" {
+		t.Fatalf("expected a template with a rule, got %+v", template)
+	}
+	instance, err := store.RepeatTargetByID("K9bx7h1xCJdevvyWardZDq")
+	if err != nil {
+		t.Fatalf("instance: %v", err)
+	}
+	if instance.Repeating || instance.RepeatingTemplateID != template.UUID {
+		t.Fatalf("expected an instance linked to %s, got %+v", template.UUID, instance)
+	}
+	if instance.Title != template.Title || instance.Type != template.Type {
+		t.Fatalf("expected the instance to copy the template, got %+v from %+v", instance, template)
+	}
+}
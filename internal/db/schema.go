@@ -0,0 +1,218 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrUnsupportedSchema is matched (via errors.Is) by every *SchemaError.
+var ErrUnsupportedSchema = errors.New("unsupported Things database schema")
+
+// SchemaError reports the columns a query needs that the database lacks.
+type SchemaError struct {
+	Version string
+	Missing []string
+}
+
+func (e *SchemaError) Error() string {
+	version := ""
+	if e.Version != "" {
+		version = " (database version " + e.Version + ")"
+	}
+	return fmt.Sprintf("%s%s: missing columns %s", ErrUnsupportedSchema, version, strings.Join(e.Missing, ", "))
+}
+
+// Is makes errors.Is(err, ErrUnsupportedSchema) hold for schema errors.
+func (e *SchemaError) Is(target error) bool {
+	return target == ErrUnsupportedSchema
+}
+
+// Schema is the introspected layout of a Things database and the query
+// variants it selects. The queries are written against databaseVersion 24
+// (Things 3, as in integration/fixtures/main.sqlite), which stores repeat
+// fields as rt1_* columns and has todayIndex and deadlineSuppressionDate.
+// Databases without one of those two optional columns get a fallback
+// variant; anything else missing is an *SchemaError rather than a guessed
+// layout. Version is kept for diagnostics.
+type Schema struct {
+	// Version is the databaseVersion recorded in the Meta table, if any.
+	Version string
+	// HasTodayIndex is false for databases without Today ordering; Today then
+	// falls back to the list index.
+	HasTodayIndex bool
+	// HasDeadlineSuppression is false for databases that cannot dismiss
+	// overdue deadlines from Today.
+	HasDeadlineSuppression bool
+
+	columns map[string]map[string]bool
+}
+
+// SupportedSchemaVersion is the databaseVersion the queries are written
+// against.
+const SupportedSchemaVersion = "24"
+
+// schemaTables are the tables introspected when a store opens.
+var schemaTables = []string{"TMTask", "TMArea", "TMTag", "TMTaskTag", "TMChecklistItem"}
+
+var repeatFields = []string{
+	"recurrenceRule", "repeatingTemplate", "instanceCreationStartDate", "instanceCreationPaused",
+	"instanceCreationCount", "afterCompletionReferenceDate", "nextInstanceStartDate",
+}
+
+func detectSchema(conn *sql.DB) (*Schema, error) {
+	schema := &Schema{columns: map[string]map[string]bool{}}
+	for _, table := range schemaTables {
+		columns, err := tableColumns(conn, table)
+		if err != nil {
+			return nil, err
+		}
+		schema.columns[table] = columns
+	}
+	task := schema.columns["TMTask"]
+	schema.HasTodayIndex = task["todayIndex"]
+	schema.HasDeadlineSuppression = task["deadlineSuppressionDate"]
+	schema.Version = metaDatabaseVersion(conn)
+	return schema, nil
+}
+
+// tableColumns returns the column names of table. Table names come from the
+// fixed schema lists above, never from user input.
+func tableColumns(conn *sql.DB, table string) (map[string]bool, error) {
+	rows, err := conn.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("inspect %s: %w", table, err)
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+var plistIntegerPattern = regexp.MustCompile(`<integer>\s*(-?\d+)\s*</integer>`)
+
+// metaDatabaseVersion reads Meta.databaseVersion, which Things stores as a
+// small XML plist. Databases without a Meta table report "".
+func metaDatabaseVersion(conn *sql.DB) string {
+	var value sql.NullString
+	if err := conn.QueryRow(`SELECT value FROM Meta WHERE key = 'databaseVersion'`).Scan(&value); err != nil {
+		return ""
+	}
+	if match := plistIntegerPattern.FindStringSubmatch(value.String); match != nil {
+		return match[1]
+	}
+	return strings.TrimSpace(value.String)
+}
+
+// RepeatColumn returns the column holding a repeat field. Reads and writes
+// both name repeat columns through it.
+func (sc *Schema) RepeatColumn(field string) string {
+	return "rt1_" + field
+}
+
+// todayOrder is the ORDER BY expression for Today listings.
+func (sc *Schema) todayOrder() string {
+	if sc.HasTodayIndex {
+		return "t.todayIndex"
+	}
+	return "t.\"index\""
+}
+
+// taskQueryColumns lists the tables and columns task, project, and tree
+// listings read or filter on. Optional columns handled by variants are left
+// out.
+func (sc *Schema) taskQueryColumns() map[string][]string {
+	return map[string][]string{
+		"TMTask": {
+			"uuid", "type", "title", "status", "trashed", "notes", "start", "startDate",
+			"deadline", "stopDate", "creationDate", "userModificationDate", "index",
+			sc.RepeatColumn("recurrenceRule"), "project", "area", "heading",
+		},
+		"TMArea":    {"uuid", "title"},
+		"TMTag":     {"uuid", "title"},
+		"TMTaskTag": {"tasks", "tags"},
+	}
+}
+
+// repeatColumns requires the given repeat fields, named for this layout.
+func repeatColumns(fields ...string) func(*Schema) map[string][]string {
+	return func(sc *Schema) map[string][]string {
+		columns := make([]string, 0, len(fields))
+		for _, field := range fields {
+			columns = append(columns, sc.RepeatColumn(field))
+		}
+		return map[string][]string{"TMTask": columns}
+	}
+}
+
+// repeatWriteColumns lists the columns repeat writes touch.
+func repeatWriteColumns(sc *Schema) map[string][]string {
+	columns := []string{"uuid", "deadline", "userModificationDate"}
+	for _, field := range repeatFields {
+		columns = append(columns, sc.RepeatColumn(field))
+	}
+	return map[string][]string{"TMTask": columns}
+}
+
+// missing reports required table.column names absent from the database,
+// sorted.
+func (sc *Schema) missing(required map[string][]string) []string {
+	missing := []string{}
+	for table, columns := range required {
+		for _, column := range columns {
+			if !sc.columns[table][column] {
+				missing = append(missing, table+"."+column)
+			}
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// Schema returns the database layout detected when the store was opened.
+func (s *Store) Schema() (*Schema, error) {
+	if s == nil || s.conn == nil {
+		return nil, fmt.Errorf("database not initialized")
+	}
+	if s.schema == nil {
+		schema, err := detectSchema(s.conn)
+		if err != nil {
+			return nil, err
+		}
+		s.schema = schema
+	}
+	return s.schema, nil
+}
+
+// requireColumns returns the schema, or a *SchemaError naming any required
+// column the database lacks.
+func (s *Store) requireColumns(required func(*Schema) map[string][]string) (*Schema, error) {
+	schema, err := s.Schema()
+	if err != nil {
+		return nil, err
+	}
+	if missing := schema.missing(required(schema)); len(missing) > 0 {
+		return nil, &SchemaError{Version: schema.Version, Missing: missing}
+	}
+	return schema, nil
+}
+
+// MissingTaskQueryColumns reports the table.column names that task listings
+// need but the database lacks, sorted. A missing table reports each of its
+// columns.
+func (s *Store) MissingTaskQueryColumns() ([]string, error) {
+	schema, err := s.Schema()
+	if err != nil {
+		return nil, err
+	}
+	return schema.missing(schema.taskQueryColumns()), nil
+}
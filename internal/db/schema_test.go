@@ -0,0 +1,97 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func openSeededStore(t *testing.T, alter ...string) *Store {
+	t.Helper()
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := seedTestDB(conn); err != nil {
+		t.Fatalf("seed db: %v", err)
+	}
+	for _, statement := range alter {
+		if _, err := conn.Exec(statement); err != nil {
+			t.Fatalf("alter schema: %v", err)
+		}
+	}
+	return &Store{conn: conn, path: ":memory:"}
+}
+
+func TestSchemaDetectsCurrentLayout(t *testing.T) {
+	store := openSeededStore(t, `CREATE TABLE Meta (key TEXT PRIMARY KEY, value TEXT)`,
+		`INSERT INTO Meta (key, value) VALUES ('databaseVersion', '<?xml version="1.0"?><plist version="1.0"><integer>26</integer></plist>')`)
+
+	schema, err := store.Schema()
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	if schema.Version != "26" || !schema.HasTodayIndex || !schema.HasDeadlineSuppression {
+		t.Fatalf("unexpected schema: %+v", schema)
+	}
+	missing, err := store.MissingTaskQueryColumns()
+	if err != nil {
+		t.Fatalf("missing columns: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("expected complete schema, missing %v", missing)
+	}
+}
+
+func TestSchemaFallsBackWithoutOptionalColumns(t *testing.T) {
+	store := openSeededStore(t,
+		`ALTER TABLE TMTask DROP COLUMN todayIndex`,
+		`ALTER TABLE TMTask DROP COLUMN deadlineSuppressionDate`,
+	)
+	status := StatusIncomplete
+	if _, err := store.TodayTasks(TaskFilter{Status: &status}); err != nil {
+		t.Fatalf("today without todayIndex: %v", err)
+	}
+	if _, err := store.Tasks(TaskFilter{Order: "t.todayIndex"}); err != nil {
+		t.Fatalf("order by todayIndex: %v", err)
+	}
+}
+
+func TestSchemaRejectsUnprefixedRepeatColumns(t *testing.T) {
+	// Unprefixed repeat columns match no known Things version, so reads and
+	// writes both report the missing rt1_ column instead of guessing.
+	store := openSeededStore(t, `ALTER TABLE TMTask RENAME COLUMN rt1_recurrenceRule TO recurrenceRule`)
+
+	_, err := store.Tasks(TaskFilter{RepeatingOnly: true})
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || !reflect.DeepEqual(schemaErr.Missing, []string{"TMTask.rt1_recurrenceRule"}) {
+		t.Fatalf("expected reads to name the missing column, got %v", err)
+	}
+
+	err = store.ApplyRepeatRule("T1", RepeatUpdate{RecurrenceRule: []byte{0x01}})
+	if !errors.As(err, &schemaErr) || !strings.Contains(err.Error(), "TMTask.rt1_recurrenceRule") {
+		t.Fatalf("expected writes to name the missing column, got %v", err)
+	}
+}
+
+func TestSchemaReportsMissingColumns(t *testing.T) {
+	store := openSeededStore(t, `DROP TABLE TMTaskTag`)
+
+	_, err := store.Tasks(TaskFilter{})
+	if !errors.Is(err, ErrUnsupportedSchema) {
+		t.Fatalf("expected ErrUnsupportedSchema, got %v", err)
+	}
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected *SchemaError, got %T", err)
+	}
+	want := []string{"TMTaskTag.tags", "TMTaskTag.tasks"}
+	if !reflect.DeepEqual(schemaErr.Missing, want) {
+		t.Fatalf("expected missing %v, got %v", want, schemaErr.Missing)
+	}
+}
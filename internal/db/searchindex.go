@@ -0,0 +1,376 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// searchIndexVersion changes whenever the index layout does; an index with
+// another version is rebuilt from scratch.
+const searchIndexVersion = "1"
+
+// searchIndexBatch bounds how many task IDs go into one checklist lookup.
+const searchIndexBatch = 500
+
+// SearchIndex is a full-text index of todo titles, notes, and checklist
+// items. It lives in its own SQLite file, never inside the Things container,
+// and is kept up to date by Store.RefreshSearchIndex.
+type SearchIndex struct {
+	conn *sql.DB
+	path string
+}
+
+// SearchIndexStats reports what a refresh changed.
+type SearchIndexStats struct {
+	Rebuilt bool
+	Updated int
+	Removed int
+	Total   int
+}
+
+// SearchHit is one ranked match. Score is the negated BM25 rank, so higher
+// is better; Snippet is the best matching passage with the matched terms
+// wrapped in the markers passed to Search.
+type SearchHit struct {
+	UUID    string
+	Score   float64
+	Snippet string
+}
+
+// SearchIndexPath returns where the search index is stored. It honors
+// THINGS_SEARCH_INDEX and otherwise lives in the user cache directory.
+func SearchIndexPath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("THINGS_SEARCH_INDEX")); path != "" {
+		return expandHome(path), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "things3-cli", "search-index.sqlite"), nil
+}
+
+// OpenSearchIndex opens the index at path, creating it if needed.
+func OpenSearchIndex(path string) (*SearchIndex, error) {
+	if path == "" {
+		return nil, fmt.Errorf("empty search index path")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolve search index path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+		return nil, fmt.Errorf("create search index directory: %w", err)
+	}
+	conn, err := sql.Open("sqlite", sqliteDSN(abs, "rwc"))
+	if err != nil {
+		return nil, fmt.Errorf("open search index: %w", err)
+	}
+	// One connection keeps transactions and the FTS table on the same handle.
+	conn.SetMaxOpenConns(1)
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS search_meta (key TEXT PRIMARY KEY, value TEXT NOT NULL)`,
+		`CREATE TABLE IF NOT EXISTS search_docs (id INTEGER PRIMARY KEY, uuid TEXT NOT NULL UNIQUE)`,
+		`CREATE VIRTUAL TABLE IF NOT EXISTS search_fts USING fts5(title, notes, checklist, tokenize = 'porter unicode61 remove_diacritics 2')`,
+	}
+	for _, statement := range statements {
+		if _, err := conn.Exec(statement); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("open search index: %w", err)
+		}
+	}
+	return &SearchIndex{conn: conn, path: abs}, nil
+}
+
+// Close closes the index.
+func (x *SearchIndex) Close() error {
+	if x == nil || x.conn == nil {
+		return nil
+	}
+	return x.conn.Close()
+}
+
+// Path returns the index file path.
+func (x *SearchIndex) Path() string {
+	if x == nil {
+		return ""
+	}
+	return x.path
+}
+
+func (x *SearchIndex) meta(key string) (string, error) {
+	var value string
+	err := x.conn.QueryRow(`SELECT value FROM search_meta WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return value, err
+}
+
+func setSearchMeta(tx *sql.Tx, key string, value string) error {
+	_, err := tx.Exec(`INSERT INTO search_meta (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+// RefreshSearchIndex brings index up to date with the store. Only todos
+// whose userModificationDate (or one of whose checklist items') is newer
+// than the last refresh are re-read, and todos no longer in the database
+// are dropped. The index is rebuilt from scratch when rebuild is set, when
+// it was built from another database, or when its layout is outdated.
+func (s *Store) RefreshSearchIndex(index *SearchIndex, rebuild bool) (SearchIndexStats, error) {
+	stats := SearchIndexStats{}
+	if s == nil || s.conn == nil {
+		return stats, fmt.Errorf("database not initialized")
+	}
+	if index == nil || index.conn == nil {
+		return stats, fmt.Errorf("search index not initialized")
+	}
+
+	version, err := index.meta("version")
+	if err != nil {
+		return stats, err
+	}
+	source, err := index.meta("source")
+	if err != nil {
+		return stats, err
+	}
+	rawWatermark, err := index.meta("watermark")
+	if err != nil {
+		return stats, err
+	}
+	watermark := 0.0
+	if rawWatermark != "" {
+		watermark, _ = strconv.ParseFloat(rawWatermark, 64)
+	}
+	if rebuild || version != searchIndexVersion || source != s.path {
+		stats.Rebuilt = true
+		watermark = 0
+	}
+
+	// Take the new watermark before reading so edits made while the refresh
+	// runs are picked up by the next one.
+	var latest float64
+	if err := s.conn.QueryRow(
+		`SELECT MAX(
+			(SELECT IFNULL(MAX(userModificationDate), 0) FROM TMTask WHERE type = ?),
+			(SELECT IFNULL(MAX(userModificationDate), 0) FROM TMChecklistItem))`,
+		TaskTypeTodo,
+	).Scan(&latest); err != nil {
+		return stats, err
+	}
+
+	filter := TaskFilter{
+		Types:            []int{TaskTypeTodo},
+		IncludeTrashed:   true,
+		IncludeRepeating: true,
+	}
+	if !stats.Rebuilt {
+		filter.Where = "t.userModificationDate > ? OR t.uuid IN (SELECT task FROM TMChecklistItem WHERE userModificationDate > ?)"
+		filter.WhereArgs = []any{watermark, watermark}
+	}
+	changed, err := s.Tasks(filter)
+	if err != nil {
+		return stats, err
+	}
+	for start := 0; start < len(changed); start += searchIndexBatch {
+		end := start + searchIndexBatch
+		if end > len(changed) {
+			end = len(changed)
+		}
+		ids := make([]string, 0, end-start)
+		for _, task := range changed[start:end] {
+			ids = append(ids, task.UUID)
+		}
+		items, err := loadChecklistItems(s.conn, ids)
+		if err != nil {
+			return stats, err
+		}
+		for i := start; i < end; i++ {
+			changed[i].Checklist = items[changed[i].UUID]
+		}
+	}
+
+	live, err := s.todoIDs()
+	if err != nil {
+		return stats, err
+	}
+
+	tx, err := index.conn.Begin()
+	if err != nil {
+		return stats, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if stats.Rebuilt {
+		for _, statement := range []string{`DELETE FROM search_fts`, `DELETE FROM search_docs`, `DELETE FROM search_meta`} {
+			if _, err := tx.Exec(statement); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	for _, task := range changed {
+		if err := upsertSearchDoc(tx, task); err != nil {
+			return stats, err
+		}
+		stats.Updated++
+	}
+
+	rows, err := tx.Query(`SELECT id, uuid FROM search_docs`)
+	if err != nil {
+		return stats, err
+	}
+	stale := []int64{}
+	for rows.Next() {
+		var id int64
+		var uuid string
+		if err := rows.Scan(&id, &uuid); err != nil {
+			rows.Close()
+			return stats, err
+		}
+		if !live[uuid] {
+			stale = append(stale, id)
+		}
+	}
+	if err := rows.Close(); err != nil {
+		return stats, err
+	}
+	for _, id := range stale {
+		if err := deleteSearchDoc(tx, id); err != nil {
+			return stats, err
+		}
+		stats.Removed++
+	}
+
+	if watermark > latest {
+		latest = watermark
+	}
+	meta := [][2]string{
+		{"version", searchIndexVersion},
+		{"source", s.path},
+		{"watermark", strconv.FormatFloat(latest, 'f', -1, 64)},
+	}
+	for _, entry := range meta {
+		if err := setSearchMeta(tx, entry[0], entry[1]); err != nil {
+			return stats, err
+		}
+	}
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM search_docs`).Scan(&stats.Total); err != nil {
+		return stats, err
+	}
+	return stats, tx.Commit()
+}
+
+func (s *Store) todoIDs() (map[string]bool, error) {
+	rows, err := s.conn.Query(`SELECT uuid FROM TMTask WHERE type = ?`, TaskTypeTodo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ids := map[string]bool{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+func upsertSearchDoc(tx *sql.Tx, task Task) error {
+	var id int64
+	err := tx.QueryRow(`SELECT id FROM search_docs WHERE uuid = ?`, task.UUID).Scan(&id)
+	switch {
+	case err == sql.ErrNoRows:
+		result, err := tx.Exec(`INSERT INTO search_docs (uuid) VALUES (?)`, task.UUID)
+		if err != nil {
+			return err
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		if _, err := tx.Exec(`DELETE FROM search_fts WHERE rowid = ?`, id); err != nil {
+			return err
+		}
+	}
+	checklist := make([]string, 0, len(task.Checklist))
+	for _, item := range task.Checklist {
+		checklist = append(checklist, item.Title)
+	}
+	_, err = tx.Exec(
+		`INSERT INTO search_fts (rowid, title, notes, checklist) VALUES (?, ?, ?, ?)`,
+		id, task.Title, task.Notes, strings.Join(checklist, "\n"),
+	)
+	return err
+}
+
+func deleteSearchDoc(tx *sql.Tx, id int64) error {
+	if _, err := tx.Exec(`DELETE FROM search_fts WHERE rowid = ?`, id); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`DELETE FROM search_docs WHERE id = ?`, id)
+	return err
+}
+
+// Search returns todos matching every word of text, best first. A word
+// ending in * matches as a prefix. Titles weigh more than checklist items,
+// which weigh more than notes.
+func (x *SearchIndex) Search(text string, openMark string, closeMark string) ([]SearchHit, error) {
+	if x == nil || x.conn == nil {
+		return nil, fmt.Errorf("search index not initialized")
+	}
+	match := searchMatchExpression(text)
+	if match == "" {
+		return nil, fmt.Errorf("empty search")
+	}
+	rows, err := x.conn.Query(
+		`SELECT d.uuid, -bm25(search_fts, 5.0, 1.0, 2.0), snippet(search_fts, -1, ?, ?, '…', 16)
+		 FROM search_fts JOIN search_docs d ON d.id = search_fts.rowid
+		 WHERE search_fts MATCH ?
+		 ORDER BY bm25(search_fts, 5.0, 1.0, 2.0), d.uuid`,
+		openMark, closeMark, match,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	hits := []SearchHit{}
+	for rows.Next() {
+		var hit SearchHit
+		if err := rows.Scan(&hit.UUID, &hit.Score, &hit.Snippet); err != nil {
+			return nil, err
+		}
+		hit.Snippet = strings.Join(strings.Fields(hit.Snippet), " ")
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// searchMatchExpression quotes each word of text as an FTS5 string so user
+// input never reaches the query syntax; the words are ANDed.
+func searchMatchExpression(text string) string {
+	terms := []string{}
+	for _, word := range strings.Fields(text) {
+		prefix := strings.HasSuffix(word, "*")
+		word = strings.Trim(word, "*")
+		word = strings.ReplaceAll(word, `"`, "")
+		if word == "" {
+			continue
+		}
+		term := `"` + word + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSearchIndexRefreshAndSearch(t *testing.T) {
+	store := openSeededStore(t)
+	store.conn.SetMaxOpenConns(1)
+	index, err := OpenSearchIndex(filepath.Join(t.TempDir(), "index", "search.sqlite"))
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer index.Close()
+
+	refresh := func(rebuild bool) SearchIndexStats {
+		t.Helper()
+		stats, err := store.RefreshSearchIndex(index, rebuild)
+		if err != nil {
+			t.Fatalf("refresh: %v", err)
+		}
+		return stats
+	}
+	search := func(text string) []SearchHit {
+		t.Helper()
+		hits, err := index.Search(text, "[", "]")
+		if err != nil {
+			t.Fatalf("search %q: %v", text, err)
+		}
+		return hits
+	}
+
+	stats := refresh(false)
+	if !stats.Rebuilt || stats.Updated != 2 || stats.Total != 2 {
+		t.Fatalf("unexpected first refresh: %#v", stats)
+	}
+	if stats := refresh(false); stats.Rebuilt || stats.Updated != 0 || stats.Total != 2 {
+		t.Fatalf("expected a no-op refresh, got %#v", stats)
+	}
+
+	// Porter stemming lets "note" find "notes"; checklist items are indexed.
+	hits := search("note")
+	if len(hits) != 1 || hits[0].UUID != "T1" || hits[0].Snippet != "Some [notes]" {
+		t.Fatalf("unexpected hits for note: %#v", hits)
+	}
+	if hits[0].Score <= 0 {
+		t.Fatalf("expected a positive score, got %v", hits[0].Score)
+	}
+	if hits := search("check item"); len(hits) != 1 || hits[0].UUID != "T1" {
+		t.Fatalf("unexpected hits for checklist text: %#v", hits)
+	}
+
+	later := float64(time.Date(2025, 1, 3, 0, 0, 0, 0, time.Local).Unix())
+	if _, err := store.conn.Exec(`UPDATE TMTask SET title = 'Quarterly meetings', userModificationDate = ? WHERE uuid = 'T2'`, later); err != nil {
+		t.Fatalf("update task: %v", err)
+	}
+	if stats := refresh(false); stats.Rebuilt || stats.Updated != 1 {
+		t.Fatalf("expected one updated task, got %#v", stats)
+	}
+	if hits := search("meeting"); len(hits) != 1 || hits[0].UUID != "T2" {
+		t.Fatalf("unexpected hits after update: %#v", hits)
+	}
+
+	if _, err := store.conn.Exec(`UPDATE TMChecklistItem SET title = 'Budget review', userModificationDate = ? WHERE uuid = 'C1'`, later+1); err != nil {
+		t.Fatalf("update checklist: %v", err)
+	}
+	if stats := refresh(false); stats.Updated != 1 {
+		t.Fatalf("expected the checklist change to reindex its task, got %#v", stats)
+	}
+	if hits := search("budg*"); len(hits) != 1 || hits[0].UUID != "T1" {
+		t.Fatalf("unexpected prefix hits: %#v", hits)
+	}
+	if hits := search("check"); len(hits) != 0 {
+		t.Fatalf("expected old checklist text to be gone, got %#v", hits)
+	}
+
+	if _, err := store.conn.Exec(`DELETE FROM TMTask WHERE uuid = 'T2'`); err != nil {
+		t.Fatalf("delete task: %v", err)
+	}
+	if stats := refresh(false); stats.Removed != 1 || stats.Total != 1 {
+		t.Fatalf("expected the deleted task to be dropped, got %#v", stats)
+	}
+
+	if stats := refresh(true); !stats.Rebuilt || stats.Updated != 1 || stats.Total != 1 {
+		t.Fatalf("unexpected rebuild: %#v", stats)
+	}
+}
+
+func TestSearchIndexQuotesUserInput(t *testing.T) {
+	if got := searchMatchExpression(`notes OR "title:x pre*`); got != `"notes" "OR" "title:x" "pre"*` {
+		t.Fatalf("unexpected match expression: %s", got)
+	}
+
+	index, err := OpenSearchIndex(filepath.Join(t.TempDir(), "search.sqlite"))
+	if err != nil {
+		t.Fatalf("open index: %v", err)
+	}
+	defer index.Close()
+	if _, err := index.Search(`NEAR( "unbalanced`, "[", "]"); err != nil {
+		t.Fatalf("search with syntax characters: %v", err)
+	}
+	if _, err := index.Search("  ", "[", "]"); err == nil || !strings.Contains(err.Error(), "empty search") {
+		t.Fatalf("expected empty search error, got %v", err)
+	}
+}
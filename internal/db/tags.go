@@ -0,0 +1,152 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// TaggedItem is a todo, project, or area that carries a tag, with all of its
+// tag titles.
+type TaggedItem struct {
+	UUID  string   `json:"uuid"`
+	Type  string   `json:"type"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// TagByInput returns a tag by UUID or title (case-insensitive).
+func (s *Store) TagByInput(input string) (*Tag, error) {
+	id, err := resolveTagID(s.conn, input, false)
+	if err != nil {
+		return nil, err
+	}
+	var tag Tag
+	var shortcut sql.NullString
+	var parent sql.NullString
+	if err := s.conn.QueryRow("SELECT uuid, title, shortcut, parent FROM TMTag WHERE uuid = ?", id).Scan(&tag.UUID, &tag.Title, &shortcut, &parent); err != nil {
+		return nil, err
+	}
+	tag.Shortcut = shortcut.String
+	tag.ParentID = parent.String
+	return &tag, nil
+}
+
+// TaggedItems returns the todos, projects, and areas tagged with tagID.
+// Todos and projects come from TMTaskTag; areas from TMAreaTag when the
+// database has it.
+func (s *Store) TaggedItems(tagID string) ([]TaggedItem, error) {
+	items, err := s.queryTaggedItems(
+		`SELECT t.uuid, t.type, t.title,
+		 (SELECT group_concat(title, '`+tagSeparator+`') FROM (
+		   SELECT tag.title AS title FROM TMTag tag
+		   JOIN TMTaskTag x ON x.tags = tag.uuid
+		   WHERE x.tasks = t.uuid ORDER BY tag.title COLLATE NOCASE))
+		 FROM TMTask t
+		 JOIN TMTaskTag tt ON tt.tasks = t.uuid
+		 WHERE tt.tags = ? AND t.type IN (?, ?)
+		 ORDER BY t.type, t.title COLLATE NOCASE`,
+		tagID, TaskTypeTodo, TaskTypeProject,
+	)
+	if err != nil {
+		return nil, err
+	}
+	hasAreaTags, err := s.hasAreaTags()
+	if err != nil {
+		return nil, err
+	}
+	if !hasAreaTags {
+		return items, nil
+	}
+	areas, err := s.queryTaggedItems(
+		`SELECT a.uuid, -1, a.title,
+		 (SELECT group_concat(title, '`+tagSeparator+`') FROM (
+		   SELECT tag.title AS title FROM TMTag tag
+		   JOIN TMAreaTag x ON x.tags = tag.uuid
+		   WHERE x.areas = a.uuid ORDER BY tag.title COLLATE NOCASE))
+		 FROM TMArea a
+		 JOIN TMAreaTag at ON at.areas = a.uuid
+		 WHERE at.tags = ?
+		 ORDER BY a.title COLLATE NOCASE`,
+		tagID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return append(items, areas...), nil
+}
+
+// AreaTags returns the titles of an area's tags, sorted. Databases without
+// TMAreaTag have no area tags.
+func (s *Store) AreaTags(areaID string) ([]string, error) {
+	hasAreaTags, err := s.hasAreaTags()
+	if err != nil || !hasAreaTags {
+		return nil, err
+	}
+	rows, err := s.conn.Query(
+		`SELECT tag.title FROM TMTag tag
+		 JOIN TMAreaTag x ON x.tags = tag.uuid
+		 WHERE x.areas = ? ORDER BY tag.title COLLATE NOCASE`,
+		areaID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tags := []string{}
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, err
+		}
+		tags = append(tags, title)
+	}
+	return tags, rows.Err()
+}
+
+func (s *Store) hasAreaTags() (bool, error) {
+	var count int
+	if err := s.conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'TMAreaTag'").Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// tagFilterClause returns the SQL condition matching tasks tagged with the
+// bound tag ID, or with any of its descendant tags when recursive is set.
+func tagFilterClause(recursive bool) string {
+	if !recursive {
+		return "EXISTS (SELECT 1 FROM TMTaskTag tt WHERE tt.tasks = t.uuid AND tt.tags = ?)"
+	}
+	return "EXISTS (SELECT 1 FROM TMTaskTag tt WHERE tt.tasks = t.uuid AND tt.tags IN (" +
+		"WITH RECURSIVE subtags(uuid) AS (SELECT ? UNION SELECT tag.uuid FROM TMTag tag JOIN subtags ON tag.parent = subtags.uuid) " +
+		"SELECT uuid FROM subtags))"
+}
+
+func (s *Store) queryTaggedItems(query string, args ...any) ([]TaggedItem, error) {
+	rows, err := s.conn.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []TaggedItem
+	for rows.Next() {
+		var item TaggedItem
+		var kind int
+		var title sql.NullString
+		var tags sql.NullString
+		if err := rows.Scan(&item.UUID, &kind, &title, &tags); err != nil {
+			return nil, err
+		}
+		item.Type = "area"
+		if kind >= 0 {
+			item.Type = taskTypeLabel(kind)
+		}
+		item.Title = title.String
+		if tags.Valid && tags.String != "" {
+			item.Tags = strings.Split(tags.String, tagSeparator)
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestTagByInputAndTaggedItems(t *testing.T) {
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+
+	if err := seedTestDB(conn); err != nil {
+		t.Fatalf("seed db: %v", err)
+	}
+
+	store := &Store{conn: conn, path: ":memory:"}
+
+	tag, err := store.TagByInput("URGENT")
+	if err != nil {
+		t.Fatalf("tag by input: %v", err)
+	}
+	if tag.UUID != "TAG1" {
+		t.Fatalf("unexpected tag: %#v", tag)
+	}
+
+	items, err := store.TaggedItems(tag.UUID)
+	if err != nil {
+		t.Fatalf("tagged items: %v", err)
+	}
+	if len(items) != 1 || items[0].UUID != "T1" || items[0].Type != "to-do" {
+		t.Fatalf("unexpected tagged items: %#v", items)
+	}
+	if len(items[0].Tags) != 1 || items[0].Tags[0] != "urgent" {
+		t.Fatalf("unexpected item tags: %#v", items[0].Tags)
+	}
+
+	if _, err := conn.Exec(`CREATE TABLE TMAreaTag (areas TEXT NOT NULL, tags TEXT NOT NULL);`); err != nil {
+		t.Fatalf("create area tags: %v", err)
+	}
+	if _, err := conn.Exec(`INSERT INTO TMAreaTag (areas, tags) VALUES ('A1', 'TAG1');`); err != nil {
+		t.Fatalf("insert area tag: %v", err)
+	}
+	items, err = store.TaggedItems(tag.UUID)
+	if err != nil {
+		t.Fatalf("tagged items: %v", err)
+	}
+	if len(items) != 2 || items[1].UUID != "A1" || items[1].Type != "area" {
+		t.Fatalf("expected area to be included: %#v", items)
+	}
+	if tags, err := store.AreaTags("A1"); err != nil || len(tags) != 1 || tags[0] != "urgent" {
+		t.Fatalf("unexpected area tags: %#v, %v", tags, err)
+	}
+}
+
+func TestTasksTagRecursive(t *testing.T) {
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer conn.Close()
+
+	if err := seedTestDB(conn); err != nil {
+		t.Fatalf("seed db: %v", err)
+	}
+	for _, stmt := range []string{
+		`INSERT INTO TMTag (uuid, title) VALUES ('ROOT', 'work');`,
+		`UPDATE TMTag SET parent = 'MID' WHERE uuid = 'TAG1';`,
+		`INSERT INTO TMTag (uuid, title, parent) VALUES ('MID', 'clients', 'ROOT');`,
+	} {
+		if _, err := conn.Exec(stmt); err != nil {
+			t.Fatalf("seed tags: %v", err)
+		}
+	}
+
+	store := &Store{conn: conn, path: ":memory:"}
+
+	tasks, err := store.Tasks(TaskFilter{TagID: "ROOT"})
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("expected no direct matches, got %d", len(tasks))
+	}
+
+	tasks, err = store.Tasks(TaskFilter{TagID: "ROOT", TagRecursive: true})
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].UUID != "T1" {
+		t.Fatalf("expected T1 through grandchild tag, got %#v", tasks)
+	}
+}
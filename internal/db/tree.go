@@ -153,6 +153,11 @@ func (s *Store) projectChildren(projectID string, filter TaskFilter) ([]TreeItem
 }
 
 func (s *Store) queryTaskItems(taskType int, where string, args []any, filter TaskFilter, order string) ([]TreeItem, error) {
+	schema, err := s.requireColumns((*Schema).taskQueryColumns)
+	if err != nil {
+		return nil, err
+	}
+
 	var b strings.Builder
 	b.WriteString("SELECT t.uuid, t.title, t.status, t.trashed ")
 	b.WriteString("FROM TMTask t ")
@@ -188,13 +193,14 @@ func (s *Store) queryTaskItems(taskType int, where string, args []any, filter Ta
 		params = append(params, filter.ProjectID)
 	}
 	if filter.TagID != "" {
-		b.WriteString(" AND EXISTS (SELECT 1 FROM TMTaskTag tt WHERE tt.tasks = t.uuid AND tt.tags = ?)")
+		b.WriteString(" AND " + tagFilterClause(filter.TagRecursive))
 		params = append(params, filter.TagID)
 	}
+	recurrence := "t." + schema.RepeatColumn("recurrenceRule")
 	if filter.RepeatingOnly {
-		b.WriteString(" AND t.rt1_recurrenceRule IS NOT NULL")
+		b.WriteString(" AND " + recurrence + " IS NOT NULL")
 	} else if !filter.IncludeRepeating {
-		b.WriteString(" AND t.rt1_recurrenceRule IS NULL")
+		b.WriteString(" AND " + recurrence + " IS NULL")
 	}
 
 	if order == "" {
@@ -229,10 +235,15 @@ func taskToTree(task Task) TreeItem {
 	status := task.Status
 	trashed := task.Trashed
 	return TreeItem{
-		UUID:    task.UUID,
-		Type:    "to-do",
-		Title:   task.Title,
-		Status:  &status,
-		Trashed: &trashed,
+		UUID:      task.UUID,
+		Type:      "to-do",
+		Title:     task.Title,
+		Status:    &status,
+		Trashed:   &trashed,
+		StartDate: task.StartDate,
+		Deadline:  task.Deadline,
+		StopDate:  task.StopDate,
+		Tags:      task.Tags,
+		Checklist: task.Checklist,
 	}
 }
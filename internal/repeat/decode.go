@@ -0,0 +1,229 @@
+package repeat
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"howett.net/plist"
+)
+
+// DecodeRule parses an rt1_recurrenceRule plist back into a Spec. The anchor
+// is taken from the rule's start reference and aligned to its first offset so
+// that the weekday or day of month matches what Things will schedule. Offsets
+// the anchor cannot express (several weekdays, nth weekdays, the last day of
+// the month) are returned in Spec.On.
+func DecodeRule(data []byte) (Spec, error) {
+	if len(data) == 0 {
+		return Spec{}, fmt.Errorf("empty recurrence rule")
+	}
+	var rule map[string]any
+	if _, err := plist.Unmarshal(data, &rule); err != nil {
+		return Spec{}, fmt.Errorf("decode recurrence rule: %w", err)
+	}
+
+	unitRaw, ok := plistInt(rule["fu"])
+	if !ok {
+		return Spec{}, fmt.Errorf("recurrence rule has no unit")
+	}
+	unit, err := unitFromValue(unitRaw)
+	if err != nil {
+		return Spec{}, err
+	}
+
+	spec := Spec{Unit: unit, Every: 1, Mode: ModeAfterCompletion}
+	if every, ok := plistInt(rule["fa"]); ok && every > 0 {
+		spec.Every = every
+	}
+	if mode, ok := plistInt(rule["tp"]); ok && mode == 0 {
+		spec.Mode = ModeSchedule
+	}
+
+	anchorValue, ok := plistFloat(rule["ia"])
+	if !ok {
+		anchorValue, ok = plistFloat(rule["sr"])
+	}
+	if ok {
+		spec.Anchor = normalizeDate(time.Unix(int64(anchorValue), 0).In(time.Local))
+	}
+	if raw, ok := rule["of"].([]any); ok && len(raw) > 0 {
+		offsets := make([]map[string]any, 0, len(raw))
+		for _, item := range raw {
+			if offset, ok := item.(map[string]any); ok {
+				offsets = append(offsets, offset)
+			}
+		}
+		spec.On = decodeOffsets(unit, offsets)
+		if len(spec.On) == 0 && len(offsets) > 0 && !spec.Anchor.IsZero() {
+			spec.Anchor = alignAnchor(spec.Anchor, unit, offsets[0])
+		}
+	}
+
+	if end, ok := plistFloat(rule["ed"]); ok && end < farFutureEpochSeconds() {
+		endDate := normalizeDate(time.Unix(int64(end), 0).In(time.Local))
+		spec.EndDate = &endDate
+	}
+	if ts, ok := plistInt(rule["ts"]); ok && ts != 0 {
+		offset := -ts
+		spec.DeadlineOffset = &offset
+	}
+	return spec, nil
+}
+
+// Describe renders the spec in a short human-readable form, e.g.
+// "every 2 weeks on Monday, after completion, until 2027-01-01".
+func (s Spec) Describe() string {
+	every := s.Every
+	if every <= 0 {
+		every = 1
+	}
+	name := unitName(s.Unit)
+	var b strings.Builder
+	if every == 1 {
+		b.WriteString("every " + name)
+	} else {
+		fmt.Fprintf(&b, "every %d %ss", every, name)
+	}
+	if len(s.On) > 0 {
+		b.WriteString(" " + describeOffsets(s.Unit, s.On))
+	} else if !s.Anchor.IsZero() {
+		switch s.Unit {
+		case UnitWeek:
+			b.WriteString(" on " + s.Anchor.Weekday().String())
+		case UnitMonth:
+			fmt.Fprintf(&b, " on day %d", s.Anchor.Day())
+		case UnitYear:
+			fmt.Fprintf(&b, " on %s %d", s.Anchor.Month().String(), s.Anchor.Day())
+		}
+	}
+	if s.Mode == ModeAfterCompletion {
+		b.WriteString(", after completion")
+	}
+	if s.DeadlineOffset != nil {
+		if *s.DeadlineOffset == 1 {
+			b.WriteString(", deadline after 1 day")
+		} else {
+			fmt.Fprintf(&b, ", deadline after %d days", *s.DeadlineOffset)
+		}
+	}
+	if s.EndDate != nil {
+		b.WriteString(", until " + s.EndDate.Format("2006-01-02"))
+	}
+	return b.String()
+}
+
+func decodeOffsets(unit Unit, offsets []map[string]any) []Offset {
+	switch unit {
+	case UnitWeek:
+		if len(offsets) < 2 {
+			return nil
+		}
+		var on []Offset
+		for _, offset := range offsets {
+			if weekday, ok := plistInt(offset["wd"]); ok && weekday >= 0 && weekday <= 6 {
+				on = append(on, Offset{Weekday: time.Weekday(weekday)})
+			}
+		}
+		return normalizeWeekdays(on)
+	case UnitMonth:
+		offset := offsets[0]
+		if day, ok := plistInt(offset["dy"]); ok && day == -1 {
+			return []Offset{{LastDay: true}}
+		}
+		nth, hasNth := plistInt(offset["wo"])
+		weekday, hasWeekday := plistInt(offset["wd"])
+		if hasNth && hasWeekday && nth != 0 && weekday >= 0 && weekday <= 6 {
+			return []Offset{{Weekday: time.Weekday(weekday), Nth: nth}}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+func alignAnchor(anchor time.Time, unit Unit, offset map[string]any) time.Time {
+	switch unit {
+	case UnitWeek:
+		weekday, ok := plistInt(offset["wd"])
+		if !ok || weekday < 0 || weekday > 6 {
+			return anchor
+		}
+		return anchor.AddDate(0, 0, (weekday-int(anchor.Weekday())+7)%7)
+	case UnitMonth:
+		day, ok := plistInt(offset["dy"])
+		if !ok || day < 0 {
+			return anchor
+		}
+		return dateWithDay(anchor.Year(), anchor.Month(), day+1, anchor.Location())
+	case UnitYear:
+		day, ok := plistInt(offset["dy"])
+		if !ok || day < 0 {
+			return anchor
+		}
+		month := int(anchor.Month())
+		if value, ok := plistInt(offset["mo"]); ok && value >= 0 && value < 12 {
+			month = value + 1
+		}
+		return dateWithDay(anchor.Year(), time.Month(month), day+1, anchor.Location())
+	default:
+		return anchor
+	}
+}
+
+func unitFromValue(value int) (Unit, error) {
+	switch value {
+	case 16:
+		return UnitDay, nil
+	case 256:
+		return UnitWeek, nil
+	case 8:
+		return UnitMonth, nil
+	case 4:
+		return UnitYear, nil
+	default:
+		return UnitDay, fmt.Errorf("unsupported repeat unit value %d", value)
+	}
+}
+
+func unitName(unit Unit) string {
+	switch unit {
+	case UnitWeek:
+		return "week"
+	case UnitMonth:
+		return "month"
+	case UnitYear:
+		return "year"
+	default:
+		return "day"
+	}
+}
+
+func plistInt(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+func plistFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,101 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+
+	"howett.net/plist"
+)
+
+func TestDecodeRuleRoundTrip(t *testing.T) {
+	anchor := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	end := time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local)
+	offset := 3
+	spec := Spec{
+		Mode:           ModeAfterCompletion,
+		Unit:           UnitWeek,
+		Every:          2,
+		Anchor:         anchor,
+		EndDate:        &end,
+		DeadlineOffset: &offset,
+	}
+	update, err := BuildUpdate(spec)
+	if err != nil {
+		t.Fatalf("BuildUpdate failed: %v", err)
+	}
+
+	decoded, err := DecodeRule(update.RecurrenceRule)
+	if err != nil {
+		t.Fatalf("DecodeRule failed: %v", err)
+	}
+	if decoded.Mode != ModeAfterCompletion || decoded.Unit != UnitWeek || decoded.Every != 2 {
+		t.Fatalf("unexpected spec: %+v", decoded)
+	}
+	if !decoded.Anchor.Equal(anchor) {
+		t.Fatalf("anchor mismatch: got %v want %v", decoded.Anchor, anchor)
+	}
+	if decoded.EndDate == nil || !decoded.EndDate.Equal(end) {
+		t.Fatalf("end date mismatch: %v", decoded.EndDate)
+	}
+	if decoded.DeadlineOffset == nil || *decoded.DeadlineOffset != 3 {
+		t.Fatalf("deadline offset mismatch: %v", decoded.DeadlineOffset)
+	}
+
+	want := "every 2 weeks on Monday, after completion, deadline after 3 days, until 2027-01-01"
+	if got := decoded.Describe(); got != want {
+		t.Fatalf("describe mismatch:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestDecodeRuleScheduleWithoutEnd(t *testing.T) {
+	anchor := time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local)
+	update, err := BuildUpdate(Spec{Mode: ModeSchedule, Unit: UnitMonth, Every: 1, Anchor: anchor})
+	if err != nil {
+		t.Fatalf("BuildUpdate failed: %v", err)
+	}
+	decoded, err := DecodeRule(update.RecurrenceRule)
+	if err != nil {
+		t.Fatalf("DecodeRule failed: %v", err)
+	}
+	if decoded.EndDate != nil || decoded.DeadlineOffset != nil {
+		t.Fatalf("unexpected end/deadline: %+v", decoded)
+	}
+	if got := decoded.Describe(); got != "every month on day 15" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
+
+func TestDecodeRuleAlignsAnchorToOffset(t *testing.T) {
+	anchor := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	rule := map[string]any{
+		"fa": 1,
+		"fu": 4,
+		"ia": float64(anchor.Unix()),
+		"of": []map[string]int{{"dy": 24, "mo": 11}},
+		"tp": 0,
+	}
+	encoded, err := plist.Marshal(rule, plist.XMLFormat)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	decoded, err := DecodeRule(encoded)
+	if err != nil {
+		t.Fatalf("DecodeRule failed: %v", err)
+	}
+	if got := decoded.Describe(); got != "every year on December 25" {
+		t.Fatalf("unexpected description %q", got)
+	}
+}
+
+func TestDecodeRuleRejectsInvalid(t *testing.T) {
+	if _, err := DecodeRule(nil); err == nil {
+		t.Fatalf("expected error for empty rule")
+	}
+	encoded, err := plist.Marshal(map[string]any{"fu": 2}, plist.XMLFormat)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if _, err := DecodeRule(encoded); err == nil {
+		t.Fatalf("expected error for unknown unit")
+	}
+}
@@ -0,0 +1,102 @@
+package repeat
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// Occurrence is one projected instance of a repeating template. Start is the
+// day the copy appears; Deadline is set when the rule adds deadlines, in which
+// case the repeat date is the deadline and Start falls DeadlineOffset days
+// earlier.
+type Occurrence struct {
+	Start    time.Time
+	Deadline *time.Time
+}
+
+// Occurrences projects the next n instances of spec whose repeat date is on
+// or after from, stopping at the rule's end date. Scheduled rules are exact;
+// after-completion rules assume each copy is completed on the day it starts.
+func Occurrences(spec Spec, from time.Time, n int) ([]Occurrence, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	if spec.Every <= 0 {
+		return nil, fmt.Errorf("repeat interval must be >= 1")
+	}
+	if err := validateOffsets(spec.Unit, spec.On); err != nil {
+		return nil, err
+	}
+	anchor := normalizeDate(spec.Anchor)
+	cursor := normalizeDate(from)
+	if cursor.Before(anchor) {
+		cursor = anchor
+	}
+	var end *time.Time
+	if spec.EndDate != nil {
+		value := normalizeDate(*spec.EndDate)
+		end = &value
+	}
+
+	occurrences := make([]Occurrence, 0, n)
+	var date time.Time
+	for len(occurrences) < n {
+		var err error
+		if spec.Mode == ModeAfterCompletion && len(occurrences) > 0 {
+			date = advance(date, spec.Unit, spec.Every)
+		} else {
+			date, err = nextScheduleDate(anchor, cursor, spec.Unit, spec.Every, spec.On)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if end != nil && date.After(*end) {
+			break
+		}
+		occurrence := Occurrence{Start: date}
+		if spec.DeadlineOffset != nil {
+			deadline := date
+			occurrence.Deadline = &deadline
+			occurrence.Start = date.AddDate(0, 0, -*spec.DeadlineOffset)
+		}
+		occurrences = append(occurrences, occurrence)
+		cursor = date.AddDate(0, 0, 1)
+	}
+	return occurrences, nil
+}
+
+func advance(date time.Time, unit Unit, every int) time.Time {
+	switch unit {
+	case UnitWeek:
+		return date.AddDate(0, 0, 7*every)
+	case UnitMonth:
+		return addMonths(date, every)
+	case UnitYear:
+		return addYears(date, every)
+	default:
+		return date.AddDate(0, 0, every)
+	}
+}
+
+// SkipNext moves a template's next instance forward by one interval. next is
+// the template's current rt1_nextInstanceStartDate; the returned value is the
+// date of the instance after it, in the same encoding.
+func SkipNext(spec Spec, next int) (int, error) {
+	if spec.Every <= 0 {
+		return 0, fmt.Errorf("repeat interval must be >= 1")
+	}
+	date, ok := db.ThingsDate(int64(next))
+	if !ok {
+		return 0, fmt.Errorf("invalid Things date value %d", next)
+	}
+	if spec.Mode == ModeAfterCompletion {
+		return thingsDateValue(advance(date, spec.Unit, spec.Every)), nil
+	}
+	following, err := nextScheduleDate(normalizeDate(spec.Anchor), date.AddDate(0, 0, 1), spec.Unit, spec.Every, spec.On)
+	if err != nil {
+		return 0, err
+	}
+	return thingsDateValue(following), nil
+}
@@ -0,0 +1,94 @@
+package repeat
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOccurrencesScheduleUntilEndDate(t *testing.T) {
+	end := time.Date(2026, 2, 20, 0, 0, 0, 0, time.Local)
+	spec := Spec{
+		Mode:    ModeSchedule,
+		Unit:    UnitWeek,
+		Every:   2,
+		Anchor:  time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local),
+		EndDate: &end,
+		On:      []Offset{{Weekday: time.Monday}, {Weekday: time.Thursday}},
+	}
+	got, err := Occurrences(spec, time.Date(2026, 1, 6, 9, 30, 0, 0, time.Local), 10)
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	want := []string{"2026-01-08", "2026-01-19", "2026-01-22", "2026-02-02", "2026-02-05", "2026-02-16", "2026-02-19"}
+	assertOccurrenceStarts(t, got, want)
+}
+
+func TestOccurrencesWithDeadlineOffset(t *testing.T) {
+	offset := 3
+	spec := Spec{
+		Mode:           ModeSchedule,
+		Unit:           UnitMonth,
+		Every:          1,
+		Anchor:         time.Date(2026, 1, 15, 0, 0, 0, 0, time.Local),
+		DeadlineOffset: &offset,
+	}
+	got, err := Occurrences(spec, time.Date(2026, 1, 16, 0, 0, 0, 0, time.Local), 2)
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	assertOccurrenceStarts(t, got, []string{"2026-02-12", "2026-03-12"})
+	if got[0].Deadline == nil || got[0].Deadline.Format("2006-01-02") != "2026-02-15" {
+		t.Fatalf("unexpected deadline: %v", got[0].Deadline)
+	}
+}
+
+func TestOccurrencesAfterCompletion(t *testing.T) {
+	spec := Spec{
+		Mode:   ModeAfterCompletion,
+		Unit:   UnitMonth,
+		Every:  1,
+		Anchor: time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local),
+	}
+	got, err := Occurrences(spec, time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local), 3)
+	if err != nil {
+		t.Fatalf("Occurrences failed: %v", err)
+	}
+	assertOccurrenceStarts(t, got, []string{"2026-01-31", "2026-02-28", "2026-03-28"})
+}
+
+func assertOccurrenceStarts(t *testing.T, got []Occurrence, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d occurrences, got %d", len(want), len(got))
+	}
+	for i, occurrence := range got {
+		if value := occurrence.Start.Format("2006-01-02"); value != want[i] {
+			t.Fatalf("occurrence %d: got %s want %s", i, value, want[i])
+		}
+	}
+}
+
+func TestSkipNext(t *testing.T) {
+	anchor := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	weekly := Spec{Mode: ModeSchedule, Unit: UnitWeek, Every: 1, Anchor: anchor, On: []Offset{{Weekday: time.Monday}, {Weekday: time.Friday}}}
+	got, err := SkipNext(weekly, thingsDateValue(time.Date(2026, 1, 9, 0, 0, 0, 0, time.Local)))
+	if err != nil {
+		t.Fatalf("SkipNext failed: %v", err)
+	}
+	if want := thingsDateValue(time.Date(2026, 1, 12, 0, 0, 0, 0, time.Local)); got != want {
+		t.Fatalf("weekly skip: got %d want %d", got, want)
+	}
+
+	monthly := Spec{Mode: ModeAfterCompletion, Unit: UnitMonth, Every: 2, Anchor: anchor}
+	got, err = SkipNext(monthly, thingsDateValue(time.Date(2026, 3, 5, 0, 0, 0, 0, time.Local)))
+	if err != nil {
+		t.Fatalf("SkipNext failed: %v", err)
+	}
+	if want := thingsDateValue(time.Date(2026, 5, 5, 0, 0, 0, 0, time.Local)); got != want {
+		t.Fatalf("monthly skip: got %d want %d", got, want)
+	}
+
+	if _, err := SkipNext(monthly, 0); err == nil {
+		t.Fatalf("expected error for invalid date value")
+	}
+}
@@ -0,0 +1,215 @@
+package repeat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Offset pins a repeat to a position within each period: a weekday for weekly
+// rules, or an nth weekday or the last day for monthly rules.
+type Offset struct {
+	Weekday time.Weekday
+	// Nth is the week of the month (1-5, or -1 for the last one). It is zero
+	// for weekly offsets.
+	Nth int
+	// LastDay repeats on the final day of the month.
+	LastDay bool
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+var ordinalNames = map[string]int{
+	"1st": 1, "first": 1,
+	"2nd": 2, "second": 2,
+	"3rd": 3, "third": 3,
+	"4th": 4, "fourth": 4,
+	"5th": 5, "fifth": 5,
+	"last": -1,
+}
+
+// ParseOn parses a --repeat-on value for the given unit. Weekly rules take a
+// comma-separated weekday list (mon,wed,fri); monthly rules take a single
+// nth weekday (2nd-tue, last-fri) or last-day.
+func ParseOn(input string, unit Unit) ([]Offset, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return nil, nil
+	}
+	switch unit {
+	case UnitWeek:
+		var offsets []Offset
+		for _, part := range strings.Split(input, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			weekday, ok := weekdayNames[part]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday %q in repeat-on", part)
+			}
+			offsets = append(offsets, Offset{Weekday: weekday})
+		}
+		if len(offsets) == 0 {
+			return nil, fmt.Errorf("repeat-on requires at least one weekday")
+		}
+		return normalizeWeekdays(offsets), nil
+	case UnitMonth:
+		if strings.Contains(input, ",") {
+			return nil, fmt.Errorf("monthly repeat-on takes a single value")
+		}
+		if input == "last-day" {
+			return []Offset{{LastDay: true}}, nil
+		}
+		ordinal, day, ok := strings.Cut(input, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid monthly repeat-on %q (use e.g. 2nd-tue, last-fri, or last-day)", input)
+		}
+		nth, ok := ordinalNames[ordinal]
+		if !ok {
+			return nil, fmt.Errorf("invalid ordinal %q in repeat-on", ordinal)
+		}
+		weekday, ok := weekdayNames[day]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q in repeat-on", day)
+		}
+		return []Offset{{Weekday: weekday, Nth: nth}}, nil
+	default:
+		return nil, fmt.Errorf("repeat-on is only supported for week and month repeats")
+	}
+}
+
+func validateOffsets(unit Unit, offsets []Offset) error {
+	if len(offsets) == 0 {
+		return nil
+	}
+	switch unit {
+	case UnitWeek:
+		for _, offset := range offsets {
+			if offset.Nth != 0 || offset.LastDay {
+				return fmt.Errorf("weekly repeats only take weekdays")
+			}
+		}
+		return nil
+	case UnitMonth:
+		if len(offsets) != 1 {
+			return fmt.Errorf("monthly repeats take a single repeat-on value")
+		}
+		offset := offsets[0]
+		if offset.LastDay {
+			return nil
+		}
+		if offset.Nth == 0 || offset.Nth < -1 || offset.Nth > 5 {
+			return fmt.Errorf("monthly repeat-on needs an ordinal (1st-5th or last)")
+		}
+		return nil
+	default:
+		return fmt.Errorf("repeat-on is only supported for week and month repeats")
+	}
+}
+
+func normalizeWeekdays(offsets []Offset) []Offset {
+	seen := map[time.Weekday]bool{}
+	result := make([]Offset, 0, len(offsets))
+	for _, offset := range offsets {
+		if seen[offset.Weekday] {
+			continue
+		}
+		seen[offset.Weekday] = true
+		result = append(result, Offset{Weekday: offset.Weekday})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Weekday < result[j].Weekday })
+	return result
+}
+
+func encodeOffsets(offsets []Offset) []map[string]int {
+	encoded := make([]map[string]int, 0, len(offsets))
+	for _, offset := range offsets {
+		switch {
+		case offset.LastDay:
+			encoded = append(encoded, map[string]int{"dy": -1})
+		case offset.Nth != 0:
+			encoded = append(encoded, map[string]int{"wd": int(offset.Weekday), "wo": offset.Nth})
+		default:
+			encoded = append(encoded, map[string]int{"wd": int(offset.Weekday)})
+		}
+	}
+	return encoded
+}
+
+// monthOffsetDate returns the date the offset falls on in the given month, or
+// false when the month has no such day (e.g. a 5th Monday).
+func monthOffsetDate(year int, month time.Month, offset Offset, loc *time.Location) (time.Time, bool) {
+	last := daysInMonth(year, month, loc)
+	if offset.LastDay {
+		return time.Date(year, month, last, 0, 0, 0, 0, loc), true
+	}
+	if offset.Nth == -1 {
+		lastDate := time.Date(year, month, last, 0, 0, 0, 0, loc)
+		back := (int(lastDate.Weekday()) - int(offset.Weekday) + 7) % 7
+		return lastDate.AddDate(0, 0, -back), true
+	}
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	day := 1 + (int(offset.Weekday)-int(first.Weekday())+7)%7 + (offset.Nth-1)*7
+	if day > last {
+		return time.Time{}, false
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc), true
+}
+
+func nextWeekdays(anchor, start time.Time, every int, offsets []Offset) time.Time {
+	days := map[time.Weekday]bool{}
+	for _, offset := range offsets {
+		days[offset.Weekday] = true
+	}
+	weekStart := anchor.AddDate(0, 0, -int(anchor.Weekday()))
+	candidate := start
+	for i := 0; i < 7*(every+1); i++ {
+		weeks := daysBetween(weekStart, candidate) / 7
+		if days[candidate.Weekday()] && weeks%every == 0 {
+			return candidate
+		}
+		candidate = candidate.AddDate(0, 0, 1)
+	}
+	return candidate
+}
+
+func nextMonthOffset(anchor, start time.Time, every int, offset Offset) (time.Time, error) {
+	steps := monthsBetween(anchor, start) / every
+	for i := 0; i < 64; i++ {
+		base := time.Date(anchor.Year(), anchor.Month()+time.Month((steps+i)*every), 1, 0, 0, 0, 0, anchor.Location())
+		date, ok := monthOffsetDate(base.Year(), base.Month(), offset, anchor.Location())
+		if ok && !date.Before(start) {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no matching date for repeat-on")
+}
+
+func describeOffsets(unit Unit, offsets []Offset) string {
+	if unit == UnitWeek {
+		names := make([]string, 0, len(offsets))
+		for _, offset := range offsets {
+			names = append(names, offset.Weekday.String())
+		}
+		return "on " + strings.Join(names, ", ")
+	}
+	offset := offsets[0]
+	if offset.LastDay {
+		return "on the last day"
+	}
+	if offset.Nth == -1 {
+		return "on the last " + offset.Weekday.String()
+	}
+	ordinals := map[int]string{1: "1st", 2: "2nd", 3: "3rd", 4: "4th", 5: "5th"}
+	return "on the " + ordinals[offset.Nth] + " " + offset.Weekday.String()
+}
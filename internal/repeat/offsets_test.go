@@ -0,0 +1,187 @@
+package repeat
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"howett.net/plist"
+)
+
+func TestParseOn(t *testing.T) {
+	cases := []struct {
+		input string
+		unit  Unit
+		want  []Offset
+	}{
+		{"fri,mon,wed,mon", UnitWeek, []Offset{{Weekday: time.Monday}, {Weekday: time.Wednesday}, {Weekday: time.Friday}}},
+		{"2nd-tue", UnitMonth, []Offset{{Weekday: time.Tuesday, Nth: 2}}},
+		{"last-fri", UnitMonth, []Offset{{Weekday: time.Friday, Nth: -1}}},
+		{"first-monday", UnitMonth, []Offset{{Weekday: time.Monday, Nth: 1}}},
+		{"last-day", UnitMonth, []Offset{{LastDay: true}}},
+	}
+	for _, tc := range cases {
+		got, err := ParseOn(tc.input, tc.unit)
+		if err != nil {
+			t.Fatalf("ParseOn(%q) failed: %v", tc.input, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("ParseOn(%q) = %+v, want %+v", tc.input, got, tc.want)
+		}
+	}
+
+	invalid := []struct {
+		input string
+		unit  Unit
+	}{
+		{"mon,funday", UnitWeek},
+		{"2nd-tue", UnitWeek},
+		{"6th-mon", UnitMonth},
+		{"2nd-tue,last-fri", UnitMonth},
+		{"mon", UnitDay},
+	}
+	for _, tc := range invalid {
+		if _, err := ParseOn(tc.input, tc.unit); err == nil {
+			t.Fatalf("expected ParseOn(%q) to fail", tc.input)
+		}
+	}
+}
+
+// TestThingsRulesRoundTrip decodes rules in Things' plist layout (see
+// testdata) and checks that re-encoding them keeps Things' offsets.
+func TestThingsRulesRoundTrip(t *testing.T) {
+	cases := []struct {
+		file     string
+		describe string
+	}{
+		// rt1_recurrenceRule of "Repeating To-Do" in integration/fixtures/main.sqlite.
+		{"weekly-sunday.plist", "every week on Sunday, after completion, deadline after 99 days"},
+		// The derived-* rules are weekly-sunday.plist with only fu, of, tp,
+		// and ts changed: the reference database has no rule using several
+		// weekdays, "wo", or "dy": -1. Replace them with exports from Things
+		// when one is available.
+		{"derived-weekly-mon-wed-fri.plist", "every week on Monday, Wednesday, Friday"},
+		{"derived-monthly-2nd-tue.plist", "every month on the 2nd Tuesday"},
+		{"derived-monthly-last-day.plist", "every month on the last day"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", tc.file))
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+			spec, err := DecodeRule(data)
+			if err != nil {
+				t.Fatalf("DecodeRule failed: %v", err)
+			}
+			if got := spec.Describe(); got != tc.describe {
+				t.Fatalf("describe mismatch: got %q want %q", got, tc.describe)
+			}
+
+			update, err := BuildUpdate(spec)
+			if err != nil {
+				t.Fatalf("BuildUpdate failed: %v", err)
+			}
+			if got, want := ruleOffsets(t, update.RecurrenceRule), ruleOffsets(t, data); !reflect.DeepEqual(got, want) {
+				t.Fatalf("offsets mismatch: got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestOffsetsRoundTrip covers --repeat-on offsets that no exported fixture
+// exercises yet: each must decode back to the spec it was built from.
+func TestOffsetsRoundTrip(t *testing.T) {
+	anchor := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	cases := []struct {
+		spec     Spec
+		describe string
+	}{
+		{Spec{Unit: UnitWeek, Every: 1, On: []Offset{{Weekday: time.Monday}, {Weekday: time.Wednesday}, {Weekday: time.Friday}}}, "every week on Monday, Wednesday, Friday"},
+		{Spec{Unit: UnitMonth, Every: 1, On: []Offset{{Weekday: time.Tuesday, Nth: 2}}}, "every month on the 2nd Tuesday"},
+		{Spec{Unit: UnitMonth, Every: 2, On: []Offset{{Weekday: time.Friday, Nth: -1}}}, "every 2 months on the last Friday"},
+		{Spec{Unit: UnitMonth, Every: 1, On: []Offset{{LastDay: true}}}, "every month on the last day"},
+	}
+	for _, tc := range cases {
+		tc.spec.Mode = ModeSchedule
+		tc.spec.Anchor = anchor
+		update, err := BuildUpdate(tc.spec)
+		if err != nil {
+			t.Fatalf("BuildUpdate(%s) failed: %v", tc.describe, err)
+		}
+		decoded, err := DecodeRule(update.RecurrenceRule)
+		if err != nil {
+			t.Fatalf("DecodeRule(%s) failed: %v", tc.describe, err)
+		}
+		if !reflect.DeepEqual(decoded.On, tc.spec.On) {
+			t.Fatalf("offsets mismatch: got %+v want %+v", decoded.On, tc.spec.On)
+		}
+		if got := decoded.Describe(); got != tc.describe {
+			t.Fatalf("describe mismatch: got %q want %q", got, tc.describe)
+		}
+	}
+}
+
+func TestNextScheduleDateWithOffsets(t *testing.T) {
+	anchor := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2026, month, d, 0, 0, 0, 0, time.Local)
+	}
+	weekdays := []Offset{{Weekday: time.Monday}, {Weekday: time.Wednesday}, {Weekday: time.Friday}}
+	cases := []struct {
+		name  string
+		unit  Unit
+		every int
+		on    []Offset
+		start time.Time
+		want  time.Time
+	}{
+		{"next weekday in week", UnitWeek, 2, weekdays, day(time.January, 6), day(time.January, 7)},
+		{"skips off week", UnitWeek, 2, weekdays, day(time.January, 10), day(time.January, 19)},
+		{"2nd tuesday", UnitMonth, 1, []Offset{{Weekday: time.Tuesday, Nth: 2}}, day(time.January, 6), day(time.January, 13)},
+		{"last friday", UnitMonth, 1, []Offset{{Weekday: time.Friday, Nth: -1}}, day(time.January, 31), day(time.February, 27)},
+		{"last day", UnitMonth, 1, []Offset{{LastDay: true}}, day(time.February, 1), day(time.February, 28)},
+		{"missing 5th monday", UnitMonth, 1, []Offset{{Weekday: time.Monday, Nth: 5}}, day(time.January, 6), day(time.March, 30)},
+	}
+	for _, tc := range cases {
+		got, err := nextScheduleDate(anchor, tc.start, tc.unit, tc.every, tc.on)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !got.Equal(tc.want) {
+			t.Fatalf("%s: got %s want %s", tc.name, got.Format("2006-01-02"), tc.want.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestBuildUpdateRejectsOffsetsForUnit(t *testing.T) {
+	_, err := BuildUpdate(Spec{Unit: UnitDay, Every: 1, On: []Offset{{Weekday: time.Monday}}})
+	if err == nil {
+		t.Fatalf("expected error for daily repeat-on")
+	}
+	_, err = BuildUpdate(Spec{Unit: UnitMonth, Every: 1, On: []Offset{{Weekday: time.Monday}}})
+	if err == nil {
+		t.Fatalf("expected error for monthly weekday without ordinal")
+	}
+}
+
+func ruleOffsets(t *testing.T, data []byte) []any {
+	t.Helper()
+	var decoded map[string]any
+	if _, err := plist.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal rule: %v", err)
+	}
+	offsets, _ := decoded["of"].([]any)
+	normalized := make([]any, 0, len(offsets))
+	for _, raw := range offsets {
+		offset := map[string]int{}
+		for key, value := range raw.(map[string]any) {
+			n, _ := plistInt(value)
+			offset[key] = n
+		}
+		normalized = append(normalized, offset)
+	}
+	return normalized
+}
@@ -35,6 +35,10 @@ type Spec struct {
 	Anchor         time.Time
 	EndDate        *time.Time
 	DeadlineOffset *int
+	// On overrides the anchor-derived position within each period. Weekly
+	// rules may list several weekdays; monthly rules take one nth weekday or
+	// the last day.
+	On []Offset
 }
 
 // ParseMode parses a repeat mode string.
@@ -78,7 +82,10 @@ func BuildUpdate(spec Spec) (db.RepeatUpdate, error) {
 			return db.RepeatUpdate{}, fmt.Errorf("repeat end date must be on or after the start date")
 		}
 	}
-	offsets, err := offsetsFor(anchor, spec.Unit)
+	if err := validateOffsets(spec.Unit, spec.On); err != nil {
+		return db.RepeatUpdate{}, err
+	}
+	offsets, err := offsetsFor(anchor, spec.Unit, spec.On)
 	if err != nil {
 		return db.RepeatUpdate{}, err
 	}
@@ -89,17 +96,14 @@ func BuildUpdate(spec Spec) (db.RepeatUpdate, error) {
 	}
 
 	ts := 0
-	setDeadline := false
-	var deadline *int
 	if spec.DeadlineOffset != nil {
 		if *spec.DeadlineOffset < 0 {
 			return db.RepeatUpdate{}, fmt.Errorf("repeat deadline offset must be >= 0")
 		}
 		ts = -1 * (*spec.DeadlineOffset)
-		setDeadline = true
-		sentinel := thingsDateValue(time.Date(4001, 1, 1, 0, 0, 0, 0, time.Local))
-		deadline = &sentinel
 	}
+	deadline := TemplateDeadline(spec)
+	setDeadline := deadline != nil
 
 	rule := map[string]any{
 		"ed":  float64(endDate.Unix()),
@@ -123,7 +127,7 @@ func BuildUpdate(spec Spec) (db.RepeatUpdate, error) {
 	start := thingsDateValue(startDate)
 	var next *int
 	if spec.Mode == ModeSchedule {
-		nextDate, err := nextScheduleDate(anchor, startDate, spec.Unit, spec.Every)
+		nextDate, err := nextScheduleDate(anchor, startDate, spec.Unit, spec.Every, spec.On)
 		if err != nil {
 			return db.RepeatUpdate{}, err
 		}
@@ -143,6 +147,17 @@ func BuildUpdate(spec Spec) (db.RepeatUpdate, error) {
 	}, nil
 }
 
+// TemplateDeadline returns the deadline Things stores on a template whose
+// rule is spec: a fixed 4001-01-01 placeholder when instances get deadlines,
+// and none otherwise.
+func TemplateDeadline(spec Spec) *int {
+	if spec.DeadlineOffset == nil {
+		return nil
+	}
+	sentinel := thingsDateValue(time.Date(4001, 1, 1, 0, 0, 0, 0, time.Local))
+	return &sentinel
+}
+
 func normalizeDate(t time.Time) time.Time {
 	if t.IsZero() {
 		t = time.Now()
@@ -150,7 +165,7 @@ func normalizeDate(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 }
 
-func nextScheduleDate(anchor, start time.Time, unit Unit, every int) (time.Time, error) {
+func nextScheduleDate(anchor, start time.Time, unit Unit, every int, on []Offset) (time.Time, error) {
 	if every <= 0 {
 		return time.Time{}, fmt.Errorf("repeat interval must be >= 1")
 	}
@@ -168,6 +183,9 @@ func nextScheduleDate(anchor, start time.Time, unit Unit, every int) (time.Time,
 		}
 		return anchor.AddDate(0, 0, steps*every), nil
 	case UnitWeek:
+		if len(on) > 0 {
+			return nextWeekdays(anchor, start, every, on), nil
+		}
 		daysUntil := (int(anchor.Weekday()) - int(start.Weekday()) + 7) % 7
 		candidate := start.AddDate(0, 0, daysUntil)
 		weeksBetween := daysBetween(anchor, candidate) / 7
@@ -177,6 +195,9 @@ func nextScheduleDate(anchor, start time.Time, unit Unit, every int) (time.Time,
 		}
 		return candidate, nil
 	case UnitMonth:
+		if len(on) > 0 {
+			return nextMonthOffset(anchor, start, every, on[0])
+		}
 		return nextMonthly(anchor, start, every), nil
 	case UnitYear:
 		return nextYearly(anchor, start, every), nil
@@ -258,7 +279,13 @@ func daysInMonth(year int, month time.Month, loc *time.Location) int {
 	return time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
 }
 
-func offsetsFor(anchor time.Time, unit Unit) ([]map[string]int, error) {
+func offsetsFor(anchor time.Time, unit Unit, on []Offset) ([]map[string]int, error) {
+	if len(on) > 0 {
+		if unit == UnitWeek {
+			on = normalizeWeekdays(on)
+		}
+		return encodeOffsets(on), nil
+	}
 	switch unit {
 	case UnitDay:
 		return []map[string]int{{"dy": 0}}, nil
@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"strings"
+
+	"github.com/ossianhempel/things3-cli/internal/things"
+)
+
+// JSONItems converts an expanded template into a Things json payload.
+// Project-level todos come first, followed by each heading and its todos.
+func (t *Template) JSONItems() []things.JSONItem {
+	items := make([]things.JSONItem, 0, len(t.Todos)+len(t.Headings))
+	for _, todo := range t.Todos {
+		items = append(items, todoJSONItem(todo))
+	}
+	for _, heading := range t.Headings {
+		items = append(items, things.NewJSONHeading(things.JSONHeadingAttributes{Title: heading.Title}))
+		for _, todo := range heading.Todos {
+			items = append(items, todoJSONItem(todo))
+		}
+	}
+
+	project := things.NewJSONProject(things.JSONProjectAttributes{
+		Title:    t.Title,
+		Notes:    t.Notes,
+		When:     t.When,
+		Deadline: t.Deadline,
+		Tags:     trimAll(t.Tags),
+		AreaID:   t.AreaID,
+		Area:     t.Area,
+		Items:    items,
+	})
+	return []things.JSONItem{project}
+}
+
+func todoJSONItem(todo Todo) things.JSONItem {
+	checklist := make([]things.JSONItem, 0, len(todo.Checklist))
+	for _, entry := range todo.Checklist {
+		checklist = append(checklist, things.NewJSONChecklistItem(things.JSONChecklistItemAttributes{Title: entry}))
+	}
+	if len(checklist) == 0 {
+		checklist = nil
+	}
+	return things.NewJSONTodo(things.JSONTodoAttributes{
+		Title:          todo.Title,
+		Notes:          todo.Notes,
+		When:           todo.When,
+		Deadline:       todo.Deadline,
+		Tags:           trimAll(todo.Tags),
+		ChecklistItems: checklist,
+	})
+}
+
+func trimAll(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+)
+
+// FromProject captures a template from an existing project tree. Todo
+// details (notes, tags, dates, checklist) are looked up in tasks by UUID, and
+// dates are stored as offsets relative to base.
+func FromProject(project db.Task, tree db.TreeItem, tasks map[string]db.Task, base time.Time) *Template {
+	tmpl := &Template{
+		Title:    project.Title,
+		Notes:    project.Notes,
+		Area:     project.AreaTitle,
+		Tags:     project.Tags,
+		When:     relativeDate(project.StartDate, base),
+		Deadline: relativeDate(project.Deadline, base),
+	}
+	for _, child := range tree.Items {
+		switch child.Type {
+		case "heading":
+			heading := Heading{Title: child.Title}
+			for _, item := range child.Items {
+				heading.Todos = append(heading.Todos, todoFromTree(item, tasks, base))
+			}
+			tmpl.Headings = append(tmpl.Headings, heading)
+		default:
+			tmpl.Todos = append(tmpl.Todos, todoFromTree(child, tasks, base))
+		}
+	}
+	return tmpl
+}
+
+func todoFromTree(item db.TreeItem, tasks map[string]db.Task, base time.Time) Todo {
+	todo := Todo{Title: item.Title}
+	task, ok := tasks[item.UUID]
+	if !ok {
+		return todo
+	}
+	todo.Notes = task.Notes
+	todo.Tags = task.Tags
+	todo.When = relativeDate(task.StartDate, base)
+	todo.Deadline = relativeDate(task.Deadline, base)
+	for _, entry := range task.Checklist {
+		todo.Checklist = append(todo.Checklist, entry.Title)
+	}
+	return todo
+}
+
+func relativeDate(value string, base time.Time) string {
+	if value == "" {
+		return ""
+	}
+	parsed, err := time.ParseInLocation("2006-01-02", value, base.Location())
+	if err != nil {
+		return value
+	}
+	days := int(math.Round(parsed.Sub(dateOnly(base)).Hours() / 24))
+	if days >= 0 {
+		return fmt.Sprintf("+%dd", days)
+	}
+	return fmt.Sprintf("%dd", days)
+}
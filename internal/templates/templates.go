@@ -0,0 +1,279 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template describes a project and its headings, todos, and checklist items.
+type Template struct {
+	Vars     map[string]string `yaml:"vars,omitempty" json:"vars,omitempty"`
+	Title    string            `yaml:"title" json:"title"`
+	Notes    string            `yaml:"notes,omitempty" json:"notes,omitempty"`
+	Area     string            `yaml:"area,omitempty" json:"area,omitempty"`
+	AreaID   string            `yaml:"area_id,omitempty" json:"area_id,omitempty"`
+	Tags     []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	When     string            `yaml:"when,omitempty" json:"when,omitempty"`
+	Deadline string            `yaml:"deadline,omitempty" json:"deadline,omitempty"`
+	Todos    []Todo            `yaml:"todos,omitempty" json:"todos,omitempty"`
+	Headings []Heading         `yaml:"headings,omitempty" json:"headings,omitempty"`
+}
+
+// Heading groups todos under a project heading.
+type Heading struct {
+	Title string `yaml:"title" json:"title"`
+	Todos []Todo `yaml:"todos,omitempty" json:"todos,omitempty"`
+}
+
+// Todo describes a single todo in a template.
+type Todo struct {
+	Title     string   `yaml:"title" json:"title"`
+	Notes     string   `yaml:"notes,omitempty" json:"notes,omitempty"`
+	Tags      []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	When      string   `yaml:"when,omitempty" json:"when,omitempty"`
+	Deadline  string   `yaml:"deadline,omitempty" json:"deadline,omitempty"`
+	Checklist []string `yaml:"checklist,omitempty" json:"checklist,omitempty"`
+}
+
+// Parse decodes a YAML or JSON template.
+func Parse(data []byte) (*Template, error) {
+	var tmpl Template
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&tmpl); err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	if strings.TrimSpace(tmpl.Title) == "" {
+		return nil, fmt.Errorf("template requires a title")
+	}
+	return &tmpl, nil
+}
+
+// Marshal encodes a template as YAML.
+func Marshal(tmpl *Template) ([]byte, error) {
+	return yaml.Marshal(tmpl)
+}
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// Expand substitutes {{name}} variables and resolves relative when/deadline
+// values against base. Values in vars override the template defaults.
+func (t *Template) Expand(vars map[string]string, base time.Time) (*Template, error) {
+	values := make(map[string]string, len(t.Vars)+len(vars))
+	for k, v := range t.Vars {
+		values[k] = v
+	}
+	for k, v := range vars {
+		values[k] = v
+	}
+	missing := map[string]bool{}
+	sub := func(input string) string {
+		return variablePattern.ReplaceAllStringFunc(input, func(match string) string {
+			name := variablePattern.FindStringSubmatch(match)[1]
+			value, ok := values[name]
+			if !ok {
+				missing[name] = true
+				return match
+			}
+			return value
+		})
+	}
+
+	var dateErr error
+	date := func(input string) string {
+		value, err := ResolveDate(sub(input), base)
+		if err != nil && dateErr == nil {
+			dateErr = err
+		}
+		return value
+	}
+	subAll := func(items []string) []string {
+		if len(items) == 0 {
+			return nil
+		}
+		out := make([]string, 0, len(items))
+		for _, item := range items {
+			out = append(out, sub(item))
+		}
+		return out
+	}
+	todo := func(in Todo) Todo {
+		return Todo{
+			Title:     sub(in.Title),
+			Notes:     sub(in.Notes),
+			Tags:      subAll(in.Tags),
+			When:      date(in.When),
+			Deadline:  date(in.Deadline),
+			Checklist: subAll(in.Checklist),
+		}
+	}
+
+	out := &Template{
+		Title:    sub(t.Title),
+		Notes:    sub(t.Notes),
+		Area:     sub(t.Area),
+		AreaID:   sub(t.AreaID),
+		Tags:     subAll(t.Tags),
+		When:     date(t.When),
+		Deadline: date(t.Deadline),
+	}
+	for _, item := range t.Todos {
+		out.Todos = append(out.Todos, todo(item))
+	}
+	for _, heading := range t.Headings {
+		expanded := Heading{Title: sub(heading.Title)}
+		for _, item := range heading.Todos {
+			expanded.Todos = append(expanded.Todos, todo(item))
+		}
+		out.Headings = append(out.Headings, expanded)
+	}
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("missing template variables: %s (use --var name=value)", strings.Join(names, ", "))
+	}
+	if dateErr != nil {
+		return nil, dateErr
+	}
+	return out, nil
+}
+
+var dateOffsetPattern = regexp.MustCompile(`^([+-])\s*(\d+)\s*([dwmy])$`)
+
+// ResolveDate resolves a when/deadline expression relative to base.
+//
+// Accepted forms: empty, Things keywords (today, tomorrow, evening, anytime,
+// someday), YYYY-MM-DD, an offset such as +3d, -1w, +2m, +1y, or a date
+// followed by one or more offsets ("2026-03-01 -2d", "today +1w").
+func ResolveDate(input string, base time.Time) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", nil
+	}
+	switch strings.ToLower(input) {
+	case "evening", "anytime", "someday", "inbox":
+		return strings.ToLower(input), nil
+	case "today", "tomorrow":
+		if isToday(base) {
+			return strings.ToLower(input), nil
+		}
+	}
+
+	fields := splitDateExpr(input)
+	current := dateOnly(base)
+	start := 0
+	switch first := strings.ToLower(fields[0]); {
+	case first == "today":
+		start = 1
+	case first == "tomorrow":
+		current = current.AddDate(0, 0, 1)
+		start = 1
+	default:
+		if parsed, err := time.ParseInLocation("2006-01-02", fields[0], base.Location()); err == nil {
+			current = parsed
+			start = 1
+		}
+	}
+	for _, field := range fields[start:] {
+		match := dateOffsetPattern.FindStringSubmatch(field)
+		if match == nil {
+			return "", fmt.Errorf("invalid date expression %q (use YYYY-MM-DD, +Nd, -Nw, +Nm, or +Ny)", input)
+		}
+		amount, _ := strconv.Atoi(match[2])
+		if match[1] == "-" {
+			amount = -amount
+		}
+		switch match[3] {
+		case "d":
+			current = current.AddDate(0, 0, amount)
+		case "w":
+			current = current.AddDate(0, 0, 7*amount)
+		case "m":
+			current = current.AddDate(0, amount, 0)
+		case "y":
+			current = current.AddDate(amount, 0, 0)
+		}
+	}
+	return current.Format("2006-01-02"), nil
+}
+
+// splitDateExpr splits "2026-03-01 -2d+1w" into ["2026-03-01", "-2d", "+1w"].
+func splitDateExpr(input string) []string {
+	fields := []string{}
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			fields = append(fields, b.String())
+			b.Reset()
+		}
+	}
+	for i, ch := range input {
+		switch {
+		case ch == ' ' || ch == '\t':
+			flush()
+		case (ch == '+' || ch == '-') && (i == 0 || b.Len() == 0 || !isDatePrefix(b.String())):
+			flush()
+			b.WriteRune(ch)
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	flush()
+	return fields
+}
+
+// isDatePrefix reports whether s could still grow into a YYYY-MM-DD date, so
+// that dashes inside the date are not mistaken for offsets.
+func isDatePrefix(s string) bool {
+	if len(s) >= 10 {
+		return false
+	}
+	for i, ch := range s {
+		if i == 4 || i == 7 {
+			if ch != '-' {
+				return false
+			}
+			continue
+		}
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func dateOnly(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func isToday(t time.Time) bool {
+	now := time.Now().In(t.Location())
+	return t.Year() == now.Year() && t.YearDay() == now.YearDay()
+}
+
+// ParseVars parses name=value assignments.
+func ParseVars(assignments []string) (map[string]string, error) {
+	vars := make(map[string]string, len(assignments))
+	for _, raw := range assignments {
+		name, value, ok := strings.Cut(raw, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable %q (use name=value)", raw)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
@@ -0,0 +1,152 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ossianhempel/things3-cli/internal/db"
+	"github.com/ossianhempel/things3-cli/internal/things"
+)
+
+const sampleTemplate = `
+title: "Release {{version}}"
+vars:
+  version: "1.0"
+area: Work
+deadline: +2w
+todos:
+  - title: Write changelog for {{version}}
+headings:
+  - title: Prep
+    todos:
+      - title: Tag build
+        when: 2026-03-01 -2d
+        checklist: [Build, Sign]
+`
+
+func TestExpandSubstitutesVarsAndDates(t *testing.T) {
+	tmpl, err := Parse([]byte(sampleTemplate))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	base := time.Date(2026, 1, 10, 0, 0, 0, 0, time.Local)
+	expanded, err := tmpl.Expand(map[string]string{"version": "1.2"}, base)
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	if expanded.Title != "Release 1.2" {
+		t.Fatalf("unexpected title %q", expanded.Title)
+	}
+	if expanded.Todos[0].Title != "Write changelog for 1.2" {
+		t.Fatalf("unexpected todo title %q", expanded.Todos[0].Title)
+	}
+	if expanded.Deadline != "2026-01-24" {
+		t.Fatalf("unexpected deadline %q", expanded.Deadline)
+	}
+	if got := expanded.Headings[0].Todos[0].When; got != "2026-02-27" {
+		t.Fatalf("unexpected when %q", got)
+	}
+}
+
+func TestExpandReportsMissingVars(t *testing.T) {
+	tmpl, err := Parse([]byte("title: \"{{client}} onboarding {{year}}\"\n"))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	_, err = tmpl.Expand(nil, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "client, year") {
+		t.Fatalf("expected missing variables error, got %v", err)
+	}
+}
+
+func TestParseRejectsUnknownKeys(t *testing.T) {
+	if _, err := Parse([]byte("title: X\nbogus: 1\n")); err == nil {
+		t.Fatalf("expected unknown key error")
+	}
+	if _, err := Parse([]byte("notes: no title\n")); err == nil {
+		t.Fatalf("expected missing title error")
+	}
+}
+
+func TestResolveDate(t *testing.T) {
+	base := time.Date(2026, 1, 31, 15, 0, 0, 0, time.Local)
+	cases := map[string]string{
+		"":               "",
+		"someday":        "someday",
+		"+1d":            "2026-02-01",
+		"-1w":            "2026-01-24",
+		"+1m":            "2026-03-03",
+		"2026-05-01":     "2026-05-01",
+		"2026-05-01 +1y": "2027-05-01",
+		"2026-05-01-2d":  "2026-04-29",
+		"today +1w":      "2026-02-07",
+		"tomorrow":       "2026-02-01",
+	}
+	for input, want := range cases {
+		got, err := ResolveDate(input, base)
+		if err != nil {
+			t.Fatalf("ResolveDate(%q): %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("ResolveDate(%q) = %q, want %q", input, got, want)
+		}
+	}
+	if _, err := ResolveDate("next week", base); err == nil {
+		t.Fatalf("expected error for invalid expression")
+	}
+}
+
+func TestJSONItemsBuildsProjectTree(t *testing.T) {
+	tmpl, err := Parse([]byte(sampleTemplate))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	expanded, err := tmpl.Expand(nil, time.Date(2026, 1, 10, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	items := expanded.JSONItems()
+	if len(items) != 1 || items[0].Type != things.JSONTypeProject {
+		t.Fatalf("expected single project, got %+v", items)
+	}
+	attrs := items[0].Attributes.(things.JSONProjectAttributes)
+	if attrs.Area != "Work" || len(attrs.Items) != 3 {
+		t.Fatalf("unexpected project attributes: %+v", attrs)
+	}
+	if attrs.Items[1].Type != things.JSONTypeHeading {
+		t.Fatalf("expected heading second, got %s", attrs.Items[1].Type)
+	}
+	todo := attrs.Items[2].Attributes.(things.JSONTodoAttributes)
+	if len(todo.ChecklistItems) != 2 {
+		t.Fatalf("expected checklist items, got %+v", todo)
+	}
+}
+
+func TestFromProjectUsesRelativeDates(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	project := db.Task{UUID: "P1", Title: "Launch", AreaTitle: "Work", Deadline: "2026-03-15"}
+	tree := db.TreeItem{
+		UUID: "P1",
+		Items: []db.TreeItem{
+			{UUID: "T1", Type: "to-do", Title: "Kickoff"},
+			{UUID: "H1", Type: "heading", Title: "Ship", Items: []db.TreeItem{
+				{UUID: "T2", Type: "to-do", Title: "Deploy"},
+			}},
+		},
+	}
+	tasks := map[string]db.Task{
+		"T2": {UUID: "T2", Title: "Deploy", StartDate: "2026-02-27", Checklist: []db.ChecklistItem{{Title: "Smoke test"}}},
+	}
+	tmpl := FromProject(project, tree, tasks, base)
+	if tmpl.Deadline != "+14d" {
+		t.Fatalf("unexpected deadline %q", tmpl.Deadline)
+	}
+	if len(tmpl.Todos) != 1 || len(tmpl.Headings) != 1 {
+		t.Fatalf("unexpected structure: %+v", tmpl)
+	}
+	deploy := tmpl.Headings[0].Todos[0]
+	if deploy.When != "-2d" || len(deploy.Checklist) != 1 {
+		t.Fatalf("unexpected todo: %+v", deploy)
+	}
+}
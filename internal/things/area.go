@@ -16,6 +16,8 @@ type UpdateAreaOptions struct {
 	Title   string
 	Tags    string
 	AddTags string
+	// ClearTags removes every tag from the area; Tags and AddTags win over it.
+	ClearTags bool
 }
 
 // DeleteAreaOptions defines options for delete-area.
@@ -50,7 +52,7 @@ func BuildUpdateAreaScript(opts UpdateAreaOptions, rawInput string) (string, err
 	if opts.ID == "" && targetTitle == "" {
 		return "", errMissingAreaTarget
 	}
-	if strings.TrimSpace(opts.Title) == "" && strings.TrimSpace(opts.Tags) == "" && strings.TrimSpace(opts.AddTags) == "" {
+	if strings.TrimSpace(opts.Title) == "" && strings.TrimSpace(opts.Tags) == "" && strings.TrimSpace(opts.AddTags) == "" && !opts.ClearTags {
 		return "", errMissingAreaUpdate
 	}
 
@@ -84,6 +86,8 @@ func BuildUpdateAreaScript(opts UpdateAreaOptions, rawInput string) (string, err
 		b.WriteString(escapeAppleScriptString(addTags))
 		b.WriteString("\"\n")
 		b.WriteString("  end if\n")
+	} else if opts.ClearTags {
+		b.WriteString("  set tag names of targetArea to \"\"\n")
 	}
 
 	b.WriteString("end tell")
@@ -77,6 +77,16 @@ func TestBuildUpdateAreaScriptReplaceTags(t *testing.T) {
 	}
 }
 
+func TestBuildUpdateAreaScriptClearTags(t *testing.T) {
+	script, err := BuildUpdateAreaScript(UpdateAreaOptions{ID: "123", ClearTags: true}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(script, "set tag names of targetArea to \"\"") {
+		t.Fatalf("expected tags cleared in %q", script)
+	}
+}
+
 func TestBuildUpdateAreaScriptWithTitle(t *testing.T) {
 	script, err := BuildUpdateAreaScript(UpdateAreaOptions{ID: "123", Title: "Renamed"}, "")
 	if err != nil {
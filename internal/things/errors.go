@@ -10,3 +10,6 @@ var errMissingAreaTarget = errors.New("Error: Must specify --id=ID or area title
 var errMissingAreaUpdate = errors.New("Error: Must specify --tags, --add-tags, or --title")
 var errMissingTodoTarget = errors.New("Error: Must specify --id=ID or todo title")
 var errMissingProjectTarget = errors.New("Error: Must specify --id=ID or project title")
+var errMissingJSONItems = errors.New("Error: JSON payload must contain at least one item")
+var errMissingTagTarget = errors.New("Error: Must specify --id=ID or tag title")
+var errMissingTagUpdate = errors.New("Error: Must specify --title, --parent, --no-parent, or --shortcut")
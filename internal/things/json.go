@@ -0,0 +1,386 @@
+package things
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JSON object types understood by the Things json command.
+const (
+	JSONTypeTodo          = "to-do"
+	JSONTypeProject       = "project"
+	JSONTypeHeading       = "heading"
+	JSONTypeChecklistItem = "checklist-item"
+)
+
+// JSON operations understood by the Things json command.
+const (
+	JSONOperationCreate = "create"
+	JSONOperationUpdate = "update"
+)
+
+// DefaultJSONMaxURLLength caps the length of a single json URL before the
+// payload is split across several URLs.
+const DefaultJSONMaxURLLength = 32000
+
+// JSONItem is a single object in a Things json payload.
+type JSONItem struct {
+	Type       string `json:"type"`
+	Operation  string `json:"operation,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Attributes any    `json:"attributes"`
+}
+
+// JSONTodoAttributes defines the attributes of a to-do object.
+type JSONTodoAttributes struct {
+	Title                 string     `json:"title,omitempty"`
+	Notes                 string     `json:"notes,omitempty"`
+	PrependNotes          string     `json:"prepend-notes,omitempty"`
+	AppendNotes           string     `json:"append-notes,omitempty"`
+	When                  string     `json:"when,omitempty"`
+	Deadline              string     `json:"deadline,omitempty"`
+	Tags                  []string   `json:"tags,omitempty"`
+	AddTags               []string   `json:"add-tags,omitempty"`
+	ChecklistItems        []JSONItem `json:"checklist-items,omitempty"`
+	PrependChecklistItems []JSONItem `json:"prepend-checklist-items,omitempty"`
+	AppendChecklistItems  []JSONItem `json:"append-checklist-items,omitempty"`
+	ListID                string     `json:"list-id,omitempty"`
+	List                  string     `json:"list,omitempty"`
+	HeadingID             string     `json:"heading-id,omitempty"`
+	Heading               string     `json:"heading,omitempty"`
+	Completed             *bool      `json:"completed,omitempty"`
+	Canceled              *bool      `json:"canceled,omitempty"`
+	CreationDate          string     `json:"creation-date,omitempty"`
+	CompletionDate        string     `json:"completion-date,omitempty"`
+}
+
+// JSONProjectAttributes defines the attributes of a project object.
+type JSONProjectAttributes struct {
+	Title          string     `json:"title,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	PrependNotes   string     `json:"prepend-notes,omitempty"`
+	AppendNotes    string     `json:"append-notes,omitempty"`
+	When           string     `json:"when,omitempty"`
+	Deadline       string     `json:"deadline,omitempty"`
+	Tags           []string   `json:"tags,omitempty"`
+	AddTags        []string   `json:"add-tags,omitempty"`
+	AreaID         string     `json:"area-id,omitempty"`
+	Area           string     `json:"area,omitempty"`
+	Items          []JSONItem `json:"items,omitempty"`
+	Completed      *bool      `json:"completed,omitempty"`
+	Canceled       *bool      `json:"canceled,omitempty"`
+	CreationDate   string     `json:"creation-date,omitempty"`
+	CompletionDate string     `json:"completion-date,omitempty"`
+}
+
+// JSONHeadingAttributes defines the attributes of a heading object.
+type JSONHeadingAttributes struct {
+	Title    string `json:"title,omitempty"`
+	Archived *bool  `json:"archived,omitempty"`
+}
+
+// JSONChecklistItemAttributes defines the attributes of a checklist item object.
+type JSONChecklistItemAttributes struct {
+	Title     string `json:"title,omitempty"`
+	Completed *bool  `json:"completed,omitempty"`
+	Canceled  *bool  `json:"canceled,omitempty"`
+}
+
+// JSONOptions defines options for the json command.
+type JSONOptions struct {
+	AuthToken    string
+	Reveal       bool
+	MaxURLLength int
+}
+
+// NewJSONTodo returns a create operation for a to-do.
+func NewJSONTodo(attrs JSONTodoAttributes) JSONItem {
+	return JSONItem{Type: JSONTypeTodo, Attributes: attrs}
+}
+
+// NewJSONProject returns a create operation for a project.
+func NewJSONProject(attrs JSONProjectAttributes) JSONItem {
+	return JSONItem{Type: JSONTypeProject, Attributes: attrs}
+}
+
+// NewJSONHeading returns a heading for use inside project items.
+func NewJSONHeading(attrs JSONHeadingAttributes) JSONItem {
+	return JSONItem{Type: JSONTypeHeading, Attributes: attrs}
+}
+
+// NewJSONChecklistItem returns a checklist item for use inside to-do attributes.
+func NewJSONChecklistItem(attrs JSONChecklistItemAttributes) JSONItem {
+	return JSONItem{Type: JSONTypeChecklistItem, Attributes: attrs}
+}
+
+// UpdateJSONTodo returns an update operation for an existing to-do.
+func UpdateJSONTodo(id string, attrs JSONTodoAttributes) JSONItem {
+	return JSONItem{Type: JSONTypeTodo, Operation: JSONOperationUpdate, ID: id, Attributes: attrs}
+}
+
+// UpdateJSONProject returns an update operation for an existing project.
+func UpdateJSONProject(id string, attrs JSONProjectAttributes) JSONItem {
+	return JSONItem{Type: JSONTypeProject, Operation: JSONOperationUpdate, ID: id, Attributes: attrs}
+}
+
+// UnmarshalJSON decodes attributes into the typed struct for the item type.
+func (item *JSONItem) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type       string          `json:"type"`
+		Operation  string          `json:"operation"`
+		ID         string          `json:"id"`
+		Attributes json.RawMessage `json:"attributes"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	item.Type = raw.Type
+	item.Operation = raw.Operation
+	item.ID = raw.ID
+
+	attrs := raw.Attributes
+	if len(attrs) == 0 || string(attrs) == "null" {
+		attrs = []byte("{}")
+	}
+	switch raw.Type {
+	case JSONTypeTodo:
+		var a JSONTodoAttributes
+		if err := decodeJSONAttributes(attrs, &a); err != nil {
+			return fmt.Errorf("%s: %w", raw.Type, err)
+		}
+		item.Attributes = a
+	case JSONTypeProject:
+		var a JSONProjectAttributes
+		if err := decodeJSONAttributes(attrs, &a); err != nil {
+			return fmt.Errorf("%s: %w", raw.Type, err)
+		}
+		item.Attributes = a
+	case JSONTypeHeading:
+		var a JSONHeadingAttributes
+		if err := decodeJSONAttributes(attrs, &a); err != nil {
+			return fmt.Errorf("%s: %w", raw.Type, err)
+		}
+		item.Attributes = a
+	case JSONTypeChecklistItem:
+		var a JSONChecklistItemAttributes
+		if err := decodeJSONAttributes(attrs, &a); err != nil {
+			return fmt.Errorf("%s: %w", raw.Type, err)
+		}
+		item.Attributes = a
+	default:
+		return fmt.Errorf("unknown object type %q", raw.Type)
+	}
+	return nil
+}
+
+func decodeJSONAttributes(data []byte, target any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(target)
+}
+
+// ParseJSONItems decodes a json payload containing an array of objects or a
+// single object.
+func ParseJSONItems(data []byte) ([]JSONItem, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errMissingJSONItems
+	}
+	if trimmed[0] == '{' {
+		var item JSONItem
+		if err := json.Unmarshal(trimmed, &item); err != nil {
+			return nil, fmt.Errorf("Error: invalid JSON payload: %v", err)
+		}
+		return []JSONItem{item}, nil
+	}
+	var items []JSONItem
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("Error: invalid JSON payload: %v", err)
+	}
+	return items, nil
+}
+
+// JSONItemsNeedAuth reports whether any top-level item is an update operation.
+func JSONItemsNeedAuth(items []JSONItem) bool {
+	for _, item := range items {
+		if item.Operation == JSONOperationUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateJSONItems checks the structure of a json payload before encoding.
+func ValidateJSONItems(items []JSONItem) error {
+	if len(items) == 0 {
+		return errMissingJSONItems
+	}
+	for i, item := range items {
+		if err := validateJSONItem(item, true); err != nil {
+			return fmt.Errorf("Error: item %d: %s", i+1, err)
+		}
+	}
+	return nil
+}
+
+func validateJSONItem(item JSONItem, topLevel bool) error {
+	switch item.Operation {
+	case "", JSONOperationCreate:
+		if item.ID != "" {
+			return fmt.Errorf("id is only valid for update operations")
+		}
+	case JSONOperationUpdate:
+		if !topLevel {
+			return fmt.Errorf("update operations must be top-level objects")
+		}
+		if strings.TrimSpace(item.ID) == "" {
+			return fmt.Errorf("update operations require an id")
+		}
+	default:
+		return fmt.Errorf("unknown operation %q", item.Operation)
+	}
+	update := item.Operation == JSONOperationUpdate
+
+	switch attrs := item.Attributes.(type) {
+	case JSONTodoAttributes:
+		if item.Type != JSONTypeTodo {
+			return fmt.Errorf("attributes do not match type %q", item.Type)
+		}
+		if !update && strings.TrimSpace(attrs.Title) == "" {
+			return fmt.Errorf("to-do requires a title")
+		}
+		for _, list := range [][]JSONItem{attrs.ChecklistItems, attrs.PrependChecklistItems, attrs.AppendChecklistItems} {
+			for _, child := range list {
+				if child.Type != JSONTypeChecklistItem {
+					return fmt.Errorf("to-do checklist may only contain checklist-item objects")
+				}
+				if err := validateJSONItem(child, false); err != nil {
+					return err
+				}
+			}
+		}
+	case JSONProjectAttributes:
+		if item.Type != JSONTypeProject {
+			return fmt.Errorf("attributes do not match type %q", item.Type)
+		}
+		if !topLevel {
+			return fmt.Errorf("projects must be top-level objects")
+		}
+		if !update && strings.TrimSpace(attrs.Title) == "" {
+			return fmt.Errorf("project requires a title")
+		}
+		if update && len(attrs.Items) > 0 {
+			return fmt.Errorf("project updates cannot add items")
+		}
+		for _, child := range attrs.Items {
+			if child.Type != JSONTypeTodo && child.Type != JSONTypeHeading {
+				return fmt.Errorf("project items may only contain to-do and heading objects")
+			}
+			if err := validateJSONItem(child, false); err != nil {
+				return err
+			}
+		}
+	case JSONHeadingAttributes:
+		if item.Type != JSONTypeHeading {
+			return fmt.Errorf("attributes do not match type %q", item.Type)
+		}
+		if topLevel {
+			return fmt.Errorf("headings must be inside project items")
+		}
+		if strings.TrimSpace(attrs.Title) == "" {
+			return fmt.Errorf("heading requires a title")
+		}
+	case JSONChecklistItemAttributes:
+		if item.Type != JSONTypeChecklistItem {
+			return fmt.Errorf("attributes do not match type %q", item.Type)
+		}
+		if topLevel {
+			return fmt.Errorf("checklist items must be inside to-do attributes")
+		}
+		if strings.TrimSpace(attrs.Title) == "" {
+			return fmt.Errorf("checklist item requires a title")
+		}
+	default:
+		return fmt.Errorf("unsupported attributes for type %q", item.Type)
+	}
+	return nil
+}
+
+// BuildJSONURLs builds one or more Things URLs for the json command. Top-level
+// items are packed in order; a new URL is started whenever the next item would
+// push the URL past opts.MaxURLLength.
+func BuildJSONURLs(opts JSONOptions, items []JSONItem) ([]string, error) {
+	if err := ValidateJSONItems(items); err != nil {
+		return nil, err
+	}
+	if JSONItemsNeedAuth(items) && opts.AuthToken == "" {
+		return nil, ErrMissingAuthToken
+	}
+	maxLength := opts.MaxURLLength
+	if maxLength <= 0 {
+		maxLength = DefaultJSONMaxURLLength
+	}
+
+	encoded := make([]string, 0, len(items))
+	for _, item := range items {
+		data, err := marshalJSONPayload(item)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, URLEncode(string(data)))
+	}
+
+	// "[" and "]" encode to three characters each, "," to three as well.
+	const bracketLength = 6
+	const separatorLength = 3
+	baseLength := len(buildJSONURL(opts, "")) + bracketLength
+
+	urls := make([]string, 0, 1)
+	chunk := make([]string, 0, len(encoded))
+	chunkLength := baseLength
+	for i, item := range encoded {
+		itemLength := len(item)
+		if len(chunk) > 0 {
+			itemLength += separatorLength
+		}
+		if baseLength+len(item) > maxLength {
+			return nil, fmt.Errorf("Error: item %d is too large for a single URL (%d > %d characters)", i+1, baseLength+len(item), maxLength)
+		}
+		if len(chunk) > 0 && chunkLength+itemLength > maxLength {
+			urls = append(urls, buildJSONURL(opts, "%5B"+strings.Join(chunk, "%2C")+"%5D"))
+			chunk = chunk[:0]
+			chunkLength = baseLength
+			itemLength = len(item)
+		}
+		chunk = append(chunk, item)
+		chunkLength += itemLength
+	}
+	if len(chunk) > 0 {
+		urls = append(urls, buildJSONURL(opts, "%5B"+strings.Join(chunk, "%2C")+"%5D"))
+	}
+	return urls, nil
+}
+
+func buildJSONURL(opts JSONOptions, data string) string {
+	params := make([]string, 0, 3)
+	if opts.AuthToken != "" {
+		params = append(params, "auth-token="+URLEncode(opts.AuthToken))
+	}
+	params = append(params, "data="+data)
+	if opts.Reveal {
+		params = append(params, "reveal=true")
+	}
+	return "things:///json?" + strings.Join(params, "&") + "&"
+}
+
+func marshalJSONPayload(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, fmt.Errorf("Error: encode JSON payload: %v", err)
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
@@ -0,0 +1,151 @@
+package things
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestBuildJSONURLProjectWithHeadingsAndChecklist(t *testing.T) {
+	project := NewJSONProject(JSONProjectAttributes{
+		Title: "Release 1.2",
+		Area:  "Work",
+		Items: []JSONItem{
+			NewJSONHeading(JSONHeadingAttributes{Title: "Prep"}),
+			NewJSONTodo(JSONTodoAttributes{
+				Title: "Tag build",
+				ChecklistItems: []JSONItem{
+					NewJSONChecklistItem(JSONChecklistItemAttributes{Title: "Bump version"}),
+				},
+			}),
+		},
+	})
+	urls, err := BuildJSONURLs(JSONOptions{Reveal: true}, []JSONItem{project})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(urls) != 1 {
+		t.Fatalf("expected 1 url, got %d", len(urls))
+	}
+	if !strings.HasPrefix(urls[0], "things:///json?data=") {
+		t.Fatalf("unexpected url prefix %q", urls[0])
+	}
+	if !contains(urls[0], "reveal=true") {
+		t.Fatalf("expected reveal in %q", urls[0])
+	}
+	data := decodeJSONData(t, urls[0])
+	for _, want := range []string{`"type":"project"`, `"type":"heading"`, `"checklist-items":[{"type":"checklist-item"`, `"area":"Work"`} {
+		if !strings.Contains(data, want) {
+			t.Fatalf("expected %s in %s", want, data)
+		}
+	}
+}
+
+func TestBuildJSONURLUpdateRequiresAuthToken(t *testing.T) {
+	items := []JSONItem{UpdateJSONTodo("ABC", JSONTodoAttributes{When: "today"})}
+	if _, err := BuildJSONURLs(JSONOptions{}, items); err != ErrMissingAuthToken {
+		t.Fatalf("expected missing auth token error, got %v", err)
+	}
+	urls, err := BuildJSONURLs(JSONOptions{AuthToken: "tok"}, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(urls[0], "auth-token=tok") {
+		t.Fatalf("expected auth token in %q", urls[0])
+	}
+	data := decodeJSONData(t, urls[0])
+	if !strings.Contains(data, `"operation":"update","id":"ABC"`) {
+		t.Fatalf("expected update operation in %s", data)
+	}
+}
+
+func TestBuildJSONURLSplitsLargePayloads(t *testing.T) {
+	items := make([]JSONItem, 0, 10)
+	for i := 0; i < 10; i++ {
+		items = append(items, NewJSONTodo(JSONTodoAttributes{Title: strings.Repeat("x", 100)}))
+	}
+	urls, err := BuildJSONURLs(JSONOptions{MaxURLLength: 500}, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(urls) < 2 {
+		t.Fatalf("expected payload to be split, got %d urls", len(urls))
+	}
+	total := 0
+	for _, u := range urls {
+		if len(u) > 500 {
+			t.Fatalf("url exceeds max length: %d", len(u))
+		}
+		total += strings.Count(decodeJSONData(t, u), `"type":"to-do"`)
+	}
+	if total != 10 {
+		t.Fatalf("expected 10 todos across urls, got %d", total)
+	}
+}
+
+func TestBuildJSONURLRejectsOversizedItem(t *testing.T) {
+	items := []JSONItem{NewJSONTodo(JSONTodoAttributes{Title: strings.Repeat("x", 1000)})}
+	if _, err := BuildJSONURLs(JSONOptions{MaxURLLength: 200}, items); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestValidateJSONItemsStructure(t *testing.T) {
+	cases := map[string][]JSONItem{
+		"empty":              nil,
+		"top-level heading":  {NewJSONHeading(JSONHeadingAttributes{Title: "H"})},
+		"missing title":      {NewJSONTodo(JSONTodoAttributes{})},
+		"update without id":  {UpdateJSONTodo("", JSONTodoAttributes{Title: "T"})},
+		"checklist in items": {NewJSONProject(JSONProjectAttributes{Title: "P", Items: []JSONItem{NewJSONChecklistItem(JSONChecklistItemAttributes{Title: "C"})}})},
+		"heading in update":  {UpdateJSONProject("P", JSONProjectAttributes{Items: []JSONItem{NewJSONHeading(JSONHeadingAttributes{Title: "H"})}})},
+	}
+	for name, items := range cases {
+		if err := ValidateJSONItems(items); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseJSONItemsTypedAttributes(t *testing.T) {
+	input := `[{"type":"project","attributes":{"title":"P","items":[{"type":"heading","attributes":{"title":"H"}},{"type":"to-do","attributes":{"title":"T","checklist-items":[{"type":"checklist-item","attributes":{"title":"C"}}]}}]}}]`
+	items, err := ParseJSONItems([]byte(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	project, ok := items[0].Attributes.(JSONProjectAttributes)
+	if !ok {
+		t.Fatalf("expected project attributes, got %T", items[0].Attributes)
+	}
+	todo, ok := project.Items[1].Attributes.(JSONTodoAttributes)
+	if !ok || len(todo.ChecklistItems) != 1 {
+		t.Fatalf("expected to-do with checklist, got %#v", project.Items[1].Attributes)
+	}
+	if err := ValidateJSONItems(items); err != nil {
+		t.Fatalf("unexpected validation error: %v", err)
+	}
+}
+
+func TestParseJSONItemsRejectsUnknownAttributes(t *testing.T) {
+	if _, err := ParseJSONItems([]byte(`{"type":"to-do","attributes":{"title":"T","bogus":1}}`)); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, err := ParseJSONItems([]byte(`{"type":"area","attributes":{"title":"T"}}`)); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func decodeJSONData(t *testing.T, raw string) string {
+	t.Helper()
+	query := strings.TrimPrefix(raw, "things:///json?")
+	for _, part := range strings.Split(query, "&") {
+		if strings.HasPrefix(part, "data=") {
+			value, err := url.QueryUnescape(strings.TrimPrefix(part, "data="))
+			if err != nil {
+				t.Fatalf("decode data: %v", err)
+			}
+			return value
+		}
+	}
+	t.Fatalf("missing data param in %q", raw)
+	return ""
+}
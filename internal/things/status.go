@@ -0,0 +1,51 @@
+package things
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Statuses accepted by BuildStatusScript.
+const (
+	StatusOpen      = "open"
+	StatusCompleted = "completed"
+	StatusCanceled  = "canceled"
+)
+
+// StatusTarget pairs a todo ID with the status to set.
+type StatusTarget struct {
+	ID     string
+	Status string
+}
+
+// BuildStatusScript builds an AppleScript snippet that sets the status of
+// todos. Unlike the URL scheme, it can reopen completed or canceled todos.
+func BuildStatusScript(targets []StatusTarget) (string, error) {
+	var b strings.Builder
+	b.WriteString("tell application \"Things3\"\n")
+	count := 0
+	for _, target := range targets {
+		id := strings.TrimSpace(target.ID)
+		if id == "" {
+			continue
+		}
+		switch target.Status {
+		case StatusOpen, StatusCompleted, StatusCanceled:
+		default:
+			return "", fmt.Errorf("Error: invalid status %q", target.Status)
+		}
+		b.WriteString("  try\n")
+		b.WriteString("    set status of to do id \"")
+		b.WriteString(escapeAppleScriptString(id))
+		b.WriteString("\" to ")
+		b.WriteString(target.Status)
+		b.WriteString("\n")
+		b.WriteString("  end try\n")
+		count++
+	}
+	b.WriteString("end tell")
+	if count == 0 {
+		return "", fmt.Errorf("Error: Must specify --id=ID or query")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,28 @@
+package things
+
+import "testing"
+
+func TestBuildStatusScript(t *testing.T) {
+	script, err := BuildStatusScript([]StatusTarget{
+		{ID: "A1", Status: StatusOpen},
+		{ID: "B2", Status: StatusCompleted},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(script, "set status of to do id \"A1\" to open") {
+		t.Fatalf("expected reopen in script, got %q", script)
+	}
+	if !contains(script, "set status of to do id \"B2\" to completed") {
+		t.Fatalf("expected complete in script, got %q", script)
+	}
+}
+
+func TestBuildStatusScriptRejectsInvalid(t *testing.T) {
+	if _, err := BuildStatusScript(nil); err == nil {
+		t.Fatalf("expected error for empty targets")
+	}
+	if _, err := BuildStatusScript([]StatusTarget{{ID: "A1", Status: "done"}}); err == nil {
+		t.Fatalf("expected error for invalid status")
+	}
+}
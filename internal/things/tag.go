@@ -0,0 +1,242 @@
+package things
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AddTagOptions defines options for add-tag.
+type AddTagOptions struct {
+	Parent   string
+	Shortcut string
+}
+
+// UpdateTagOptions defines options for update-tag.
+type UpdateTagOptions struct {
+	ID          string
+	Title       string
+	Parent      string
+	ClearParent bool
+	Shortcut    string
+}
+
+// DeleteTagOptions defines options for delete-tag.
+type DeleteTagOptions struct {
+	ID string
+}
+
+// Tagged item kinds used by MergeTagsOptions.
+const (
+	TaggedToDo    = "to do"
+	TaggedProject = "project"
+	TaggedArea    = "area"
+)
+
+// TaggedItem is a todo, project, or area carrying the merge source tag.
+type TaggedItem struct {
+	Kind string
+	ID   string
+	Tags []string
+}
+
+// MergeTagsOptions defines options for merge-tags. Items are retagged from
+// Source to Destination, Children are reparented under Destination, and
+// Source is deleted. When Destination is nested anywhere under Source, set
+// DestinationIsChild so it moves up to SourceParent (or the top level when
+// SourceParent is empty) instead of being deleted with Source. When it is
+// nested deeper than a direct child, DestinationAncestor names the child of
+// Source it sits under; that tag moves up with it rather than under it.
+type MergeTagsOptions struct {
+	Source              string
+	Destination         string
+	Items               []TaggedItem
+	Children            []string
+	DestinationIsChild  bool
+	DestinationAncestor string
+	SourceParent        string
+}
+
+// BuildAddTagScript builds an AppleScript snippet for creating a tag.
+func BuildAddTagScript(opts AddTagOptions, rawInput string) (string, error) {
+	title := parseSingleLineTitle(rawInput)
+	if title == "" {
+		return "", errMissingTitle
+	}
+
+	var b strings.Builder
+	b.WriteString("tell application \"Things3\"\n")
+	b.WriteString("  set newTag to make new tag with properties {name:\"")
+	b.WriteString(escapeAppleScriptString(title))
+	b.WriteString("\"}\n")
+	if parent := strings.TrimSpace(opts.Parent); parent != "" {
+		b.WriteString("  set parent tag of newTag to tag \"")
+		b.WriteString(escapeAppleScriptString(parent))
+		b.WriteString("\"\n")
+	}
+	if shortcut := strings.TrimSpace(opts.Shortcut); shortcut != "" {
+		b.WriteString("  set keyboard shortcut of newTag to \"")
+		b.WriteString(escapeAppleScriptString(shortcut))
+		b.WriteString("\"\n")
+	}
+	b.WriteString("end tell")
+	return b.String(), nil
+}
+
+// BuildUpdateTagScript builds an AppleScript snippet for updating a tag.
+func BuildUpdateTagScript(opts UpdateTagOptions, rawInput string) (string, error) {
+	targetTitle := parseSingleLineTitle(rawInput)
+	if opts.ID == "" && targetTitle == "" {
+		return "", errMissingTagTarget
+	}
+	title := strings.TrimSpace(opts.Title)
+	parent := strings.TrimSpace(opts.Parent)
+	shortcut := strings.TrimSpace(opts.Shortcut)
+	if title == "" && parent == "" && !opts.ClearParent && shortcut == "" {
+		return "", errMissingTagUpdate
+	}
+	if parent != "" && opts.ClearParent {
+		return "", fmt.Errorf("Error: use either --parent or --no-parent")
+	}
+
+	var b strings.Builder
+	b.WriteString("tell application \"Things3\"\n")
+	b.WriteString("  set targetTag to ")
+	b.WriteString(tagTarget(opts.ID, targetTitle))
+	b.WriteString("\n")
+	if title != "" {
+		b.WriteString("  set name of targetTag to \"")
+		b.WriteString(escapeAppleScriptString(title))
+		b.WriteString("\"\n")
+	}
+	if parent != "" {
+		b.WriteString("  set parent tag of targetTag to tag \"")
+		b.WriteString(escapeAppleScriptString(parent))
+		b.WriteString("\"\n")
+	} else if opts.ClearParent {
+		b.WriteString("  set parent tag of targetTag to missing value\n")
+	}
+	if shortcut != "" {
+		b.WriteString("  set keyboard shortcut of targetTag to \"")
+		b.WriteString(escapeAppleScriptString(shortcut))
+		b.WriteString("\"\n")
+	}
+	b.WriteString("end tell")
+	return b.String(), nil
+}
+
+// BuildDeleteTagScript builds an AppleScript snippet for deleting a tag.
+func BuildDeleteTagScript(opts DeleteTagOptions, rawInput string) (string, error) {
+	title := parseSingleLineTitle(rawInput)
+	if opts.ID == "" && title == "" {
+		return "", errMissingTagTarget
+	}
+
+	var b strings.Builder
+	b.WriteString("tell application \"Things3\"\n")
+	b.WriteString("  set targetTag to ")
+	b.WriteString(tagTarget(opts.ID, title))
+	b.WriteString("\n")
+	b.WriteString("  delete targetTag\n")
+	b.WriteString("end tell")
+	return b.String(), nil
+}
+
+// BuildMergeTagsScript builds an AppleScript snippet that moves every use of
+// the source tag to the destination tag and then deletes the source.
+func BuildMergeTagsScript(opts MergeTagsOptions) (string, error) {
+	source := strings.TrimSpace(opts.Source)
+	dest := strings.TrimSpace(opts.Destination)
+	if source == "" || dest == "" {
+		return "", fmt.Errorf("Error: Must specify source and destination tags")
+	}
+	if strings.EqualFold(source, dest) {
+		return "", fmt.Errorf("Error: cannot merge a tag into itself")
+	}
+
+	var b strings.Builder
+	b.WriteString("tell application \"Things3\"\n")
+	ancestor := ""
+	if opts.DestinationIsChild {
+		ancestor = strings.TrimSpace(opts.DestinationAncestor)
+		lifted := []string{dest}
+		if ancestor != "" {
+			lifted = append(lifted, ancestor)
+		}
+		for _, tag := range lifted {
+			b.WriteString("  set parent tag of tag \"")
+			b.WriteString(escapeAppleScriptString(tag))
+			if parent := strings.TrimSpace(opts.SourceParent); parent != "" {
+				b.WriteString("\" to tag \"")
+				b.WriteString(escapeAppleScriptString(parent))
+				b.WriteString("\"\n")
+			} else {
+				b.WriteString("\" to missing value\n")
+			}
+		}
+	}
+	for _, item := range opts.Items {
+		var target string
+		switch item.Kind {
+		case TaggedToDo:
+			target = "to do id"
+		case TaggedProject:
+			target = "project id"
+		case TaggedArea:
+			target = "area id"
+		default:
+			return "", fmt.Errorf("Error: unknown item kind %q", item.Kind)
+		}
+		b.WriteString("  set tag names of ")
+		b.WriteString(target)
+		b.WriteString(" \"")
+		b.WriteString(escapeAppleScriptString(item.ID))
+		b.WriteString("\" to \"")
+		b.WriteString(escapeAppleScriptString(strings.Join(mergeTagNames(item.Tags, source, dest), ", ")))
+		b.WriteString("\"\n")
+	}
+	for _, child := range opts.Children {
+		child = strings.TrimSpace(child)
+		if strings.EqualFold(child, dest) || (ancestor != "" && strings.EqualFold(child, ancestor)) {
+			continue
+		}
+		b.WriteString("  set parent tag of tag \"")
+		b.WriteString(escapeAppleScriptString(child))
+		b.WriteString("\" to tag \"")
+		b.WriteString(escapeAppleScriptString(dest))
+		b.WriteString("\"\n")
+	}
+	b.WriteString("  delete tag \"")
+	b.WriteString(escapeAppleScriptString(source))
+	b.WriteString("\"\n")
+	b.WriteString("end tell")
+	return b.String(), nil
+}
+
+// mergeTagNames replaces source with dest in tags, keeping order and
+// dropping duplicates.
+func mergeTagNames(tags []string, source string, dest string) []string {
+	merged := make([]string, 0, len(tags)+1)
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		if strings.EqualFold(tag, source) {
+			tag = dest
+		}
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, tag)
+	}
+	if !seen[strings.ToLower(dest)] {
+		merged = append(merged, dest)
+	}
+	return merged
+}
+
+func tagTarget(id string, title string) string {
+	if id == "" {
+		return fmt.Sprintf("tag \"%s\"", escapeAppleScriptString(title))
+	}
+	return fmt.Sprintf("first tag whose id is \"%s\"", escapeAppleScriptString(id))
+}
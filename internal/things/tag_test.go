@@ -0,0 +1,140 @@
+package things
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildAddTagScriptWithParentAndShortcut(t *testing.T) {
+	script, err := BuildAddTagScript(AddTagOptions{Parent: "Work", Shortcut: "m"}, "Meetings")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(script, "make new tag with properties {name:\"Meetings\"}") {
+		t.Fatalf("expected make new tag in %q", script)
+	}
+	if !contains(script, "set parent tag of newTag to tag \"Work\"") {
+		t.Fatalf("expected parent tag in %q", script)
+	}
+	if !contains(script, "set keyboard shortcut of newTag to \"m\"") {
+		t.Fatalf("expected shortcut in %q", script)
+	}
+}
+
+func TestBuildUpdateTagScriptRequiresChange(t *testing.T) {
+	if _, err := BuildUpdateTagScript(UpdateTagOptions{}, "Work"); err == nil || err.Error() != "Error: Must specify --title, --parent, --no-parent, or --shortcut" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := BuildUpdateTagScript(UpdateTagOptions{Title: "Job"}, ""); err == nil || err.Error() != "Error: Must specify --id=ID or tag title" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBuildUpdateTagScriptByID(t *testing.T) {
+	script, err := BuildUpdateTagScript(UpdateTagOptions{ID: "TAG1", Title: "Job", ClearParent: true}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(script, "first tag whose id is \"TAG1\"") {
+		t.Fatalf("expected id target in %q", script)
+	}
+	if !contains(script, "set name of targetTag to \"Job\"") || !contains(script, "to missing value") {
+		t.Fatalf("expected rename and cleared parent in %q", script)
+	}
+}
+
+func TestBuildDeleteTagScript(t *testing.T) {
+	script, err := BuildDeleteTagScript(DeleteTagOptions{}, "Old")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(script, "set targetTag to tag \"Old\"") || !contains(script, "delete targetTag") {
+		t.Fatalf("unexpected script %q", script)
+	}
+}
+
+func TestBuildMergeTagsScript(t *testing.T) {
+	script, err := BuildMergeTagsScript(MergeTagsOptions{
+		Source:      "errand",
+		Destination: "Errands",
+		Items: []TaggedItem{
+			{Kind: TaggedToDo, ID: "T1", Tags: []string{"errand", "home"}},
+			{Kind: TaggedProject, ID: "P1", Tags: []string{"Errands", "errand"}},
+			{Kind: TaggedArea, ID: "A1", Tags: []string{"errand"}},
+		},
+		Children: []string{"groceries"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"set tag names of to do id \"T1\" to \"Errands, home\"",
+		"set tag names of project id \"P1\" to \"Errands\"",
+		"set tag names of area id \"A1\" to \"Errands\"",
+		"set parent tag of tag \"groceries\" to tag \"Errands\"",
+		"delete tag \"errand\"",
+	} {
+		if !contains(script, want) {
+			t.Fatalf("expected %q in %q", want, script)
+		}
+	}
+}
+
+func TestBuildMergeTagsScriptIntoChild(t *testing.T) {
+	script, err := BuildMergeTagsScript(MergeTagsOptions{
+		Source:             "errand",
+		Destination:        "groceries",
+		Children:           []string{"groceries", "hardware"},
+		DestinationIsChild: true,
+		SourceParent:       "home",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(script, "tell application \"Things3\"\n  set parent tag of tag \"groceries\" to tag \"home\"\n") {
+		t.Fatalf("expected DST to move up to SRC's parent first, got %q", script)
+	}
+	if contains(script, "set parent tag of tag \"groceries\" to tag \"groceries\"") {
+		t.Fatalf("DST must not become its own parent: %q", script)
+	}
+	if !contains(script, "set parent tag of tag \"hardware\" to tag \"groceries\"") {
+		t.Fatalf("expected other children under DST, got %q", script)
+	}
+
+	script, err = BuildMergeTagsScript(MergeTagsOptions{Source: "errand", Destination: "groceries", DestinationIsChild: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(script, "set parent tag of tag \"groceries\" to missing value") {
+		t.Fatalf("expected DST moved to the top level, got %q", script)
+	}
+}
+
+func TestBuildMergeTagsScriptRejectsSelfMerge(t *testing.T) {
+	if _, err := BuildMergeTagsScript(MergeTagsOptions{Source: "a", Destination: "A"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestBuildMergeTagsScriptIntoGrandchild(t *testing.T) {
+	script, err := BuildMergeTagsScript(MergeTagsOptions{
+		Source:              "errand",
+		Destination:         "milk",
+		Children:            []string{"groceries", "hardware"},
+		DestinationIsChild:  true,
+		DestinationAncestor: "groceries",
+		SourceParent:        "home",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(script, "tell application \"Things3\"\n  set parent tag of tag \"milk\" to tag \"home\"\n  set parent tag of tag \"groceries\" to tag \"home\"\n") {
+		t.Fatalf("expected DST and its ancestor to move up first, got %q", script)
+	}
+	if contains(script, "set parent tag of tag \"groceries\" to tag \"milk\"") {
+		t.Fatalf("DST's ancestor must not move under DST: %q", script)
+	}
+	if !contains(script, "set parent tag of tag \"hardware\" to tag \"milk\"") {
+		t.Fatalf("expected other children under DST, got %q", script)
+	}
+}
@@ -3,6 +3,7 @@ package things
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // BuildTrashScript builds an AppleScript snippet to move todos to Trash.
@@ -34,3 +35,78 @@ func BuildTrashScript(ids []string) (string, error) {
 	b.WriteString("end tell")
 	return b.String(), nil
 }
+
+// RestoreTarget describes where a trashed todo goes back to. List is the
+// Things list to move it to ("Inbox", "Anytime", or "Someday"); ProjectID or
+// AreaID, when set, reattach it to its previous container. StartDate
+// (YYYY-MM-DD) reschedules it.
+type RestoreTarget struct {
+	ID        string
+	List      string
+	ProjectID string
+	AreaID    string
+	StartDate string
+}
+
+// BuildRestoreScript builds an AppleScript snippet that moves todos out of
+// Trash in place, keeping their IDs, checklists, and repeat rules.
+func BuildRestoreScript(targets []RestoreTarget) (string, error) {
+	var b strings.Builder
+	b.WriteString("tell application \"Things3\"\n")
+	count := 0
+	for _, target := range targets {
+		id := strings.TrimSpace(target.ID)
+		if id == "" {
+			continue
+		}
+		list := target.List
+		switch strings.ToLower(list) {
+		case "inbox":
+			list = "Inbox"
+		case "someday":
+			list = "Someday"
+		default:
+			list = "Anytime"
+		}
+		var date time.Time
+		if target.StartDate != "" {
+			parsed, err := time.Parse("2006-01-02", target.StartDate)
+			if err != nil {
+				return "", fmt.Errorf("Error: invalid start date %q", target.StartDate)
+			}
+			date = parsed
+		}
+
+		b.WriteString("  try\n")
+		b.WriteString("    set restoredToDo to to do id \"")
+		b.WriteString(escapeAppleScriptString(id))
+		b.WriteString("\"\n")
+		b.WriteString("    move restoredToDo to list \"")
+		b.WriteString(list)
+		b.WriteString("\"\n")
+		if target.ProjectID != "" {
+			b.WriteString("    set project of restoredToDo to project id \"")
+			b.WriteString(escapeAppleScriptString(target.ProjectID))
+			b.WriteString("\"\n")
+		} else if target.AreaID != "" {
+			b.WriteString("    set area of restoredToDo to area id \"")
+			b.WriteString(escapeAppleScriptString(target.AreaID))
+			b.WriteString("\"\n")
+		}
+		if !date.IsZero() {
+			b.WriteString("    set restoreDate to current date\n")
+			b.WriteString("    set day of restoreDate to 1\n")
+			fmt.Fprintf(&b, "    set year of restoreDate to %d\n", date.Year())
+			fmt.Fprintf(&b, "    set month of restoreDate to %d\n", int(date.Month()))
+			fmt.Fprintf(&b, "    set day of restoreDate to %d\n", date.Day())
+			b.WriteString("    schedule restoredToDo for restoreDate\n")
+		}
+		b.WriteString("  end try\n")
+		count++
+	}
+	b.WriteString("end tell")
+	if count == 0 {
+		return "", fmt.Errorf("Error: Must specify --id=ID or query")
+	}
+	return b.String(), nil
+}
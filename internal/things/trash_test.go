@@ -23,3 +23,42 @@ func TestBuildTrashScriptWithIDs(t *testing.T) {
 		t.Fatalf("expected ids in script, got %q", script)
 	}
 }
+
+func TestBuildRestoreScriptRequiresIDs(t *testing.T) {
+	if _, err := BuildRestoreScript([]RestoreTarget{{ID: " "}}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestBuildRestoreScriptReattachesContainers(t *testing.T) {
+	script, err := BuildRestoreScript([]RestoreTarget{
+		{ID: "T1", List: "Someday", ProjectID: "P1"},
+		{ID: "T2", List: "Inbox"},
+		{ID: "T3", AreaID: "A1", StartDate: "2026-03-05"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"set restoredToDo to to do id \"T1\"",
+		"move restoredToDo to list \"Someday\"",
+		"set project of restoredToDo to project id \"P1\"",
+		"move restoredToDo to list \"Inbox\"",
+		"set area of restoredToDo to area id \"A1\"",
+		"set month of restoreDate to 3",
+		"schedule restoredToDo for restoreDate",
+	} {
+		if !contains(script, want) {
+			t.Fatalf("expected %q in script, got %q", want, script)
+		}
+	}
+	if contains(script, "delete") {
+		t.Fatalf("restore must not delete, got %q", script)
+	}
+}
+
+func TestBuildRestoreScriptRejectsBadDate(t *testing.T) {
+	if _, err := BuildRestoreScript([]RestoreTarget{{ID: "T1", StartDate: "soon"}}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -4,28 +4,29 @@ import "strings"
 
 // UpdateOptions defines options for update.
 type UpdateOptions struct {
-	AuthToken             string
-	ID                    string
-	Notes                 string
-	PrependNotes          string
-	AppendNotes           string
-	When                  string
-	Later                 bool
-	Deadline              string
-	Tags                  string
-	AddTags               string
-	Completed             bool
-	Canceled              bool
-	Reveal                bool
-	Duplicate             bool
-	CompletionDate        string
-	CreationDate          string
-	Heading               string
-	List                  string
-	ListID                string
-	ChecklistItems        []string
-	PrependChecklistItems []string
-	AppendChecklistItems  []string
+	AuthToken             string   `json:"-"`
+	ID                    string   `json:"id,omitempty"`
+	Notes                 string   `json:"notes,omitempty"`
+	PrependNotes          string   `json:"prepend_notes,omitempty"`
+	AppendNotes           string   `json:"append_notes,omitempty"`
+	When                  string   `json:"when,omitempty"`
+	Later                 bool     `json:"later,omitempty"`
+	Deadline              string   `json:"deadline,omitempty"`
+	Tags                  string   `json:"tags,omitempty"`
+	AddTags               string   `json:"add_tags,omitempty"`
+	Completed             bool     `json:"completed,omitempty"`
+	Canceled              bool     `json:"canceled,omitempty"`
+	Reveal                bool     `json:"reveal,omitempty"`
+	Duplicate             bool     `json:"duplicate,omitempty"`
+	CompletionDate        string   `json:"completion_date,omitempty"`
+	CreationDate          string   `json:"creation_date,omitempty"`
+	Heading               string   `json:"heading,omitempty"`
+	HeadingID             string   `json:"heading_id,omitempty"`
+	List                  string   `json:"list,omitempty"`
+	ListID                string   `json:"list_id,omitempty"`
+	ChecklistItems        []string `json:"checklist_items,omitempty"`
+	PrependChecklistItems []string `json:"prepend_checklist_items,omitempty"`
+	AppendChecklistItems  []string `json:"append_checklist_items,omitempty"`
 }
 
 // BuildUpdateURL builds a Things URL for the update command.
@@ -89,7 +90,9 @@ func BuildUpdateURL(opts UpdateOptions, rawInput string) (string, error) {
 		params = append(params, "append-notes="+URLEncode(opts.AppendNotes))
 	}
 
-	if opts.Heading != "" {
+	if opts.HeadingID != "" {
+		params = append(params, "heading-id="+URLEncode(opts.HeadingID))
+	} else if opts.Heading != "" {
 		params = append(params, "heading="+URLEncode(opts.Heading))
 	}
 
@@ -51,6 +51,19 @@ func TestBuildUpdateURLListIDPrecedence(t *testing.T) {
 	}
 }
 
+func TestBuildUpdateURLHeadingIDPrecedence(t *testing.T) {
+	url, err := BuildUpdateURL(UpdateOptions{AuthToken: "tok", ID: "id", Heading: "Later", HeadingID: "H1"}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !contains(url, "heading-id=H1") {
+		t.Fatalf("expected heading-id in %q", url)
+	}
+	if contains(url, "heading=Later") {
+		t.Fatalf("did not expect heading in %q", url)
+	}
+}
+
 func TestBuildUpdateURLNotesFromInputOverrideFlag(t *testing.T) {
 	opts := UpdateOptions{AuthToken: "tok", ID: "id", Notes: "FromFlag"}
 	url, err := BuildUpdateURL(opts, "Title\n\nFromInput")